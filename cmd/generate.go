@@ -4,10 +4,9 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/spf13/cobra"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
-	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/database"
 )
 
 func init() {
@@ -19,17 +18,9 @@ var generateCmd = &cobra.Command{
 	Short: "Generates and populates the database with mock data",
 	Run: func(cmd *cobra.Command, args []string) {
 
-		var db *sqlx.DB
-		var err error
-
-		dbconfig := testutil.NewDatabaseConnectionConfig()
-
-		if dokku {
-			db, err = testutil.NewDatabaseConnectionDokku(dbconfig)
-		} else {
-			db, err = testutil.NewDatabaseConnection(dbconfig)
-		}
+		cfg := loadConfig()
 
+		db, err := database.Open(&cfg.Database)
 		if err != nil {
 			fmt.Printf("error creating db connection: %s\n", err)
 			os.Exit(1)
@@ -58,5 +49,11 @@ var generateCmd = &cobra.Command{
 			fmt.Println(err)
 			os.Exit(1)
 		}
+
+		err = db.Close()
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	},
 }
@@ -0,0 +1,195 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/cobra"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/database"
+)
+
+// migrationsDir is where migrate create writes new migrations, which are
+// embedded the next time the binary is built.
+var migrationsDir = filepath.Join("ddl", "migrations")
+
+// newMigrationName matches the names given to migrate create.
+var newMigrationName = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.AddCommand(migrateUpCmd, migrateDownCmd, migrateStatusCmd, migrateCreateCmd)
+
+	migrateCreateCmd.Flags().StringVar(&migrationsDir, "dir", migrationsDir, "the directory of the migration files")
+}
+
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrates the database schema",
+}
+
+var migrateUpCmd = &cobra.Command{
+	Use:   "up [version]",
+	Short: "Applies every pending migration, or up to the given version",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		db, migrator := openMigrator()
+		defer db.Close()
+
+		target := migrator.Latest()
+		if len(args) > 0 {
+			target = parseVersion(args[0])
+		}
+
+		applied, err := migrator.Up(context.Background(), target)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(applied) <= 0 {
+			fmt.Println("no pending migrations")
+		}
+	},
+}
+
+var migrateDownCmd = &cobra.Command{
+	Use:   "down [version]",
+	Short: "Reverts the last applied migration, or every one after the given version",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		db, migrator := openMigrator()
+		defer db.Close()
+
+		var target int
+		if len(args) > 0 {
+			target = parseVersion(args[0])
+		} else {
+			var err error
+			target, err = migrator.Previous(context.Background())
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+		}
+
+		reverted, err := migrator.Down(context.Background(), target)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		if len(reverted) <= 0 {
+			fmt.Println("no migrations to revert")
+		}
+	},
+}
+
+var migrateStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Lists the migrations and when they were applied",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+
+		db, migrator := openMigrator()
+		defer db.Close()
+
+		statuses, err := migrator.Status(context.Background())
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		for _, status := range statuses {
+			appliedOn := "pending"
+			if status.AppliedOn.Valid {
+				appliedOn = status.AppliedOn.Time.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-32s %s\n", status.Version, status.Name, appliedOn)
+		}
+	},
+}
+
+var migrateCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Creates empty up and down files for a new migration",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+
+		name := args[0]
+		if !newMigrationName.MatchString(name) {
+			fmt.Println("the name must only have lower case letters, digits and underscores")
+			os.Exit(1)
+		}
+
+		migrations, err := database.LoadMigrations(os.DirFS(migrationsDir), ".")
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+
+		version := 1
+		if len(migrations) > 0 {
+			version = migrations[len(migrations)-1].Version + 1
+		}
+
+		for _, direction := range []string{"up", "down"} {
+			path := filepath.Join(migrationsDir, fmt.Sprintf("%04d_%s.%s.sql", version, name, direction))
+			content := fmt.Sprintf("-- %04d_%s (%s)\n", version, name, direction)
+
+			err = os.WriteFile(path, []byte(content), 0644)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			fmt.Printf("created %s\n", path)
+		}
+	},
+}
+
+// openMigrator opens the database from the configuration, and returns it with
+// a migrator for its schema. It exits on error.
+func openMigrator() (*sqlx.DB, *database.Migrator) {
+	cfg := loadConfig()
+
+	db, err := database.Open(&cfg.Database)
+	if err != nil {
+		fmt.Printf("error creating db connection: %s\n", err)
+		os.Exit(1)
+	}
+
+	migrator, err := database.NewEmbeddedMigrator(db, cfg.Database.Schema)
+	if err != nil {
+		db.Close()
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	return db, migrator
+}
+
+// parseVersion parses a migration version given as an argument. It exits on
+// error.
+func parseVersion(arg string) int {
+	version, err := strconv.Atoi(arg)
+	if err != nil || version < 0 {
+		fmt.Printf("invalid version '%s'\n", arg)
+		os.Exit(1)
+	}
+	return version
+}
@@ -1,22 +1,31 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/config"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
 )
 
 var (
-	testing = false
-	dokku   = false
+	testing    = false
+	dokku      = false
+	configPath = ""
 )
 
 func init() {
-	viper.AutomaticEnv()
+	config.SetDefaults(viper.GetViper())
+	config.BindEnv(viper.GetViper())
+
 	rootCmd.PersistentFlags().BoolVarP(&testing, "testing", "t", false, "set testing flag")
-	rootCmd.PersistentFlags().BoolVar(&dokku, "dokku", false, "set dokku flag")
+	rootCmd.PersistentFlags().StringVarP(&configPath, "config", "c", os.Getenv("CONFIG_FILE"), "the configuration file to use, if any (env CONFIG_FILE)")
+
+	rootCmd.PersistentFlags().BoolVar(&dokku, "dokku", false, "set dokku flag (requires DATABASE_URL)")
 }
 
 var rootCmd = &cobra.Command{
@@ -35,3 +44,51 @@ func Execute() {
 		os.Exit(1)
 	}
 }
+
+// loadConfig loads and validates the configuration, and prints it with the
+// secrets redacted. In testing mode the local test database is used unless
+// another one is configured. It exits on error.
+func loadConfig() *config.Config {
+	cfg, err := config.Load(viper.GetViper(), configPath)
+	if err != nil {
+		fmt.Printf("error loading configuration: %s\n", err)
+		os.Exit(1)
+	}
+
+	if testing && len(cfg.Database.DSN) <= 0 && len(cfg.Database.Host) <= 0 {
+		useTestDatabase(&cfg.Database)
+	}
+
+	err = cfg.Validate()
+	if err != nil {
+		fmt.Printf("invalid configuration: %s\n", err)
+		os.Exit(1)
+	}
+
+	// in dokku, the database must come from the linked app
+	if dokku && len(cfg.Database.DSN) <= 0 {
+		fmt.Println("invalid configuration: DATABASE_URL not found. Make sure the app is linked (dokku postgres:link)")
+		os.Exit(1)
+	}
+
+	effective, err := json.MarshalIndent(cfg.Redacted(), "", "    ")
+	if err != nil {
+		fmt.Printf("error printing configuration: %s\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("effective configuration:\n%s\n", effective)
+	return cfg
+}
+
+// useTestDatabase sets the given database configuration to the local test
+// database, see testutil.NewDatabaseConnectionConfig.
+func useTestDatabase(database *config.DatabaseConfig) {
+	test := testutil.NewDatabaseConnectionConfig()
+	database.Host = test.Host
+	database.Port = test.Port
+	database.Name = test.Database
+	database.User = test.User
+	database.Password = test.Password
+	database.Schema = test.Schema
+}
@@ -3,18 +3,35 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/server"
 )
 
 func init() {
 	rootCmd.AddCommand(serverCmd)
 
-	serverCmd.Flags().IntP("port", "p", 5000, "the port to use when starting the HTTP server")
-	viper.BindPFlag("port", serverCmd.Flags().Lookup("port"))
+	serverCmd.Flags().IntP("port", "p", 5000, "the port to use when starting the HTTP server (env PORT)")
+	viper.BindPFlag("server.port", serverCmd.Flags().Lookup("port"))
+
+	serverCmd.Flags().String("session-secret", "", "the secret used for signing session keys (env SESSION_SECRET)")
+	viper.BindPFlag("auth.session_secret", serverCmd.Flags().Lookup("session-secret"))
+
+	serverCmd.Flags().Duration("session-duration", time.Hour*24, "the duration session keys are valid for")
+	viper.BindPFlag("auth.session_duration", serverCmd.Flags().Lookup("session-duration"))
+
+	serverCmd.Flags().String("outbox", "outbox.jsonl", "the file where messages for users (e.g. password resets) are written to")
+	viper.BindPFlag("server.outbox", serverCmd.Flags().Lookup("outbox"))
+
+	serverCmd.Flags().Bool("migrate", false, "apply pending migrations before starting (env DATABASE_MIGRATE)")
+	viper.BindPFlag("database.migrate", serverCmd.Flags().Lookup("migrate"))
+
+	serverCmd.Flags().String("log-level", "info", "the log level: debug, info, warn or error")
+	viper.BindPFlag("log.level", serverCmd.Flags().Lookup("log-level"))
 }
 
 var serverCmd = &cobra.Command{
@@ -22,10 +39,20 @@ var serverCmd = &cobra.Command{
 	Short: "Starts the HTTP server",
 	Run: func(cmd *cobra.Command, args []string) {
 
-		port := viper.GetInt("port")
-		bindAddress := fmt.Sprintf(":%d", port)
+		cfg := loadConfig()
+
+		if len(cfg.Auth.SessionSecret) <= 0 {
+			fmt.Println("warning: no session secret given, keys will not be valid after a restart")
+
+			var err error
+			cfg.Auth.SessionSecret, err = crypto.NewToken(32)
+			if err != nil {
+				fmt.Printf("error generating session secret: %s\n", err)
+				os.Exit(1)
+			}
+		}
 
-		err := server.Start(bindAddress, testing, dokku)
+		err := server.Start(cfg, testing)
 		if err != nil {
 			fmt.Printf("error starting server: %s\n", err)
 			os.Exit(1)
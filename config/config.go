@@ -0,0 +1,344 @@
+// Package config loads the configuration of the backend from a file,
+// environment variables and command line flags, in increasing order of
+// precedence.
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// redacted replaces secrets when printing the configuration.
+const redacted = "[redacted]"
+
+// dateLayout is the layout of dates in the configuration.
+const dateLayout = "2006-01-02"
+
+// minSecretLength is the minimum length of the session secret, if given.
+const minSecretLength = 16
+
+// logLevels are the valid log levels.
+var logLevels = map[string]bool{
+	"debug": true,
+	"info":  true,
+	"warn":  true,
+	"error": true,
+}
+
+// Config holds the configuration of the backend.
+type Config struct {
+	Server   ServerConfig   `mapstructure:"server" json:"server"`
+	Database DatabaseConfig `mapstructure:"database" json:"database"`
+	Auth     AuthConfig     `mapstructure:"auth" json:"auth"`
+	CORS     CORSConfig     `mapstructure:"cors" json:"cors"`
+	Log      LogConfig      `mapstructure:"log" json:"log"`
+	API      APIConfig      `mapstructure:"api" json:"api"`
+}
+
+// ServerConfig holds the configuration of the HTTP server. TrustedProxies are
+// the IP ranges (in CIDR notation) of the proxies in front of the server, whose
+// X-Forwarded-For headers are trusted for the client IP; without any, the
+// client IP is the address of the connection. Metrics are only served to
+// callers sending MetricsToken as a bearer token, and not at all without one.
+type ServerConfig struct {
+	Port            int           `mapstructure:"port" json:"port"`
+	ReadTimeout     time.Duration `mapstructure:"read_timeout" json:"readTimeout"`
+	WriteTimeout    time.Duration `mapstructure:"write_timeout" json:"writeTimeout"`
+	UsecaseTimeout  time.Duration `mapstructure:"usecase_timeout" json:"usecaseTimeout"`
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout" json:"shutdownTimeout"`
+	OutboxPath      string        `mapstructure:"outbox" json:"outbox"`
+	TrustedProxies  []string      `mapstructure:"trusted_proxies" json:"trustedProxies"`
+	MetricsToken    string        `mapstructure:"metrics_token" json:"metricsToken"`
+}
+
+// DatabaseConfig holds the configuration of the database connection. If DSN
+// is set (e.g. from DATABASE_URL), it's used instead of the other connection
+// parameters. If Migrate is set, the server applies pending migrations on
+// startup.
+type DatabaseConfig struct {
+	DSN             string        `mapstructure:"dsn" json:"dsn"`
+	Host            string        `mapstructure:"host" json:"host"`
+	Port            int           `mapstructure:"port" json:"port"`
+	Name            string        `mapstructure:"name" json:"name"`
+	User            string        `mapstructure:"user" json:"user"`
+	Password        string        `mapstructure:"password" json:"password"`
+	SSLMode         string        `mapstructure:"sslmode" json:"sslmode"`
+	Schema          string        `mapstructure:"schema" json:"schema"`
+	MaxOpenConns    int           `mapstructure:"max_open_conns" json:"maxOpenConns"`
+	MaxIdleConns    int           `mapstructure:"max_idle_conns" json:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime" json:"connMaxLifetime"`
+	Migrate         bool          `mapstructure:"migrate" json:"migrate"`
+}
+
+// AuthConfig holds the configuration of keys and tokens.
+type AuthConfig struct {
+	SessionSecret   string        `mapstructure:"session_secret" json:"sessionSecret"`
+	SessionDuration time.Duration `mapstructure:"session_duration" json:"sessionDuration"`
+	ResetDuration   time.Duration `mapstructure:"reset_duration" json:"resetDuration"`
+}
+
+// CORSConfig holds the CORS allow-lists.
+type CORSConfig struct {
+	AllowOrigins []string `mapstructure:"allow_origins" json:"allowOrigins"`
+	AllowHeaders []string `mapstructure:"allow_headers" json:"allowHeaders"`
+}
+
+// LogConfig holds the configuration of logging.
+type LogConfig struct {
+	Level string `mapstructure:"level" json:"level"`
+}
+
+// APIConfig holds the deprecation schedule of version 1 of the API, as dates
+// (e.g. 2026-10-18). An empty date means not deprecated, or no sunset planned.
+type APIConfig struct {
+	V1Deprecation string `mapstructure:"v1_deprecation" json:"v1Deprecation"`
+	V1Sunset      string `mapstructure:"v1_sunset" json:"v1Sunset"`
+}
+
+// ParseDate parses a date of the API configuration. An empty date is the zero
+// time.
+func ParseDate(date string) (time.Time, error) {
+	if len(date) <= 0 {
+		return time.Time{}, nil
+	}
+	return time.Parse(dateLayout, date)
+}
+
+// SetDefaults sets the default values in the given viper instance. Every key
+// must have a default, otherwise it can't be set from the environment.
+func SetDefaults(v *viper.Viper) {
+	v.SetDefault("server.port", 5000)
+	v.SetDefault("server.read_timeout", time.Second*10)
+	v.SetDefault("server.write_timeout", time.Second*30)
+	v.SetDefault("server.usecase_timeout", time.Second*2)
+	v.SetDefault("server.shutdown_timeout", time.Second*15)
+	v.SetDefault("server.outbox", "outbox.jsonl")
+	v.SetDefault("server.trusted_proxies", []string{})
+	v.SetDefault("server.metrics_token", "")
+
+	v.SetDefault("database.dsn", "")
+	v.SetDefault("database.host", "")
+	v.SetDefault("database.port", 5432)
+	v.SetDefault("database.name", "")
+	v.SetDefault("database.user", "")
+	v.SetDefault("database.password", "")
+	v.SetDefault("database.sslmode", "disable")
+	v.SetDefault("database.schema", "theme_park")
+	v.SetDefault("database.max_open_conns", 20)
+	v.SetDefault("database.max_idle_conns", 5)
+	v.SetDefault("database.conn_max_lifetime", time.Minute*30)
+	v.SetDefault("database.migrate", false)
+
+	v.SetDefault("auth.session_secret", "")
+	v.SetDefault("auth.session_duration", time.Hour*24)
+	v.SetDefault("auth.reset_duration", time.Hour)
+
+	v.SetDefault("cors.allow_origins", []string{"*"})
+	v.SetDefault("cors.allow_headers", []string{"Origin", "X-Requested-With", "Content-Type", "Accept", "Authorization", "X-Device-Key"})
+
+	v.SetDefault("log.level", "info")
+
+	v.SetDefault("api.v1_deprecation", "")
+	v.SetDefault("api.v1_sunset", "")
+}
+
+// BindEnv makes every key settable from the environment, using the key in
+// upper case with dots replaced by underscores (e.g. DATABASE_HOST). Some
+// keys also keep their well known names (PORT, DATABASE_URL,
+// SESSION_SECRET).
+func BindEnv(v *viper.Viper) {
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	v.BindEnv("server.port", "PORT")
+	v.BindEnv("database.dsn", "DATABASE_URL")
+	v.BindEnv("auth.session_secret", "SESSION_SECRET")
+}
+
+// Load reads the configuration file at the given path, if any, and returns the
+// configuration from the given viper instance. Defaults and environment
+// variables must be set up already (see SetDefaults and BindEnv).
+func Load(v *viper.Viper, path string) (*Config, error) {
+	if len(path) > 0 {
+		v.SetConfigFile(path)
+		err := v.ReadInConfig()
+		if err != nil {
+			return nil, fmt.Errorf("loadConfig: %s", err)
+		}
+	}
+
+	config := &Config{}
+	err := v.Unmarshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("loadConfig: %s", err)
+	}
+
+	return config, nil
+}
+
+// Validate returns an error describing the first invalid value, if any. The
+// database has no defaults, so it must always be configured.
+func (c *Config) Validate() error {
+	if c.Server.Port <= 0 || c.Server.Port > 65535 {
+		return fmt.Errorf("validateConfig: server.port must be in the range [1, 65535]")
+	}
+
+	if c.Server.ReadTimeout <= 0 || c.Server.WriteTimeout <= 0 || c.Server.UsecaseTimeout <= 0 || c.Server.ShutdownTimeout <= 0 {
+		return fmt.Errorf("validateConfig: server timeouts must be positive")
+	}
+
+	if len(c.Server.OutboxPath) <= 0 {
+		return fmt.Errorf("validateConfig: server.outbox must be non-empty")
+	}
+
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return fmt.Errorf("validateConfig: server.trusted_proxies must be IP ranges like 10.0.0.0/8")
+		}
+	}
+
+	if len(c.Server.MetricsToken) > 0 && len(c.Server.MetricsToken) < minSecretLength {
+		return fmt.Errorf("validateConfig: server.metrics_token must be at least %d characters long", minSecretLength)
+	}
+
+	err := c.Database.validate()
+	if err != nil {
+		return err
+	}
+
+	if len(c.Auth.SessionSecret) > 0 && len(c.Auth.SessionSecret) < minSecretLength {
+		return fmt.Errorf("validateConfig: auth.session_secret must be at least %d characters long", minSecretLength)
+	}
+
+	if c.Auth.SessionDuration <= 0 || c.Auth.ResetDuration <= 0 {
+		return fmt.Errorf("validateConfig: auth durations must be positive")
+	}
+
+	if len(c.CORS.AllowOrigins) <= 0 {
+		return fmt.Errorf("validateConfig: cors.allow_origins must be non-empty")
+	}
+
+	if !logLevels[c.Log.Level] {
+		return fmt.Errorf("validateConfig: log.level must be one of debug, info, warn or error")
+	}
+
+	return c.API.validate()
+}
+
+func (a *APIConfig) validate() error {
+	deprecation, err := ParseDate(a.V1Deprecation)
+	if err != nil {
+		return fmt.Errorf("validateConfig: api.v1_deprecation must be a date like %s", dateLayout)
+	}
+
+	sunset, err := ParseDate(a.V1Sunset)
+	if err != nil {
+		return fmt.Errorf("validateConfig: api.v1_sunset must be a date like %s", dateLayout)
+	}
+
+	if !sunset.IsZero() && (deprecation.IsZero() || sunset.Before(deprecation)) {
+		return fmt.Errorf("validateConfig: api.v1_sunset must be after api.v1_deprecation")
+	}
+
+	return nil
+}
+
+func (d *DatabaseConfig) validate() error {
+	if len(d.DSN) > 0 {
+		_, err := url.Parse(d.DSN)
+		if err != nil {
+			return fmt.Errorf("validateConfig: database.dsn is not a valid URL: %s", err)
+		}
+	} else {
+		if len(d.Host) <= 0 || len(d.Name) <= 0 || len(d.User) <= 0 {
+			return fmt.Errorf("validateConfig: database.dsn, or database.host, database.name and database.user, must be set")
+		}
+
+		if d.Port <= 0 || d.Port > 65535 {
+			return fmt.Errorf("validateConfig: database.port must be in the range [1, 65535]")
+		}
+	}
+
+	if len(d.Schema) <= 0 {
+		return fmt.Errorf("validateConfig: database.schema must be non-empty")
+	}
+
+	if d.MaxOpenConns < 0 || d.MaxIdleConns < 0 || d.ConnMaxLifetime < 0 {
+		return fmt.Errorf("validateConfig: database pool sizes and lifetime must not be negative")
+	}
+
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		return fmt.Errorf("validateConfig: database.max_idle_conns must not be greater than database.max_open_conns")
+	}
+
+	return nil
+}
+
+// DataSourceName returns the data source name used for connecting to the
+// database, always using the configured schema.
+func (d *DatabaseConfig) DataSourceName() string {
+	if len(d.DSN) > 0 {
+		separator := "?"
+		if strings.Contains(d.DSN, "?") {
+			separator = "&"
+		}
+		return d.DSN + separator + "search_path=" + url.QueryEscape(d.Schema)
+	}
+
+	return fmt.Sprintf(
+		"host=%s port=%d dbname=%s user=%s password=%s sslmode=%s search_path=%s",
+		d.Host, d.Port, d.Name, d.User, d.Password, d.SSLMode, d.Schema,
+	)
+}
+
+// Redacted returns a copy of the configuration with every secret replaced,
+// meant for printing it.
+func (c *Config) Redacted() *Config {
+	redactedConfig := *c
+
+	if len(redactedConfig.Database.Password) > 0 {
+		redactedConfig.Database.Password = redacted
+	}
+
+	if len(redactedConfig.Database.DSN) > 0 {
+		redactedConfig.Database.DSN = redactURL(redactedConfig.Database.DSN)
+	}
+
+	if len(redactedConfig.Server.MetricsToken) > 0 {
+		redactedConfig.Server.MetricsToken = redacted
+	}
+
+	if len(redactedConfig.Auth.SessionSecret) > 0 {
+		redactedConfig.Auth.SessionSecret = redacted
+	}
+
+	return &redactedConfig
+}
+
+// redactURL replaces the password in the given URL. If the URL can't be
+// parsed, all of it is replaced.
+func redactURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return redacted
+	}
+
+	if parsed.User == nil {
+		return rawURL
+	}
+
+	if _, ok := parsed.User.Password(); !ok {
+		return rawURL
+	}
+
+	// set the password by hand, url.UserPassword would escape it
+	parsed.User = url.User(parsed.User.Username())
+	user := parsed.User.String()
+	return strings.Replace(parsed.String(), "//"+user+"@", "//"+user+":"+redacted+"@", 1)
+}
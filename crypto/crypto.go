@@ -1,6 +1,12 @@
 package crypto
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -27,3 +33,36 @@ func CompareHashAndPassword(hashedPassword, plainPassword string) bool {
 	}
 	return true
 }
+
+// NewSecret returns a random secret of the given size in bytes.
+func NewSecret(size int) ([]byte, error) {
+	secret := make([]byte, size)
+	_, err := rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// NewToken returns a random, URL safe token made from the given number of
+// random bytes. Tokens are meant to be sent to users, store HashToken instead.
+func NewToken(size int) (string, error) {
+	secret, err := NewSecret(size)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// HashToken returns the SHA-256 hash of the given token, hex encoded. Tokens
+// are random, so there is no need for a slow hash like for passwords.
+func HashToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// CompareHashAndToken returns true if the given hash is the hash of the given
+// token. The comparison takes constant time.
+func CompareHashAndToken(hash, token string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(HashToken(token))) == 1
+}
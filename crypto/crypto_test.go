@@ -43,3 +43,32 @@ func TestCompareHashAndPasswordSucceeds(t *testing.T) {
 		assert.False(t, crypto.CompareHashAndPassword(hash, "some-other-not-equal-password"))
 	}
 }
+
+func TestNewTokenSucceeds(t *testing.T) {
+	token0, err := crypto.NewToken(32)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	token1, err := crypto.NewToken(32)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.NotEmpty(t, token0)
+	assert.NotEqual(t, token0, token1)
+}
+
+func TestHashTokenSucceeds(t *testing.T) {
+	hash0 := crypto.HashToken("token0")
+	assert.Equal(t, hash0, crypto.HashToken("token0"))
+	assert.NotEqual(t, hash0, crypto.HashToken("token1"))
+	assert.Len(t, hash0, 64)
+}
+
+func TestCompareHashAndTokenSucceeds(t *testing.T) {
+	hash := crypto.HashToken("token0")
+	assert.True(t, crypto.CompareHashAndToken(hash, "token0"))
+	assert.False(t, crypto.CompareHashAndToken(hash, "token1"))
+	assert.False(t, crypto.CompareHashAndToken("", "token0"))
+}
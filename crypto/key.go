@@ -1,37 +1,123 @@
 package crypto
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"strings"
+	"time"
 )
 
-// Key holds an authorization key information.
+var (
+	// ErrKeyMalformed is returned when a key cannot be decoded.
+	ErrKeyMalformed = errors.New("key is malformed")
+
+	// ErrKeySignature is returned when the signature of a key does not match.
+	ErrKeySignature = errors.New("key signature is not valid")
+
+	// ErrKeyExpired is returned when a key is past its expiration time.
+	ErrKeyExpired = errors.New("key has expired")
+)
+
+// Key holds an authorization key information. Keys are signed by a Signer
+// and map one-to-one to a server-side session.
 type Key struct {
-	Login        string `json:"login"`
-	PasswordHash string `json:"passwordHash"`
+	ID        string    `json:"id"`
+	Login     string    `json:"login"`
+	Stamp     string    `json:"stamp"`
+	IssuedAt  time.Time `json:"iat"`
+	ExpiresAt time.Time `json:"exp"`
 }
 
 // EmptyKey struct holds an empty key.
 var EmptyKey = Key{}
 
-// DecodeKey creates a new Key from the given encoded key.
-func DecodeKey(encoded string) (Key, error) {
-	keyBytes, err := base64.StdEncoding.DecodeString(encoded)
+// NewKey returns a new Key that is valid from issuedAt and for the given
+// duration. Times are truncated to the second as that is the precision we
+// care about.
+func NewKey(ID, login, stamp string, issuedAt time.Time, duration time.Duration) Key {
+	issuedAt = issuedAt.UTC().Truncate(time.Second)
+	return Key{
+		ID:        ID,
+		Login:     login,
+		Stamp:     stamp,
+		IssuedAt:  issuedAt,
+		ExpiresAt: issuedAt.Add(duration),
+	}
+}
+
+// Expired returns true if the key is expired at the given time.
+func (k *Key) Expired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
+
+// Signer signs and verifies keys using HMAC-SHA256.
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner returns a new Signer instance using the given secret.
+func NewSigner(secret []byte) *Signer {
+	return &Signer{secret}
+}
+
+// Encode encodes and signs the key. The result has the form
+// "<payload>.<signature>".
+func (s *Signer) Encode(key Key) string {
+	keyBytes, _ := json.Marshal(key)
+	payload := base64.RawURLEncoding.EncodeToString(keyBytes)
+	signature := base64.RawURLEncoding.EncodeToString(s.sign([]byte(payload)))
+	return payload + "." + signature
+}
+
+// Decode creates a new Key from the given encoded key, verifying its
+// signature and expiration time.
+func (s *Signer) Decode(encoded string) (Key, error) {
+	parts := strings.Split(encoded, ".")
+	if len(parts) != 2 {
+		return EmptyKey, ErrKeyMalformed
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
 	if err != nil {
-		return EmptyKey, err
+		return EmptyKey, ErrKeyMalformed
+	}
+
+	if !hmac.Equal(signature, s.sign([]byte(parts[0]))) {
+		return EmptyKey, ErrKeySignature
+	}
+
+	keyBytes, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return EmptyKey, ErrKeyMalformed
 	}
 
 	key := Key{}
 	err = json.Unmarshal(keyBytes, &key)
 	if err != nil {
-		return EmptyKey, err
+		return EmptyKey, ErrKeyMalformed
+	}
+
+	if key.Expired(time.Now()) {
+		return EmptyKey, ErrKeyExpired
 	}
 
 	return key, nil
 }
 
-// Encode encodes the key into a base64 string.
-func (k *Key) Encode() string {
-	keyBytes, _ := json.Marshal(k)
-	return base64.StdEncoding.EncodeToString(keyBytes)
+// Stamp returns a fingerprint of the given password hash. Keys carry the
+// stamp of the password they were issued for, so that changing the password
+// invalidates them without putting the hash on the wire.
+func (s *Signer) Stamp(passwordHash string) string {
+	stamp := s.sign([]byte("stamp:" + passwordHash))
+	return hex.EncodeToString(stamp[:16])
+}
+
+func (s *Signer) sign(data []byte) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write(data)
+	return mac.Sum(nil)
 }
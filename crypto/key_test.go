@@ -1,49 +1,44 @@
 package crypto_test
 
 import (
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
 )
 
-func TestKeyEncodeSucceeds(t *testing.T) {
+var keyTests = []struct {
+	ID    string
+	login string
+	stamp string
+}{
+	{"", "", ""},
+	{"session0", "login0", "stamp0"},
+	{"session1", "login1", "stamp1  "},
+	{"session2", "login2", "stamp2 --"},
+}
 
-	tests := []struct {
-		login        string
-		passwordHash string
-	}{
-		{"", ""},
-		{"login0", "hash0"},
-		{"login1", "hash1  "},
-		{"login2", "hash2 --"},
-	}
+func TestKeyEncodeSucceeds(t *testing.T) {
+	signer := crypto.NewSigner([]byte("secret"))
 
-	for _, tt := range tests {
-		key := crypto.Key{tt.login, tt.passwordHash}
-		encoded := key.Encode()
+	for _, tt := range keyTests {
+		key := crypto.NewKey(tt.ID, tt.login, tt.stamp, time.Now(), time.Hour)
+		encoded := signer.Encode(key)
 		assert.NotEmpty(t, encoded)
 	}
 }
 
 func TestKeyDecodeSucceeds(t *testing.T) {
+	signer := crypto.NewSigner([]byte("secret"))
 
-	tests := []struct {
-		login        string
-		passwordHash string
-	}{
-		{"", ""},
-		{"login0", "hash0"},
-		{"login1", "hash1  "},
-		{"login2", "hash2 --"},
-	}
+	for _, tt := range keyTests {
+		key := crypto.NewKey(tt.ID, tt.login, tt.stamp, time.Now(), time.Hour)
 
-	for _, tt := range tests {
-		key := crypto.Key{tt.login, tt.passwordHash}
-
-		encoded := key.Encode()
-		decodedKey, err := crypto.DecodeKey(encoded)
+		encoded := signer.Encode(key)
+		decodedKey, err := signer.Decode(encoded)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -51,3 +46,50 @@ func TestKeyDecodeSucceeds(t *testing.T) {
 		assert.Equal(t, key, decodedKey)
 	}
 }
+
+func TestKeyDecodeExpiredFails(t *testing.T) {
+	signer := crypto.NewSigner([]byte("secret"))
+
+	key := crypto.NewKey("session0", "login0", "stamp0", time.Now().Add(-time.Hour*2), time.Hour)
+	_, err := signer.Decode(signer.Encode(key))
+	assert.Equal(t, crypto.ErrKeyExpired, err)
+}
+
+func TestKeyDecodeOtherSecretFails(t *testing.T) {
+	signer := crypto.NewSigner([]byte("secret"))
+	otherSigner := crypto.NewSigner([]byte("other-secret"))
+
+	key := crypto.NewKey("session0", "login0", "stamp0", time.Now(), time.Hour)
+	_, err := otherSigner.Decode(signer.Encode(key))
+	assert.Equal(t, crypto.ErrKeySignature, err)
+}
+
+func TestKeyDecodeTamperedFails(t *testing.T) {
+	signer := crypto.NewSigner([]byte("secret"))
+
+	key := crypto.NewKey("session0", "login0", "stamp0", time.Now(), time.Hour)
+	otherKey := crypto.NewKey("session0", "login1", "stamp0", time.Now(), time.Hour)
+
+	encoded := signer.Encode(key)
+	otherEncoded := signer.Encode(otherKey)
+
+	// payload from one key, signature from another
+	tampered := strings.Split(otherEncoded, ".")[0] + "." + strings.Split(encoded, ".")[1]
+	_, err := signer.Decode(tampered)
+	assert.Equal(t, crypto.ErrKeySignature, err)
+
+	tests := []string{"", "abc", "a.b.c", "!!!.???"}
+	for _, tt := range tests {
+		_, err := signer.Decode(tt)
+		assert.NotNil(t, err)
+	}
+}
+
+func TestKeyStampChangesWithPassword(t *testing.T) {
+	signer := crypto.NewSigner([]byte("secret"))
+
+	stamp0 := signer.Stamp("hash0")
+	assert.Equal(t, stamp0, signer.Stamp("hash0"))
+	assert.NotEqual(t, stamp0, signer.Stamp("hash1"))
+	assert.NotContains(t, stamp0, "hash0")
+}
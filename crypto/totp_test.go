@@ -0,0 +1,103 @@
+package crypto_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
+)
+
+// rfcSecret is the SHA1 secret from RFC 6238 ("12345678901234567890"), base32
+// encoded.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCodeSucceeds(t *testing.T) {
+
+	// RFC 6238 appendix B, truncated to 6 digits
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+
+	for _, tt := range tests {
+		code, err := crypto.TOTPCode(rfcSecret, crypto.TOTPStep(time.Unix(tt.unix, 0)))
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, tt.code, code)
+	}
+}
+
+func TestTOTPCodeFails(t *testing.T) {
+	_, err := crypto.TOTPCode("not base32!", 1)
+	assert.NotNil(t, err)
+}
+
+func TestVerifyTOTPSucceeds(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	step, ok := crypto.VerifyTOTP(rfcSecret, "081804", now, 1)
+	assert.True(t, ok)
+	assert.Equal(t, crypto.TOTPStep(now), step)
+
+	// previous step is allowed with a skew of 1
+	step, ok = crypto.VerifyTOTP(rfcSecret, "081804", now.Add(time.Second*30), 1)
+	assert.True(t, ok)
+	assert.Equal(t, crypto.TOTPStep(now), step)
+}
+
+func TestVerifyTOTPFails(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+
+	tests := []struct {
+		code string
+		t    time.Time
+	}{
+		{"000000", now},
+		{"81804", now},
+		{"081804", now.Add(time.Second * 90)},
+	}
+
+	for _, tt := range tests {
+		_, ok := crypto.VerifyTOTP(rfcSecret, tt.code, tt.t, 1)
+		assert.False(t, ok)
+	}
+}
+
+func TestNewTOTPSecretSucceeds(t *testing.T) {
+	secret, err := crypto.NewTOTPSecret()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	_, err = crypto.TOTPCode(secret, 1)
+	assert.Nil(t, err)
+}
+
+func TestTOTPURISucceeds(t *testing.T) {
+	uri := crypto.TOTPURI(rfcSecret, "Theme Park", "user@email.com")
+	assert.True(t, strings.HasPrefix(uri, "otpauth://totp/Theme%20Park:user@email.com?"))
+	assert.Contains(t, uri, "secret="+rfcSecret)
+	assert.Contains(t, uri, "issuer=Theme+Park")
+}
+
+func TestRecoveryCodeSucceeds(t *testing.T) {
+	code, err := crypto.NewRecoveryCode()
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Len(t, code, 11)
+	assert.Equal(t, crypto.NormalizeRecoveryCode(code), crypto.NormalizeRecoveryCode(strings.ToUpper(code)))
+	assert.Len(t, crypto.NormalizeRecoveryCode(code), 10)
+}
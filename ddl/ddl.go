@@ -0,0 +1,15 @@
+// Package ddl holds the migrations of the database schema, embedded in the
+// binary so that it can migrate the database it runs against.
+package ddl
+
+import "embed"
+
+// MigrationsDir is the directory of the migration files, relative to this
+// package.
+const MigrationsDir = "migrations"
+
+// Migrations holds the migration files, named like 0001_schema.up.sql and
+// 0001_schema.down.sql.
+//
+//go:embed migrations/*.sql
+var Migrations embed.FS
@@ -0,0 +1,37 @@
+-- ================================================================
+-- Team 14 - Theme Park
+-- Drops every table of 0001_schema.up.sql, dependents first.
+-- ================================================================
+
+DROP TABLE IF EXISTS device_scopes;
+DROP TABLE IF EXISTS devices;
+DROP TABLE IF EXISTS audit_log;
+DROP TABLE IF EXISTS login_attempts;
+DROP TABLE IF EXISTS recovery_codes;
+DROP TABLE IF EXISTS two_factors;
+DROP TABLE IF EXISTS password_resets;
+DROP TABLE IF EXISTS sessions;
+DROP TABLE IF EXISTS events;
+DROP TABLE IF EXISTS event_types;
+DROP TABLE IF EXISTS employees_on_maintenance;
+DROP TABLE IF EXISTS rides_maintenance;
+DROP TABLE IF EXISTS maintenance_types;
+DROP TABLE IF EXISTS employees_on_rides;
+DROP TABLE IF EXISTS employees;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS tickets_on_rides;
+DROP TABLE IF EXISTS tickets;
+DROP TABLE IF EXISTS reviews;
+DROP TABLE IF EXISTS rides;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS items_in_shop;
+DROP TABLE IF EXISTS items_types;
+DROP TABLE IF EXISTS shops;
+DROP TABLE IF EXISTS shop_types;
+DROP TABLE IF EXISTS pictures_in_collection;
+DROP TABLE IF EXISTS picture_collections;
+DROP TABLE IF EXISTS pictures;
+DROP TABLE IF EXISTS customers;
+DROP TABLE IF EXISTS user_details;
+DROP TABLE IF EXISTS genders;
+DROP TABLE IF EXISTS users;
@@ -18,8 +18,6 @@
 -- --------------------------------
 -- Section that focuses on users, details, and customers.
 
-SET search_path TO theme_park;
-
 CREATE TABLE users (
     id varchar(64) NOT NULL,
     username varchar(32) NOT NULL,
@@ -190,6 +188,7 @@ CREATE TABLE tickets_on_rides (
     ride_id varchar(64) NOT NULL,
     ticket_id varchar(64) NOT NULL,
     scan_datetime timestamp NOT NULL,
+    device_id varchar(64),
     PRIMARY KEY (id),
     FOREIGN KEY (ride_id) REFERENCES rides (id),
     FOREIGN KEY (ticket_id) REFERENCES tickets (id)
@@ -203,6 +202,7 @@ CREATE TABLE roles (
     id varchar(64) NOT NULL,
     role varchar(16) NOT NULL,
     hourly_rate numeric(10, 2) NOT NULL,
+    requires_two_factor boolean NOT NULL DEFAULT false,
     PRIMARY KEY (id),
     UNIQUE (ROLE),
     CHECK (hourly_rate >= 0)
@@ -289,3 +289,115 @@ CREATE TABLE events (
     FOREIGN KEY (employee_id) REFERENCES employees (id),
     FOREIGN KEY (event_type_id) REFERENCES event_types (id)
 );
+
+-- Sessions
+-- --------------------------------
+-- Section that focuses on log in sessions.
+
+CREATE TABLE sessions (
+    id varchar(64) NOT NULL,
+    user_id varchar(64) NOT NULL,
+    user_agent varchar(256),
+    address varchar(64),
+    issued_on timestamp NOT NULL,
+    expires_on timestamp NOT NULL,
+    revoked_on timestamp,
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    CHECK (issued_on <= expires_on)
+);
+
+CREATE TABLE password_resets (
+    id varchar(64) NOT NULL,
+    user_id varchar(64) NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    issued_on timestamp NOT NULL,
+    expires_on timestamp NOT NULL,
+    used_on timestamp,
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    UNIQUE (token_hash),
+    CHECK (issued_on <= expires_on)
+);
+
+-- NOTE: the secret is needed for computing codes, so it can't be hashed.
+-- last_step is the last time step used, so that codes can't be replayed.
+CREATE TABLE two_factors (
+    user_id varchar(64) NOT NULL,
+    secret varchar(64) NOT NULL,
+    created_on timestamp NOT NULL,
+    enabled_on timestamp,
+    last_step bigint NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id),
+    FOREIGN KEY (user_id) REFERENCES users (id)
+);
+
+CREATE TABLE recovery_codes (
+    id varchar(64) NOT NULL,
+    user_id varchar(64) NOT NULL,
+    code_hash varchar(64) NOT NULL,
+    used_on timestamp,
+    PRIMARY KEY (id),
+    FOREIGN KEY (user_id) REFERENCES users (id),
+    UNIQUE (user_id, code_hash)
+);
+
+-- NOTE: attempts are kept even if the user is deleted, so user_id is not a
+-- foreign key.
+CREATE TABLE login_attempts (
+    id varchar(64) NOT NULL,
+    email varchar(64) NOT NULL,
+    user_id varchar(64),
+    user_agent varchar(256),
+    address varchar(64),
+    success boolean NOT NULL,
+    reason varchar(64),
+    attempted_on timestamp NOT NULL,
+    PRIMARY KEY (id)
+);
+
+-- Audit
+-- --------------------------------
+-- Section that focuses on recording who changed what.
+
+-- NOTE: entries are kept even if the actor or entity is deleted, so there are
+-- no foreign keys.
+CREATE TABLE audit_log (
+    id varchar(64) NOT NULL,
+    actor_id varchar(64),
+    action varchar(16) NOT NULL,
+    entity_type varchar(32) NOT NULL,
+    entity_id varchar(64) NOT NULL,
+    before json,
+    after json,
+    recorded_on timestamp NOT NULL,
+    PRIMARY KEY (id)
+);
+
+-- Devices
+-- --------------------------------
+-- Section that focuses on devices (ticket scanners, kiosks) and what they are
+-- allowed to do.
+
+CREATE TABLE devices (
+    id varchar(64) NOT NULL,
+    name varchar(64) NOT NULL,
+    secret_hash varchar(64) NOT NULL,
+    created_on timestamp NOT NULL,
+    rotated_on timestamp,
+    revoked_on timestamp,
+    PRIMARY KEY (id),
+    CHECK (name <> '')
+);
+
+-- NOTE: a NULL ride_id means the operation is allowed on any ride.
+CREATE TABLE device_scopes (
+    device_id varchar(64) NOT NULL,
+    operation varchar(32) NOT NULL,
+    ride_id varchar(64),
+    FOREIGN KEY (device_id) REFERENCES devices (id),
+    FOREIGN KEY (ride_id) REFERENCES rides (id),
+    UNIQUE (device_id, operation, ride_id)
+);
+
+ALTER TABLE tickets_on_rides ADD FOREIGN KEY (device_id) REFERENCES devices (id);
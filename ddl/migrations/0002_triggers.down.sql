@@ -0,0 +1,20 @@
+-- ================================================================
+-- Team 14 - Theme Park
+-- Drops the triggers and functions of 0002_triggers.up.sql.
+-- ================================================================
+
+DROP TRIGGER IF EXISTS ride_bad_reviews_event ON reviews;
+
+DROP TRIGGER IF EXISTS ride_bad_review_posted_event ON reviews;
+
+DROP TRIGGER IF EXISTS ride_maintenance_reopened_event ON rides_maintenance;
+
+DROP TRIGGER IF EXISTS ride_maintenance_closed_event ON rides_maintenance;
+
+DROP TRIGGER IF EXISTS ride_maintenance_started_event ON rides_maintenance;
+
+DROP FUNCTION IF EXISTS emit_bad_reviews_event_when_rating_average_below;
+
+DROP FUNCTION IF EXISTS emit_bad_review_posted_event;
+
+DROP FUNCTION IF EXISTS emit_maintenance_status_event;
@@ -9,8 +9,6 @@
 -- --------------------------------
 -- Functions that execute after a trigger successfully matches.
 
-SET search_path TO theme_park;
-
 -- emit_maintenance_status_event creates a new event with the passed status
 -- for the ride_id in the row.
 CREATE OR REPLACE FUNCTION emit_maintenance_status_event ()
@@ -0,0 +1,18 @@
+-- ================================================================
+-- Team 14 - Theme Park
+-- Drops the indexes of 0003_list_indexes.up.sql.
+-- ================================================================
+
+DROP INDEX IF EXISTS users_registered_on_idx;
+
+DROP INDEX IF EXISTS events_posted_on_idx;
+
+DROP INDEX IF EXISTS rides_maintenance_ride_id_idx;
+DROP INDEX IF EXISTS rides_maintenance_start_datetime_idx;
+
+DROP INDEX IF EXISTS tickets_on_rides_ticket_id_idx;
+DROP INDEX IF EXISTS tickets_on_rides_ride_id_idx;
+DROP INDEX IF EXISTS tickets_on_rides_scan_datetime_idx;
+
+DROP INDEX IF EXISTS tickets_user_id_idx;
+DROP INDEX IF EXISTS tickets_purchased_on_idx;
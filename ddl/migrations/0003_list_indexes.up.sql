@@ -0,0 +1,21 @@
+-- ================================================================
+-- Team 14 - Theme Park
+-- Indexes for paginated lists
+--
+-- Lists are paginated by keyset, on their default sort column and the ID, and
+-- filtered on the columns below.
+-- ================================================================
+
+CREATE INDEX tickets_purchased_on_idx ON tickets (purchased_on, id);
+CREATE INDEX tickets_user_id_idx ON tickets (user_id);
+
+CREATE INDEX tickets_on_rides_scan_datetime_idx ON tickets_on_rides (scan_datetime, id);
+CREATE INDEX tickets_on_rides_ride_id_idx ON tickets_on_rides (ride_id);
+CREATE INDEX tickets_on_rides_ticket_id_idx ON tickets_on_rides (ticket_id);
+
+CREATE INDEX rides_maintenance_start_datetime_idx ON rides_maintenance (start_datetime, id);
+CREATE INDEX rides_maintenance_ride_id_idx ON rides_maintenance (ride_id);
+
+CREATE INDEX events_posted_on_idx ON events (posted_on, id);
+
+CREATE INDEX users_registered_on_idx ON users (registered_on, id);
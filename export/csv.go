@@ -0,0 +1,102 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WriteCSV writes the given table as CSV, with the column names as header.
+// Times are written as RFC3339, and text that spreadsheets would take for a
+// formula is escaped (see escapeFormula).
+func WriteCSV(w io.Writer, table *Table) error {
+	return newCSVWriter(w).writeTable(table)
+}
+
+// csvWriter writes CSV in batches, see WriteCSV and NewWriter.
+type csvWriter struct {
+	writer *csv.Writer
+	header bool
+}
+
+// newCSVWriter returns a new csvWriter instance.
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{
+		writer: csv.NewWriter(w),
+	}
+}
+
+// Write writes the given rows, see NewTable.
+func (cw *csvWriter) Write(rows interface{}) error {
+	table, err := NewTable(rows)
+	if err != nil {
+		return err
+	}
+	return cw.writeTable(table)
+}
+
+// Close does nothing, as nothing follows the last row.
+func (cw *csvWriter) Close() error {
+	return nil
+}
+
+// writeTable writes the rows of the given table, after the header if it's the
+// first table.
+func (cw *csvWriter) writeTable(table *Table) error {
+	if !cw.header {
+		err := cw.writer.Write(table.Columns)
+		if err != nil {
+			return err
+		}
+		cw.header = true
+	}
+
+	record := make([]string, len(table.Columns))
+	for _, row := range table.Rows {
+		for i, value := range row {
+			record[i] = formatCell(value)
+			if _, ok := value.(string); ok {
+				record[i] = escapeFormula(record[i])
+			}
+		}
+
+		err := cw.writer.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.writer.Flush()
+	return cw.writer.Error()
+}
+
+// escapeFormula prefixes the given text with a quote if it starts like a
+// formula (=, +, -, @, a tab or a carriage return), so that spreadsheets
+// opening the CSV show it as text instead of evaluating it.
+func escapeFormula(text string) string {
+	if len(text) > 0 && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
+
+// formatCell returns the text of the given cell.
+func formatCell(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(value)
+	case time.Time:
+		return value.Format(time.RFC3339)
+	}
+	return ""
+}
@@ -0,0 +1,106 @@
+// Package export writes lists of rows as CSV, NDJSON or XLSX, for clients that
+// download them into spreadsheets instead of reading JSON. Lists can be
+// written in batches, and long JSON lists streamed as well (see Writer).
+package export
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// Format is an output format of lists.
+type Format string
+
+// Supported formats, JSON being the default.
+const (
+	JSON   Format = "json"
+	CSV    Format = "csv"
+	NDJSON Format = "ndjson"
+	XLSX   Format = "xlsx"
+)
+
+// formats are the supported formats, in the order they are listed in errors.
+var formats = []Format{JSON, CSV, NDJSON, XLSX}
+
+// contentTypes maps the formats to their content type.
+var contentTypes = map[Format]string{
+	JSON:   "application/json",
+	CSV:    "text/csv",
+	NDJSON: "application/x-ndjson",
+	XLSX:   "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+}
+
+// acceptedTypes maps the media types recognized in Accept headers to their
+// format.
+var acceptedTypes = map[string]Format{
+	"application/json":     JSON,
+	"text/csv":             CSV,
+	"application/x-ndjson": NDJSON,
+	"application/ndjson":   NDJSON,
+	contentTypes[XLSX]:     XLSX,
+}
+
+// ContentType returns the content type of responses in the format.
+func (f Format) ContentType() string {
+	if f == CSV {
+		return contentTypes[f] + "; charset=utf-8"
+	}
+	return contentTypes[f]
+}
+
+// Negotiate returns the format asked for by the given format query parameter
+// or, if it's empty, by the first supported type of the given Accept header.
+// It defaults to JSON, and fails if the format parameter isn't supported.
+func Negotiate(format, accept string) (Format, error) {
+	if len(format) > 0 {
+		for _, f := range formats {
+			if strings.EqualFold(format, string(f)) {
+				return f, nil
+			}
+		}
+
+		names := make([]string, len(formats))
+		for i, f := range formats {
+			names[i] = string(f)
+		}
+		return "", fmt.Errorf("format must be one of %s", strings.Join(names, ", "))
+	}
+
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		if f, ok := acceptedTypes[mediaType]; ok {
+			return f, nil
+		}
+	}
+
+	return JSON, nil
+}
+
+// Writer writes a list in a download format one batch of rows at a time, like
+// a page, so that long lists don't have to be held in memory. Every batch is a
+// slice (or a pointer to a slice) of the same type, and Close must be called
+// once they are all written.
+type Writer interface {
+	Write(rows interface{}) error
+	Close() error
+}
+
+// NewWriter returns a Writer of the given format to w. Workbooks get a single
+// sheet named after the given name. JSON isn't a download format.
+func NewWriter(w io.Writer, format Format, name string) (Writer, error) {
+	switch format {
+	case CSV:
+		return newCSVWriter(w), nil
+	case NDJSON:
+		return &ndjsonWriter{w}, nil
+	case XLSX:
+		return newXLSXWriter(w, name)
+	}
+	return nil, fmt.Errorf("newWriter: %s is not a download format", format)
+}
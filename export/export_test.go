@@ -0,0 +1,251 @@
+package export_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/export"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+type month struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+}
+
+type location struct {
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+}
+
+type row struct {
+	month
+	Name     string            `json:"name"`
+	Note     models.NullString `json:"note"`
+	Location location          `json:"location"`
+	Tags     []string          `json:"tags"`
+	Open     bool              `json:"open"`
+	Opened   time.Time         `json:"opened"`
+	Secret   string            `json:"-"`
+	internal string
+}
+
+func sampleRows() []*row {
+	opened := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	return []*row{
+		{month{2020, 3}, "Ride, \"the\" one", models.NewNullString("fast"), location{-95.5, 29.75}, []string{"a"}, true, opened, "secret", ""},
+		{month{2020, 4}, "Ride <two>", models.NullString{}, location{}, nil, false, opened.AddDate(0, 1, 0), "secret", ""},
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		format, accept string
+		expected       export.Format
+	}{
+		{"", "", export.JSON},
+		{"", "*/*", export.JSON},
+		{"", "text/html, application/json", export.JSON},
+		{"", "text/csv", export.CSV},
+		{"", "text/html;q=0.9, application/x-ndjson", export.NDJSON},
+		{"", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", export.XLSX},
+		{"XLSX", "text/csv", export.XLSX},
+		{"json", "text/csv", export.JSON},
+	}
+
+	for _, test := range tests {
+		format, err := export.Negotiate(test.format, test.accept)
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, format, "%s %s", test.format, test.accept)
+	}
+
+	_, err := export.Negotiate("pdf", "")
+	assert.EqualError(t, err, "format must be one of json, csv, ndjson, xlsx")
+}
+
+func TestNewTable(t *testing.T) {
+	table, err := export.NewTable(sampleRows())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, []string{"year", "month", "name", "note", "location.longitude", "location.latitude", "tags", "open", "opened"}, table.Columns)
+	assert.Equal(t, []interface{}{int64(2020), int64(3), "Ride, \"the\" one", "fast", -95.5, 29.75, `["a"]`, true, time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)}, table.Rows[0])
+	assert.Nil(t, table.Rows[1][3])
+	assert.Equal(t, "null", table.Rows[1][6])
+
+	// pointers to slices are accepted, like report rows
+	rows := sampleRows()
+	table, err = export.NewTable(&rows)
+	assert.Nil(t, err)
+	assert.Len(t, table.Rows, 2)
+
+	_, err = export.NewTable([]string{"a"})
+	assert.NotNil(t, err)
+}
+
+func TestWriteCSV(t *testing.T) {
+	table, _ := export.NewTable(sampleRows())
+
+	out := &bytes.Buffer{}
+	err := export.WriteCSV(out, table)
+	if assert.Nil(t, err) {
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Equal(t, "year,month,name,note,location.longitude,location.latitude,tags,open,opened", lines[0])
+		assert.Equal(t, `2020,3,"Ride, ""the"" one",fast,-95.5,29.75,"[""a""]",true,2020-03-01T12:00:00Z`, lines[1])
+		assert.Equal(t, `2020,4,Ride <two>,,0,0,null,false,2020-04-01T12:00:00Z`, lines[2])
+	}
+}
+
+func TestWriteCSVEscapesFormulas(t *testing.T) {
+	table := &export.Table{
+		Columns: []string{"text", "number"},
+		Rows: [][]interface{}{
+			{"=HYPERLINK(\"http://example.com\")", -1.5},
+			{"+1", int64(-2)},
+			{"-1", nil},
+			{"@SUM(A1)", nil},
+			{"\tcmd", nil},
+			{"\rcmd", nil},
+			{"a=b", nil},
+		},
+	}
+
+	out := &bytes.Buffer{}
+	err := export.WriteCSV(out, table)
+	if assert.Nil(t, err) {
+		records, err := csv.NewReader(out).ReadAll()
+		if assert.Nil(t, err) && assert.Len(t, records, 8) {
+			assert.Equal(t, []string{"'=HYPERLINK(\"http://example.com\")", "-1.5"}, records[1])
+			assert.Equal(t, []string{"'+1", "-2"}, records[2])
+			assert.Equal(t, "'-1", records[3][0])
+			assert.Equal(t, "'@SUM(A1)", records[4][0])
+			assert.Equal(t, "'\tcmd", records[5][0])
+			assert.Equal(t, "'\rcmd", records[6][0])
+			assert.Equal(t, "a=b", records[7][0])
+		}
+	}
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	out := &bytes.Buffer{}
+	err := export.WriteNDJSON(out, sampleRows())
+	if assert.Nil(t, err) {
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		assert.Len(t, lines, 2)
+		assert.True(t, strings.HasPrefix(lines[1], `{"year":2020,"month":4,"name":"Ride \u003ctwo\u003e","note":null,"location":{"longitude":0,"latitude":0}`), lines[1])
+	}
+}
+
+func TestWriteXLSX(t *testing.T) {
+	table, _ := export.NewTable(sampleRows())
+
+	out := &bytes.Buffer{}
+	err := export.WriteXLSX(out, "rides/monthly", table)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	parts := map[string]string{}
+	for _, file := range archive.File {
+		reader, _ := file.Open()
+		content, _ := io.ReadAll(reader)
+		reader.Close()
+		parts[file.Name] = string(content)
+
+		// every part is well formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(content))
+		for {
+			_, err := decoder.Token()
+			if err == io.EOF {
+				break
+			}
+			if !assert.Nil(t, err, file.Name) {
+				break
+			}
+		}
+	}
+
+	assert.Contains(t, parts, "[Content_Types].xml")
+	assert.Contains(t, parts["xl/workbook.xml"], `name="rides_monthly"`)
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A1" t="inlineStr" s="2"><is><t xml:space="preserve">year</t></is></c>`)
+	assert.Contains(t, sheet, `<c r="A2"><v>2020</v></c>`)
+	assert.Contains(t, sheet, `<c r="E2"><v>-95.5</v></c>`)
+	assert.Contains(t, sheet, `<c r="H2" t="b"><v>1</v></c>`)
+	assert.Contains(t, sheet, `<c r="I2" s="1"><v>43891.5</v></c>`)
+	assert.Contains(t, sheet, `Ride &lt;two&gt;`)
+	assert.NotContains(t, sheet, `r="D3"`)
+}
+
+func TestWriterWritesBatches(t *testing.T) {
+	for _, format := range []export.Format{export.CSV, export.NDJSON, export.XLSX} {
+		out := &bytes.Buffer{}
+		writer, err := export.NewWriter(out, format, "rides")
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Nil(t, writer.Write(sampleRows()))
+		assert.Nil(t, writer.Write(sampleRows()[:1]))
+		assert.Nil(t, writer.Close())
+
+		switch format {
+		case export.CSV:
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			if assert.Len(t, lines, 4) {
+				assert.True(t, strings.HasPrefix(lines[0], "year,"))
+				assert.Equal(t, lines[1], lines[3])
+			}
+		case export.NDJSON:
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			assert.Len(t, lines, 3)
+		case export.XLSX:
+			archive, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+			if assert.Nil(t, err) {
+				reader, _ := archive.Open("xl/worksheets/sheet1.xml")
+				sheet, _ := io.ReadAll(reader)
+				assert.Contains(t, string(sheet), `<c r="A4"><v>2020</v></c>`)
+				assert.NotContains(t, string(sheet), `r="A5"`)
+				assert.Equal(t, 1, strings.Count(string(sheet), `>year<`))
+			}
+		}
+	}
+
+	_, err := export.NewWriter(&bytes.Buffer{}, export.JSON, "rides")
+	assert.NotNil(t, err)
+}
+
+func TestJSONWriterMatchesEncoder(t *testing.T) {
+	for _, batches := range [][][]*row{{}, {sampleRows()}, {sampleRows(), {}, sampleRows()[:1]}} {
+		all := []*row{}
+		out := &bytes.Buffer{}
+		writer := export.NewJSONWriter(out, "    ")
+		for _, batch := range batches {
+			all = append(all, batch...)
+			assert.Nil(t, writer.Write(batch))
+		}
+		assert.Nil(t, writer.Close())
+
+		expected := &bytes.Buffer{}
+		encoder := json.NewEncoder(expected)
+		encoder.SetIndent("", "    ")
+		encoder.Encode(all)
+		assert.Equal(t, expected.String(), out.String())
+	}
+}
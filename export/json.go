@@ -0,0 +1,74 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// jsonWriter writes a JSON array in batches, see NewJSONWriter.
+type jsonWriter struct {
+	w      io.Writer
+	indent string
+	rows   int
+}
+
+// NewJSONWriter returns a Writer of a single JSON array to w, indented with
+// the given indent like json.Encoder.SetIndent, for JSON lists that are too
+// long to be held in memory.
+func NewJSONWriter(w io.Writer, indent string) Writer {
+	return &jsonWriter{
+		w:      w,
+		indent: indent,
+	}
+}
+
+// Write writes every element of the given slice (or pointer to a slice) in
+// the array.
+func (jw *jsonWriter) Write(rows interface{}) error {
+	slice := reflect.ValueOf(rows)
+	for slice.Kind() == reflect.Ptr {
+		slice = slice.Elem()
+	}
+
+	if slice.Kind() != reflect.Slice {
+		return fmt.Errorf("writeJSON: %T is not a slice", rows)
+	}
+
+	for i := 0; i < slice.Len(); i++ {
+		encoded, err := json.MarshalIndent(slice.Index(i).Interface(), jw.indent, jw.indent)
+		if err != nil {
+			return err
+		}
+
+		separator := ",\n"
+		if jw.rows <= 0 {
+			separator = "[\n"
+		}
+		jw.rows++
+
+		_, err = io.WriteString(jw.w, separator+jw.indent)
+		if err != nil {
+			return err
+		}
+
+		_, err = jw.w.Write(encoded)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Close ends the array.
+func (jw *jsonWriter) Close() error {
+	end := "\n]\n"
+	if jw.rows <= 0 {
+		end = "[]\n"
+	}
+
+	_, err := io.WriteString(jw.w, end)
+	return err
+}
@@ -0,0 +1,61 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+)
+
+// ndjsonFlushRows is how many rows are written between flushes, when writing
+// NDJSON to a http.Flusher.
+const ndjsonFlushRows = 100
+
+// WriteNDJSON writes every element of the given slice (or pointer to a slice)
+// as a line of JSON, keeping the shape of the JSON responses. If w is a
+// http.Flusher, rows are flushed as they are written.
+func WriteNDJSON(w io.Writer, rows interface{}) error {
+	slice := reflect.ValueOf(rows)
+	for slice.Kind() == reflect.Ptr {
+		slice = slice.Elem()
+	}
+
+	if slice.Kind() != reflect.Slice {
+		return fmt.Errorf("writeNDJSON: %T is not a slice", rows)
+	}
+
+	flusher, _ := w.(http.Flusher)
+	encoder := json.NewEncoder(w)
+	for i := 0; i < slice.Len(); i++ {
+		err := encoder.Encode(slice.Index(i).Interface())
+		if err != nil {
+			return err
+		}
+
+		if flusher != nil && (i+1)%ndjsonFlushRows == 0 {
+			flusher.Flush()
+		}
+	}
+
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+// ndjsonWriter writes NDJSON in batches, see WriteNDJSON and NewWriter.
+type ndjsonWriter struct {
+	w io.Writer
+}
+
+// Write writes the given rows, flushed if possible.
+func (nw *ndjsonWriter) Write(rows interface{}) error {
+	return WriteNDJSON(nw.w, rows)
+}
+
+// Close does nothing, as nothing follows the last row.
+func (nw *ndjsonWriter) Close() error {
+	return nil
+}
@@ -0,0 +1,188 @@
+package export
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	timeType   = reflect.TypeOf(time.Time{})
+	valuerType = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// Table is a list of rows flattened into columns, named after the JSON names
+// of the fields. Nested structs are flattened into columns like
+// "location.longitude", other nested values are written as JSON.
+//
+// Cells are nil, string, int64, float64, bool or time.Time.
+type Table struct {
+	Columns []string
+	Rows    [][]interface{}
+}
+
+// column is a column of a table, and the path to its field in the row type.
+type column struct {
+	name  string
+	index []int
+}
+
+// NewTable returns the table for the given rows, which must be a slice (or a
+// pointer to a slice) of structs or pointers to structs.
+func NewTable(rows interface{}) (*Table, error) {
+	slice := reflect.ValueOf(rows)
+	for slice.Kind() == reflect.Ptr {
+		slice = slice.Elem()
+	}
+
+	if slice.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("newTable: %T is not a slice", rows)
+	}
+
+	rowType := slice.Type().Elem()
+	for rowType.Kind() == reflect.Ptr {
+		rowType = rowType.Elem()
+	}
+
+	if rowType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("newTable: %T is not a slice of structs", rows)
+	}
+
+	columns := columnsOf(rowType, "", nil)
+	table := &Table{
+		Columns: make([]string, len(columns)),
+		Rows:    make([][]interface{}, slice.Len()),
+	}
+
+	for i, column := range columns {
+		table.Columns[i] = column.name
+	}
+
+	for i := 0; i < slice.Len(); i++ {
+		row := make([]interface{}, len(columns))
+		for j, column := range columns {
+			row[j] = cell(field(slice.Index(i), column.index))
+		}
+		table.Rows[i] = row
+	}
+
+	return table, nil
+}
+
+// columnsOf returns the columns of the given struct type, following its JSON
+// encoding.
+func columnsOf(t reflect.Type, prefix string, index []int) []column {
+	columns := []column{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldType := f.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+
+		if f.Anonymous && len(name) <= 0 && isNested(fieldType) {
+			columns = append(columns, columnsOf(fieldType, prefix, fieldIndex)...)
+			continue
+		}
+
+		if len(f.PkgPath) > 0 {
+			continue // unexported
+		}
+
+		if len(name) <= 0 {
+			name = f.Name
+		}
+
+		if isNested(fieldType) {
+			columns = append(columns, columnsOf(fieldType, prefix+name+".", fieldIndex)...)
+			continue
+		}
+
+		columns = append(columns, column{prefix + name, fieldIndex})
+	}
+	return columns
+}
+
+// isNested returns true if the given type is a struct whose fields become
+// columns, rather than a single value like time.Time or models.NullString.
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !t.Implements(valuerType) && !reflect.PtrTo(t).Implements(valuerType)
+}
+
+// field returns the field at the given path, or an invalid value if a pointer
+// on the way is nil.
+func field(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		for v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
+
+// cell returns the value of a cell for the given field.
+func cell(v reflect.Value) interface{} {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+
+	if !v.IsValid() {
+		return nil
+	}
+
+	value := v.Interface()
+	if v.CanAddr() && reflect.PtrTo(v.Type()).Implements(valuerType) {
+		value = v.Addr().Interface()
+	}
+
+	if valuer, ok := value.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil || value == nil {
+			return nil
+		}
+		if b, ok := value.([]byte); ok {
+			return string(b)
+		}
+		return value
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	case reflect.String:
+		return v.String()
+	}
+
+	if v.Type() == timeType {
+		return v.Interface()
+	}
+
+	// slices, maps and the like are kept as JSON
+	encoded, err := json.Marshal(v.Interface())
+	if err != nil {
+		return nil
+	}
+	return string(encoded)
+}
@@ -0,0 +1,267 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxSheetName is the longest name of a worksheet allowed by Excel.
+const maxSheetName = 31
+
+// maxSheetRows is the most rows a worksheet can have in Excel, header
+// included.
+const maxSheetRows = 1048576
+
+// Cell styles, indexes of cellXfs in xlsxStyles.
+const (
+	styleDefault  = 0
+	styleDateTime = 1
+	styleHeader   = 2
+)
+
+// excelEpoch is the day 0 of Excel date serials (with the 1900 leap year bug
+// accounted for, for dates after February 1900).
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// sheetNameReplacer replaces the characters not allowed in sheet names.
+var sheetNameReplacer = strings.NewReplacer("[", "_", "]", "_", ":", "_", "*", "_", "?", "_", "/", "_", "\\", "_")
+
+// WriteXLSX writes the given table as a workbook with a single sheet of the
+// given name, and the column names as a bold header row. Numbers and booleans
+// are written as typed cells, times as dates in UTC.
+func WriteXLSX(w io.Writer, sheet string, table *Table) error {
+	xw, err := newXLSXWriter(w, sheet)
+	if err != nil {
+		return err
+	}
+
+	err = xw.writeTable(table)
+	if err != nil {
+		return err
+	}
+
+	return xw.Close()
+}
+
+// xlsxWriter writes a workbook in batches of rows, see WriteXLSX and
+// NewWriter. The sheet is streamed as it's written, so that only a batch is
+// held in memory.
+type xlsxWriter struct {
+	archive *zip.Writer
+	sheet   *bufio.Writer
+	rows    int // written so far, header included
+}
+
+// newXLSXWriter returns a new xlsxWriter instance, and writes every part of
+// the workbook up to the first row of the given sheet.
+func newXLSXWriter(w io.Writer, sheet string) (*xlsxWriter, error) {
+	archive := zip.NewWriter(w)
+
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", strings.Replace(xlsxWorkbook, "{{sheet}}", escapeXML(sheetName(sheet)), 1)},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", xlsxStyles},
+	}
+
+	for _, part := range parts {
+		writer, err := archive.Create(part.name)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = io.WriteString(writer, part.content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	writer, err := archive.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return nil, err
+	}
+
+	buffered := bufio.NewWriter(writer)
+	buffered.WriteString(xml.Header)
+	buffered.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	return &xlsxWriter{
+		archive: archive,
+		sheet:   buffered,
+	}, nil
+}
+
+// Write writes the given rows, see NewTable.
+func (xw *xlsxWriter) Write(rows interface{}) error {
+	table, err := NewTable(rows)
+	if err != nil {
+		return err
+	}
+	return xw.writeTable(table)
+}
+
+// Close ends the sheet and the workbook.
+func (xw *xlsxWriter) Close() error {
+	xw.sheet.WriteString(`</sheetData></worksheet>`)
+	err := xw.sheet.Flush()
+	if err != nil {
+		return err
+	}
+
+	return xw.archive.Close()
+}
+
+// writeTable writes the rows of the given table, after the header row if it's
+// the first table. It fails past the rows a sheet can have.
+func (xw *xlsxWriter) writeTable(table *Table) error {
+	if xw.rows <= 0 {
+		xw.rows++
+		writeRow(xw.sheet, xw.rows, len(table.Columns), func(i int) (interface{}, int) {
+			return table.Columns[i], styleHeader
+		})
+	}
+
+	if xw.rows+len(table.Rows) > maxSheetRows {
+		return fmt.Errorf("writeXLSX: a sheet can't have more than %d rows", maxSheetRows-1)
+	}
+
+	for _, row := range table.Rows {
+		xw.rows++
+		writeRow(xw.sheet, xw.rows, len(row), func(i int) (interface{}, int) {
+			return row[i], styleDefault
+		})
+	}
+
+	return xw.sheet.Flush()
+}
+
+// writeRow writes the row at the given (1-based) index, with the given number
+// of cells.
+func writeRow(w *bufio.Writer, index, cells int, cell func(i int) (interface{}, int)) {
+	row := strconv.Itoa(index)
+	w.WriteString(`<row r="` + row + `">`)
+
+	for i := 0; i < cells; i++ {
+		value, style := cell(i)
+		if value == nil {
+			continue
+		}
+
+		ref := columnName(i) + row
+		switch value := value.(type) {
+		case int64:
+			writeCell(w, ref, "", style, strconv.FormatInt(value, 10))
+		case float64:
+			writeCell(w, ref, "", style, strconv.FormatFloat(value, 'f', -1, 64))
+		case bool:
+			text := "0"
+			if value {
+				text = "1"
+			}
+			writeCell(w, ref, "b", style, text)
+		case time.Time:
+			serial := value.UTC().Sub(excelEpoch).Hours() / 24
+			writeCell(w, ref, "", styleDateTime, strconv.FormatFloat(serial, 'f', -1, 64))
+		default:
+			w.WriteString(`<c r="` + ref + `" t="inlineStr"` + styleAttr(style) + `><is><t xml:space="preserve">`)
+			w.WriteString(escapeXML(formatCell(value)))
+			w.WriteString(`</t></is></c>`)
+		}
+	}
+
+	w.WriteString(`</row>`)
+}
+
+func writeCell(w *bufio.Writer, ref, cellType string, style int, value string) {
+	w.WriteString(`<c r="` + ref + `"`)
+	if len(cellType) > 0 {
+		w.WriteString(` t="` + cellType + `"`)
+	}
+	w.WriteString(styleAttr(style) + `><v>` + value + `</v></c>`)
+}
+
+func styleAttr(style int) string {
+	if style == styleDefault {
+		return ""
+	}
+	return ` s="` + strconv.Itoa(style) + `"`
+}
+
+// columnName returns the name of the column at the given (0-based) index,
+// e.g. "A", "Z", "AA".
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+	return name
+}
+
+// sheetName returns the given name, made valid as a sheet name.
+func sheetName(name string) string {
+	name = sheetNameReplacer.Replace(name)
+	if len(name) <= 0 {
+		return "Sheet1"
+	}
+
+	runes := []rune(name)
+	if len(runes) > maxSheetName {
+		runes = runes[:maxSheetName]
+	}
+	return string(runes)
+}
+
+// escapeXML escapes the given text for XML, replacing characters that are not
+// allowed in XML.
+func escapeXML(text string) string {
+	builder := &strings.Builder{}
+	xml.EscapeText(builder, []byte(text))
+	return builder.String()
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="{{sheet}}" sheetId="1" r:id="rId1"/></sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles holds the cell styles: default, date and time, and bold (for the
+// header).
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="3">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`</styleSheet>`
@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/brianvoe/gofakeit/v4"
+)
+
+// InsertAuditEntry inserts a new audit entry for the given actor and entity
+// at the given time.
+func InsertAuditEntry(execer Execer, actorID, action, entityType, entityID string, recordedOn time.Time) (string, error) {
+	ID := gofakeit.UUID()
+
+	insertAuditEntryQuery := `
+	INSERT INTO audit_log (id, actor_id, action, entity_type, entity_id, after, recorded_on)
+	VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`
+
+	after := `{"id": "` + entityID + `"}`
+	_, err := execer.Exec(insertAuditEntryQuery, ID, actorID, action, entityType, entityID, after, recordedOn)
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
+
+// MustInsertAuditEntry is like InsertAuditEntry but panics on error.
+func MustInsertAuditEntry(mustExecer MustExecer, actorID, action, entityType, entityID string, recordedOn time.Time) string {
+	return MustInsert(InsertAuditEntry(&AsExecer{mustExecer}, actorID, action, entityType, entityID, recordedOn))
+}
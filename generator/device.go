@@ -0,0 +1,54 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/brianvoe/gofakeit/v4"
+)
+
+// InsertDevice inserts a new device with the given name and secret hash.
+func InsertDevice(execer Execer, name, secretHash string) (string, error) {
+	ID := gofakeit.UUID()
+
+	insertDeviceQuery := `
+	INSERT INTO devices (id, name, secret_hash, created_on)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := execer.Exec(insertDeviceQuery, ID, name, secretHash, time.Now().UTC())
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
+
+// InsertDeviceScope allows the given device to do the given operation on the
+// given ride, or on any ride if rideID is empty.
+func InsertDeviceScope(execer Execer, deviceID, operation, rideID string) error {
+	insertDeviceScopeQuery := `
+	INSERT INTO device_scopes (device_id, operation, ride_id)
+	VALUES ($1, $2, $3)
+	`
+
+	var nullableRideID interface{}
+	if len(rideID) > 0 {
+		nullableRideID = rideID
+	}
+
+	_, err := execer.Exec(insertDeviceScopeQuery, deviceID, operation, nullableRideID)
+	return err
+}
+
+// MustInsertDevice is like InsertDevice but panics on error.
+func MustInsertDevice(mustExecer MustExecer, name, secretHash string) string {
+	return MustInsert(InsertDevice(&AsExecer{mustExecer}, name, secretHash))
+}
+
+// MustInsertDeviceScope is like InsertDeviceScope but panics on error.
+func MustInsertDeviceScope(mustExecer MustExecer, deviceID, operation, rideID string) {
+	err := InsertDeviceScope(&AsExecer{mustExecer}, deviceID, operation, rideID)
+	if err != nil {
+		panic(err)
+	}
+}
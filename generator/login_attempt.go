@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/brianvoe/gofakeit/v4"
+)
+
+// InsertLoginAttempt inserts a new log in attempt for the given email at the
+// given time.
+func InsertLoginAttempt(execer Execer, email string, success bool, attemptedOn time.Time) (string, error) {
+	ID := gofakeit.UUID()
+
+	insertLoginAttemptQuery := `
+	INSERT INTO login_attempts (id, email, user_agent, address, success, attempted_on)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := execer.Exec(insertLoginAttemptQuery, ID, email, gofakeit.UserAgent(), gofakeit.IPv4Address(), success, attemptedOn)
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
+
+// MustInsertLoginAttempt is like InsertLoginAttempt but panics on error.
+func MustInsertLoginAttempt(mustExecer MustExecer, email string, success bool, attemptedOn time.Time) string {
+	return MustInsert(InsertLoginAttempt(&AsExecer{mustExecer}, email, success, attemptedOn))
+}
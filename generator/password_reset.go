@@ -0,0 +1,31 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/brianvoe/gofakeit/v4"
+)
+
+// InsertPasswordReset inserts a new password reset for the given user, with
+// the given token hash, issued at the given time and valid for the given
+// duration.
+func InsertPasswordReset(execer Execer, userID, tokenHash string, issuedOn time.Time, duration time.Duration) (string, error) {
+	ID := gofakeit.UUID()
+
+	insertPasswordResetQuery := `
+	INSERT INTO password_resets (id, user_id, token_hash, issued_on, expires_on)
+	VALUES ($1, $2, $3, $4, $5)
+	`
+
+	_, err := execer.Exec(insertPasswordResetQuery, ID, userID, tokenHash, issuedOn, issuedOn.Add(duration))
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
+
+// MustInsertPasswordReset is like InsertPasswordReset but panics on error.
+func MustInsertPasswordReset(mustExecer MustExecer, userID, tokenHash string, issuedOn time.Time, duration time.Duration) string {
+	return MustInsert(InsertPasswordReset(&AsExecer{mustExecer}, userID, tokenHash, issuedOn, duration))
+}
@@ -0,0 +1,30 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/brianvoe/gofakeit/v4"
+)
+
+// InsertSession inserts a new session for the given user, issued at the
+// given time and valid for the given duration.
+func InsertSession(execer Execer, userID string, issuedOn time.Time, duration time.Duration) (string, error) {
+	ID := gofakeit.UUID()
+
+	insertSessionQuery := `
+	INSERT INTO sessions (id, user_id, user_agent, address, issued_on, expires_on)
+	VALUES ($1, $2, $3, $4, $5, $6)
+	`
+
+	_, err := execer.Exec(insertSessionQuery, ID, userID, gofakeit.UserAgent(), gofakeit.IPv4Address(), issuedOn, issuedOn.Add(duration))
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
+
+// MustInsertSession is like InsertSession but panics on error.
+func MustInsertSession(mustExecer MustExecer, userID string, issuedOn time.Time, duration time.Duration) string {
+	return MustInsert(InsertSession(&AsExecer{mustExecer}, userID, issuedOn, duration))
+}
@@ -0,0 +1,57 @@
+package generator
+
+import (
+	"time"
+
+	"github.com/brianvoe/gofakeit/v4"
+)
+
+// InsertTwoFactor inserts the two-factor authentication for the given user
+// with the given secret. If enabled is false, it's left pending.
+func InsertTwoFactor(execer Execer, userID, secret string, enabled bool) error {
+	insertTwoFactorQuery := `
+	INSERT INTO two_factors (user_id, secret, created_on, enabled_on)
+	VALUES ($1, $2, $3, $4)
+	`
+
+	now := time.Now().UTC()
+
+	var enabledOn interface{}
+	if enabled {
+		enabledOn = now
+	}
+
+	_, err := execer.Exec(insertTwoFactorQuery, userID, secret, now, enabledOn)
+	return err
+}
+
+// MustInsertTwoFactor is like InsertTwoFactor but panics on error.
+func MustInsertTwoFactor(mustExecer MustExecer, userID, secret string, enabled bool) {
+	err := InsertTwoFactor(&AsExecer{mustExecer}, userID, secret, enabled)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// InsertRecoveryCode inserts a new, unused recovery code with the given hash
+// for the given user.
+func InsertRecoveryCode(execer Execer, userID, codeHash string) (string, error) {
+	ID := gofakeit.UUID()
+
+	insertRecoveryCodeQuery := `
+	INSERT INTO recovery_codes (id, user_id, code_hash)
+	VALUES ($1, $2, $3)
+	`
+
+	_, err := execer.Exec(insertRecoveryCodeQuery, ID, userID, codeHash)
+	if err != nil {
+		return "", err
+	}
+
+	return ID, nil
+}
+
+// MustInsertRecoveryCode is like InsertRecoveryCode but panics on error.
+func MustInsertRecoveryCode(mustExecer MustExecer, userID, codeHash string) string {
+	return MustInsert(InsertRecoveryCode(&AsExecer{mustExecer}, userID, codeHash))
+}
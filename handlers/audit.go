@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"github.com/labstack/echo/v4"
+
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// AuditHandler handles HTTP requests for the audit log.
+type AuditHandler struct {
+	auditUsecase usecases.AuditUsecase
+}
+
+// NewAuditHandler returns a new AuditHandler instance.
+func NewAuditHandler(auditUsecase usecases.AuditUsecase) *AuditHandler {
+	return &AuditHandler{
+		auditUsecase,
+	}
+}
+
+// Bind sets up the routes for the handler.
+func (ah *AuditHandler) Bind(g *echo.Group) error {
+	supervisor := middlew.RequireRoles(models.RoleSupervisor)
+
+	g.GET("/audit", ah.Fetch, supervisor)
+	return nil
+}
+
+// Fetch fetches the audit log. It can be filtered by actor (actorId), entity
+// (entityType, entityId) and time range (since, until as RFC3339).
+func (ah *AuditHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	filter := &models.AuditFilter{
+		ActorID:    c.QueryParam("actorId"),
+		EntityType: c.QueryParam("entityType"),
+		EntityID:   c.QueryParam("entityId"),
+	}
+
+	var err error
+	filter.Since, filter.Until, err = parseTimeRange(c)
+	if err != nil {
+		return err
+	}
+
+	entries, err := ah.auditUsecase.Fetch(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	return respondList(c, "audit", entries)
+}
@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// DeviceHandler handles HTTP requests for devices.
+type DeviceHandler struct {
+	deviceUsecase usecases.DeviceUsecase
+}
+
+// NewDeviceHandler returns a new DeviceHandler instance.
+func NewDeviceHandler(deviceUsecase usecases.DeviceUsecase) *DeviceHandler {
+	return &DeviceHandler{
+		deviceUsecase,
+	}
+}
+
+// Bind sets up the routes for the handler.
+func (dh *DeviceHandler) Bind(g *echo.Group) error {
+	supervisor := middlew.RequireRoles(models.RoleSupervisor)
+
+	g.GET("/devices", dh.Fetch, supervisor)
+	g.GET("/devices/:deviceID", dh.GetByID, supervisor)
+	g.POST("/devices", dh.Store, supervisor)
+	g.PUT("/devices/:deviceID", dh.Update, supervisor)
+	g.POST("/devices/:deviceID/rotate", dh.Rotate, supervisor)
+	g.DELETE("/devices/:deviceID", dh.Revoke, supervisor)
+	return nil
+}
+
+// Fetch fetches all devices.
+func (dh *DeviceHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	devices, err := dh.deviceUsecase.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, devices, Indent)
+}
+
+// GetByID gets a specific device.
+func (dh *DeviceHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	deviceID := c.Param("deviceID")
+
+	device, err := dh.deviceUsecase.GetByID(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, device, Indent)
+}
+
+// Store creates a new device. The response holds the device key, which
+// can't be retrieved again.
+func (dh *DeviceHandler) Store(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	device := &models.Device{}
+
+	err := c.Bind(device)
+	if err != nil {
+		return err
+	}
+
+	key, err := dh.deviceUsecase.Store(ctx, device)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusCreated, deviceKeyResponse{device, key}, Indent)
+}
+
+// Update updates the name and scopes of a specific device.
+func (dh *DeviceHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	deviceID := c.Param("deviceID")
+
+	device := &models.Device{}
+
+	err := c.Bind(device)
+	if err != nil {
+		return err
+	}
+
+	// the body must not change which device is updated
+	device.ID = deviceID
+
+	err = dh.deviceUsecase.Update(ctx, device)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, device, Indent)
+}
+
+// Rotate replaces the key of a specific device. The old key stops working.
+func (dh *DeviceHandler) Rotate(c echo.Context) error {
+	ctx := c.Request().Context()
+	deviceID := c.Param("deviceID")
+
+	device, key, err := dh.deviceUsecase.Rotate(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, deviceKeyResponse{device, key}, Indent)
+}
+
+// Revoke revokes a specific device.
+func (dh *DeviceHandler) Revoke(c echo.Context) error {
+	ctx := c.Request().Context()
+	deviceID := c.Param("deviceID")
+
+	err := dh.deviceUsecase.Revoke(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, "", Indent)
+}
+
+// deviceKeyResponse represents the JSON response holding a device and its
+// key. Devices send the key in the X-Device-Key header.
+type deviceKeyResponse struct {
+	Device *models.Device `json:"device"`
+	Key    string         `json:"key"`
+}
@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	_ "embed"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/export"
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/openapi"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/reports"
+)
+
+// DocsHandler handles HTTP requests for the API documentation.
+type DocsHandler struct {
+	document *openapi.Document
+}
+
+// NewDocsHandler returns a new DocsHandler instance. The document it serves
+// is generated from the routes, so it's set once every route is bound, see
+// SetDocument.
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// SetDocument sets the document served, see Docs. It must be called before
+// the server starts.
+func (dh *DocsHandler) SetDocument(document *openapi.Document) {
+	dh.document = document
+}
+
+// Bind sets up the routes for the handler.
+func (dh *DocsHandler) Bind(g *echo.Group) error {
+	g.GET("/docs", dh.UI)
+	g.GET("/docs/openapi.json", dh.Spec)
+	return nil
+}
+
+// UI serves a page to browse the documentation.
+func (dh *DocsHandler) UI(c echo.Context) error {
+	return c.HTML(http.StatusOK, docsPage)
+}
+
+// Spec serves the OpenAPI document.
+func (dh *DocsHandler) Spec(c echo.Context) error {
+	return c.JSONPretty(http.StatusOK, dh.document, Indent)
+}
+
+// docsPage renders the OpenAPI document. It's embedded rather than loaded
+// from a CDN, so that the docs don't run scripts from third parties.
+//
+//go:embed docs.html
+var docsPage string
+
+// Docs returns the OpenAPI document of the given routes, as registered in echo
+// (see echo.Echo.Routes), served in the given API versions. Routes are
+// described by rootRoutes and versionedRoutes, which must be updated when
+// routes are bound; a route missing there is still documented, with its path
+// parameters only. The error lists the routes that are not described, and
+// the descriptions of routes that are not registered.
+func Docs(routes []*echo.Route, versions ...middlew.Version) (*openapi.Document, error) {
+	generator := openapi.NewGenerator()
+	generator.Override(models.NullString{}, &openapi.Schema{Type: "string", Nullable: true})
+	generator.Override(models.NullTime{}, &openapi.Schema{Type: "string", Format: "date-time", Nullable: true})
+	generator.Override(models.NullJSON{}, &openapi.Schema{Nullable: true})
+
+	document := openapi.New(openapi.Info{
+		Title:       "Theme Park API",
+		Description: "Errors are described in the README.",
+		Version:     "1.0.0",
+	}, generator, ResponseError{})
+
+	document.AddSecurityScheme("key", openapi.SecurityScheme{Type: "http", Scheme: "bearer"})
+	document.AddSecurityScheme("deviceKey", openapi.SecurityScheme{Type: "apiKey", Name: "X-Device-Key", In: "header"})
+
+	described := map[string]openapi.Route{}
+	for _, route := range rootRoutes() {
+		described[route.Method+" "+route.Path] = route
+	}
+
+	for _, version := range versions {
+		for _, route := range versionedRoutes(version.Number) {
+			route.Path = version.Prefix() + route.Path
+			route.Deprecated = version.IsDeprecated()
+			described[route.Method+" "+route.Path] = route
+		}
+	}
+
+	undescribed := []string{}
+	for _, registered := range routes {
+		operation := registered.Method + " " + registered.Path
+		route, ok := described[operation]
+		if !ok {
+			undescribed = append(undescribed, operation)
+			route = openapi.Route{Method: registered.Method, Path: registered.Path}
+		}
+
+		delete(described, operation)
+		document.Add(route)
+	}
+
+	unregistered := []string{}
+	for operation := range described {
+		unregistered = append(unregistered, operation)
+	}
+
+	if len(undescribed) > 0 || len(unregistered) > 0 {
+		sort.Strings(undescribed)
+		sort.Strings(unregistered)
+		return document, fmt.Errorf("Docs: routes not described: %v, described but not registered: %v", undescribed, unregistered)
+	}
+
+	return document, nil
+}
+
+// rootRoutes returns the routes that are not versioned.
+func rootRoutes() []openapi.Route {
+	return []openapi.Route{
+		// health and metrics
+		openapi.Route{Method: http.MethodGet, Path: "/healthz", Tag: "health", Summary: "Reports whether the process is alive", Response: &models.HealthReport{}, Public: true},
+		openapi.Route{Method: http.MethodGet, Path: "/readyz", Tag: "health", Summary: "Reports whether the service and its dependencies are ready", Response: &models.HealthReport{}, OtherResponses: map[int]interface{}{http.StatusServiceUnavailable: &models.HealthReport{}}, Public: true},
+		openapi.Route{Method: http.MethodGet, Path: "/metrics", Tag: "health", Summary: "Prometheus metrics, for callers with the metrics token", ContentType: "text/plain", Public: true},
+
+		// docs
+		openapi.Route{Method: http.MethodGet, Path: "/docs", Tag: "docs", Summary: "Browse this documentation", ContentType: "text/html", Public: true},
+		openapi.Route{Method: http.MethodGet, Path: "/docs/openapi.json", Tag: "docs", Summary: "This document", Public: true},
+	}
+}
+
+// versionedRoutes returns the routes served under the prefix of the given API
+// version, without the prefix.
+func versionedRoutes(version int) []openapi.Route {
+	var ride, rides interface{} = &models.Ride{}, []*models.Ride{}
+	if version >= 2 {
+		ride, rides = &rideV2{}, []*rideV2{}
+	}
+
+	month := func(name string) openapi.Parameter {
+		return openapi.Parameter{Name: name, Description: "month (2006-01) or date (2006-01-02)"}
+	}
+	// list documents a list that can be downloaded in other formats, see
+	// respondList
+	list := func(route openapi.Route) openapi.Route {
+		route.Query = append(route.Query, openapi.Parameter{Name: "format", Description: "json (default), csv, ndjson or xlsx, instead of the Accept header"})
+		for _, format := range []export.Format{export.CSV, export.NDJSON, export.XLSX} {
+			route.AlternateTypes = append(route.AlternateTypes, format.ContentType())
+		}
+		return route
+	}
+	// paged documents a paginated list that can be sorted by the given fields,
+	// see respondPage. Lists in v1 have every row, they are only sorted.
+	paged := func(route openapi.Route, sorts ...string) openapi.Route {
+		route.Query = append(route.Query, openapi.Parameter{Name: "sort", Description: fmt.Sprintf("one of %s, prefixed with - to sort descending (default %s)", strings.Join(sorts[1:], ", "), sorts[0])})
+		if version >= 2 {
+			route.Query = append(route.Query,
+				openapi.Parameter{Name: "limit", Description: fmt.Sprintf("rows per page, %d by default and at most %d; downloads in other formats than json have every row", models.DefaultPageLimit, models.MaxPageLimit), Schema: &openapi.Schema{Type: "integer"}},
+				openapi.Parameter{Name: "cursor", Description: "next cursor of the previous page, also sent in the Link header"},
+			)
+			route.Response = pageOf(route.Response)
+		}
+		return list(route)
+	}
+	since := openapi.Parameter{Name: "since", Description: "RFC3339 timestamp", Schema: &openapi.Schema{Type: "string", Format: "date-time"}}
+	until := openapi.Parameter{Name: "until", Description: "RFC3339 timestamp", Schema: &openapi.Schema{Type: "string", Format: "date-time"}}
+	role := openapi.Parameter{Name: "role", Description: "role of employees"}
+	maintenanceType := openapi.Parameter{Name: "maintenanceType"}
+	status := openapi.Parameter{Name: "status", Description: "open or closed"}
+
+	// sort fields, the default sort first
+	ticketSorts := []string{"-purchasedOn", "purchasedOn", "purchasePrice"}
+	scanSorts := []string{"-scanOn", "scanOn"}
+	maintenanceSorts := []string{"-start", "start", "cost"}
+	eventSorts := []string{"-postedOn", "postedOn", "title"}
+	userSorts := []string{"-registeredOn", "registeredOn", "email"}
+
+	return []openapi.Route{
+		// login
+		openapi.Route{Method: http.MethodPost, Path: "/login", Tag: "login", Summary: "Logs in, or returns a challenge if two-factor authentication is enabled", Request: &loginRequest{}, Response: &keyResponse{}, OtherResponses: map[int]interface{}{http.StatusAccepted: &challengeResponse{}}, Public: true},
+		openapi.Route{Method: http.MethodPost, Path: "/login/two-factor", Tag: "login", Summary: "Finishes logging in with a TOTP or recovery code", Request: &twoFactorLoginRequest{}, Response: &keyResponse{}, Public: true},
+		list(openapi.Route{Method: http.MethodGet, Path: "/login/attempts", Tag: "login", Summary: "Fetches log in attempts", Query: []openapi.Parameter{since, {Name: "email"}}, Response: []*models.LoginAttempt{}}),
+		openapi.Route{Method: http.MethodPost, Path: "/register", Tag: "login", Summary: "Registers a new customer and logs in", Request: &userRequest{}, Response: &keyResponse{}, Status: http.StatusCreated, Public: true},
+		openapi.Route{Method: http.MethodPost, Path: "/logout", Tag: "login", Summary: "Logs out the current session"},
+
+		// sessions
+		openapi.Route{Method: http.MethodGet, Path: "/sessions", Tag: "sessions", Summary: "Fetches the active sessions of the current user", Response: []*models.Session{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/sessions/:sessionID", Tag: "sessions", Summary: "Revokes a session of the current user"},
+
+		// passwords
+		openapi.Route{Method: http.MethodPost, Path: "/users/:userID/password", Tag: "passwords", Summary: "Changes a password, logging out every other session", Request: &passwordChangeRequest{}, Response: &keyResponse{}},
+		openapi.Route{Method: http.MethodPost, Path: "/password/forgot", Tag: "passwords", Summary: "Sends a password reset token", Request: &passwordForgotRequest{}, Status: http.StatusAccepted, Public: true},
+		openapi.Route{Method: http.MethodPost, Path: "/password/reset", Tag: "passwords", Summary: "Resets a password with a reset token", Request: &passwordResetRequest{}, Public: true},
+
+		// two-factor authentication
+		openapi.Route{Method: http.MethodPost, Path: "/users/:userID/two-factor", Tag: "two-factor", Summary: "Starts setting up two-factor authentication", Response: &models.TwoFactorEnrollment{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodPost, Path: "/users/:userID/two-factor/enable", Tag: "two-factor", Summary: "Enables two-factor authentication", Request: &codeRequest{}, Response: &recoveryCodesResponse{}},
+		openapi.Route{Method: http.MethodPost, Path: "/users/:userID/two-factor/recovery-codes", Tag: "two-factor", Summary: "Regenerates recovery codes", Request: &codeRequest{}, Response: &recoveryCodesResponse{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/users/:userID/two-factor", Tag: "two-factor", Summary: "Disables two-factor authentication", Request: &codeRequest{}},
+
+		// roles
+		openapi.Route{Method: http.MethodGet, Path: "/roles", Tag: "roles", Summary: "Fetches roles", Response: []*models.Role{}},
+		openapi.Route{Method: http.MethodPut, Path: "/roles/:roleID", Tag: "roles", Summary: "Updates the policies of a role", Request: &roleRequest{}, Response: &models.Role{}},
+
+		// audit
+		list(openapi.Route{Method: http.MethodGet, Path: "/audit", Tag: "audit", Summary: "Fetches audit entries", Query: []openapi.Parameter{{Name: "actorId"}, {Name: "entityType"}, {Name: "entityId"}, since, until}, Response: []*models.AuditEntry{}}),
+
+		// devices
+		openapi.Route{Method: http.MethodGet, Path: "/devices", Tag: "devices", Summary: "Fetches devices", Response: []*models.Device{}},
+		openapi.Route{Method: http.MethodGet, Path: "/devices/:deviceID", Tag: "devices", Summary: "Fetches a device", Response: &models.Device{}},
+		openapi.Route{Method: http.MethodPost, Path: "/devices", Tag: "devices", Summary: "Creates a device, returning its key", Request: &models.Device{}, Response: &deviceKeyResponse{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodPut, Path: "/devices/:deviceID", Tag: "devices", Summary: "Updates a device", Request: &models.Device{}, Response: &models.Device{}},
+		openapi.Route{Method: http.MethodPost, Path: "/devices/:deviceID/rotate", Tag: "devices", Summary: "Rotates the key of a device", Response: &deviceKeyResponse{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/devices/:deviceID", Tag: "devices", Summary: "Revokes a device"},
+
+		// users
+		paged(openapi.Route{Method: http.MethodGet, Path: "/users", Tag: "users", Summary: "Fetches users", Query: []openapi.Parameter{role}, Response: []*models.User{}}, userSorts...),
+		paged(openapi.Route{Method: http.MethodGet, Path: "/users/customers", Tag: "users", Summary: "Fetches customers", Query: []openapi.Parameter{role}, Response: []*models.User{}}, userSorts...),
+		paged(openapi.Route{Method: http.MethodGet, Path: "/users/employees", Tag: "users", Summary: "Fetches employees", Query: []openapi.Parameter{role}, Response: []*models.User{}}, userSorts...),
+		openapi.Route{Method: http.MethodGet, Path: "/users/:userID", Tag: "users", Summary: "Fetches a user", Response: &models.User{}},
+		openapi.Route{Method: http.MethodPost, Path: "/users", Tag: "users", Summary: "Creates a user", Request: &userRequest{}, Response: &models.User{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodPut, Path: "/users/:userID", Tag: "users", Summary: "Updates a user", Request: &models.User{}, Response: &models.User{}},
+
+		// rides
+		list(openapi.Route{Method: http.MethodGet, Path: "/rides", Tag: "rides", Summary: "Fetches rides", Response: rides}),
+		openapi.Route{Method: http.MethodPost, Path: "/rides", Tag: "rides", Summary: "Creates a ride", Request: ride, Response: ride, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodGet, Path: "/rides/:rideID", Tag: "rides", Summary: "Fetches a ride", Response: ride},
+		openapi.Route{Method: http.MethodPut, Path: "/rides/:rideID", Tag: "rides", Summary: "Updates a ride", Request: ride, Response: ride},
+		openapi.Route{Method: http.MethodDelete, Path: "/rides/:rideID", Tag: "rides", Summary: "Deletes a ride"},
+
+		// reviews
+		list(openapi.Route{Method: http.MethodGet, Path: "/reviews", Tag: "reviews", Summary: "Fetches reviews", Response: []*models.Review{}}),
+		openapi.Route{Method: http.MethodPost, Path: "/reviews", Tag: "reviews", Summary: "Posts a review", Request: &models.Review{}, Response: &models.Review{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodGet, Path: "/reviews/:reviewID", Tag: "reviews", Summary: "Fetches a review", Response: &models.Review{}},
+		openapi.Route{Method: http.MethodPut, Path: "/reviews/:reviewID", Tag: "reviews", Summary: "Updates a review", Request: &models.Review{}, Response: &models.Review{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/reviews/:reviewID", Tag: "reviews", Summary: "Deletes a review"},
+		list(openapi.Route{Method: http.MethodGet, Path: "/rides/:rideID/reviews", Tag: "reviews", Summary: "Fetches the reviews of a ride", Response: []*models.Review{}}),
+
+		// maintenance
+		paged(openapi.Route{Method: http.MethodGet, Path: "/maintenance", Tag: "maintenance", Summary: "Fetches maintenance jobs", Query: []openapi.Parameter{{Name: "rideId"}, maintenanceType, status, since, until}, Response: []*models.Maintenance{}}, maintenanceSorts...),
+		openapi.Route{Method: http.MethodPost, Path: "/maintenance", Tag: "maintenance", Summary: "Begins a maintenance job", Request: &models.Maintenance{}, Response: &models.Maintenance{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodGet, Path: "/maintenance/:maintenanceID", Tag: "maintenance", Summary: "Fetches a maintenance job", Response: &models.Maintenance{}},
+		openapi.Route{Method: http.MethodPut, Path: "/maintenance/:maintenanceID", Tag: "maintenance", Summary: "Updates a maintenance job", Request: &models.Maintenance{}, Response: &models.Maintenance{}},
+		openapi.Route{Method: http.MethodPost, Path: "/maintenance/:maintenanceID/close", Tag: "maintenance", Summary: "Closes a maintenance job", Response: &models.Maintenance{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/maintenance/:maintenanceID", Tag: "maintenance", Summary: "Deletes a maintenance job"},
+		paged(openapi.Route{Method: http.MethodGet, Path: "/rides/:rideID/maintenance", Tag: "maintenance", Summary: "Fetches the maintenance jobs of a ride", Query: []openapi.Parameter{maintenanceType, status, since, until}, Response: []*models.Maintenance{}}, maintenanceSorts...),
+
+		// tickets
+		paged(openapi.Route{Method: http.MethodGet, Path: "/tickets", Tag: "tickets", Summary: "Fetches tickets", Query: []openapi.Parameter{{Name: "userId"}, since, until}, Response: []*models.Ticket{}}, ticketSorts...),
+		openapi.Route{Method: http.MethodPost, Path: "/tickets", Tag: "tickets", Summary: "Buys a ticket for the current user, at the adult or kid price", Request: &models.Ticket{}, Response: &models.Ticket{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodGet, Path: "/tickets/:ticketID", Tag: "tickets", Summary: "Fetches a ticket", Response: &models.Ticket{}},
+		openapi.Route{Method: http.MethodPut, Path: "/tickets/:ticketID", Tag: "tickets", Summary: "Updates a ticket", Request: &models.Ticket{}, Response: &models.Ticket{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/tickets/:ticketID", Tag: "tickets", Summary: "Deletes a ticket"},
+		paged(openapi.Route{Method: http.MethodGet, Path: "/scans", Tag: "tickets", Summary: "Fetches ticket scans", Query: []openapi.Parameter{{Name: "rideId"}, {Name: "userId"}, {Name: "ticketId"}, since, until}, Response: []*models.TicketScan{}}, scanSorts...),
+		openapi.Route{Method: http.MethodPost, Path: "/scans/:ticketID/on/:rideID", Tag: "tickets", Summary: "Scans a ticket on a ride", Response: &models.TicketScan{}, Status: http.StatusCreated},
+		paged(openapi.Route{Method: http.MethodGet, Path: "/users/:userID/tickets", Tag: "tickets", Summary: "Fetches the tickets of a user", Query: []openapi.Parameter{since, until}, Response: []*models.Ticket{}}, ticketSorts...),
+		paged(openapi.Route{Method: http.MethodGet, Path: "/rides/:rideID/scans", Tag: "tickets", Summary: "Fetches the ticket scans on a ride", Query: []openapi.Parameter{{Name: "userId"}, {Name: "ticketId"}, since, until}, Response: []*models.TicketScan{}}, scanSorts...),
+		paged(openapi.Route{Method: http.MethodGet, Path: "/users/:userID/scans", Tag: "tickets", Summary: "Fetches the ticket scans of a user", Query: []openapi.Parameter{{Name: "rideId"}, {Name: "ticketId"}, since, until}, Response: []*models.TicketScan{}}, scanSorts...),
+
+		// events
+		paged(openapi.Route{Method: http.MethodGet, Path: "/events", Tag: "events", Summary: "Fetches events", Query: []openapi.Parameter{{Name: "eventType"}, since, until, {Name: "date", Description: "alias of since", Schema: &openapi.Schema{Type: "string", Format: "date-time"}}}, Response: []*models.Event{}}, eventSorts...),
+		openapi.Route{Method: http.MethodPost, Path: "/events", Tag: "events", Summary: "Posts an event", Request: &models.Event{}, Response: &models.Event{}, Status: http.StatusCreated},
+		openapi.Route{Method: http.MethodGet, Path: "/events/:eventID", Tag: "events", Summary: "Fetches an event", Response: &models.Event{}},
+		openapi.Route{Method: http.MethodPut, Path: "/events/:eventID", Tag: "events", Summary: "Updates an event", Request: &models.Event{}, Response: &models.Event{}},
+		openapi.Route{Method: http.MethodDelete, Path: "/events/:eventID", Tag: "events", Summary: "Deletes an event"},
+
+		// reports
+		openapi.Route{Method: http.MethodGet, Path: "/reports", Tag: "reports", Summary: "Lists the reports", Response: []*reports.Report{}},
+		list(openapi.Route{Method: http.MethodGet, Path: "/reports/:name", Tag: "reports", Summary: "Runs a report, ranged ones can be limited to the months from start to end", Query: []openapi.Parameter{month("start"), month("end")}, Response: []map[string]interface{}{}}),
+	}
+}
+
+// pageOf returns a sample of the page envelope of the given rows, with the
+// type of the rows instead of interface{} so that their schema is documented.
+func pageOf(rows interface{}) interface{} {
+	envelope := reflect.TypeOf(page{})
+	fields := []reflect.StructField{envelope.Field(0), envelope.Field(1)}
+	fields[0].Type = reflect.TypeOf(rows)
+	return reflect.New(reflect.StructOf(fields)).Interface()
+}
@@ -0,0 +1,32 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/handlers"
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+)
+
+func TestDocsFollowRoutes(t *testing.T) {
+	routes := []*echo.Route{
+		{Method: http.MethodGet, Path: "/healthz"},
+		{Method: http.MethodGet, Path: "/v1/rides/:rideID"},
+		{Method: http.MethodGet, Path: "/v1/undescribed"},
+	}
+
+	document, err := handlers.Docs(routes, middlew.Version{Number: 1})
+
+	// routes without a description are still documented, but reported along
+	// with the descriptions of routes that are not registered
+	if assert.NotNil(t, err) {
+		assert.Contains(t, err.Error(), "GET /v1/undescribed")
+		assert.Contains(t, err.Error(), "DELETE /v1/rides/:rideID")
+	}
+
+	assert.Equal(t, []string{"GET /healthz", "GET /v1/rides/:rideID", "GET /v1/undescribed"}, document.Operations())
+	assert.Equal(t, "Fetches a ride", document.Paths["/v1/rides/{rideID}"]["get"].Summary)
+}
@@ -2,9 +2,9 @@ package handlers
 
 import (
 	"net/http"
-	"time"
 
 	"github.com/labstack/echo/v4"
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
@@ -22,12 +22,14 @@ func NewEventHandler(eventUsecase usecases.EventUsecase) *EventHandler {
 }
 
 // Bind sets up the routes for the handler.
-func (eh *EventHandler) Bind(e *echo.Echo) error {
-	e.GET("/events", eh.Fetch)
-	e.POST("/events", eh.Store)
-	e.GET("/events/:eventID", eh.GetByID)
-	e.PUT("/events/:eventID", eh.Update)
-	e.DELETE("/events/:eventID", eh.Delete)
+func (eh *EventHandler) Bind(g *echo.Group) error {
+	employee := middlew.RequireRoles(models.RoleEmployee)
+
+	g.GET("/events", eh.Fetch)
+	g.POST("/events", eh.Store, employee)
+	g.GET("/events/:eventID", eh.GetByID)
+	g.PUT("/events/:eventID", eh.Update, employee)
+	g.DELETE("/events/:eventID", eh.Delete, employee)
 	return nil
 }
 
@@ -38,29 +40,39 @@ func (eh *EventHandler) GetByID(c echo.Context) error {
 
 	event, err := eh.eventUsecase.GetByID(ctx, eventID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, event, Indent)
 }
 
-// Fetch fetches all events.
+// Fetch fetches a page of the events. They can be filtered by type
+// (eventType) and time of posting (since, until as RFC3339; date is kept as
+// an alias of since).
 func (eh *EventHandler) Fetch(c echo.Context) error {
 	ctx := c.Request().Context()
-	day, _ := time.Parse(time.RFC3339, c.QueryParam("date"))
-	var err error
-	var event []*models.Event
-	if len(c.QueryParam("date")) == 0 {
-		event, err = eh.eventUsecase.Fetch(ctx)
-	} else {
-		event, err = eh.eventUsecase.FetchSince(ctx, day)
+
+	page, err := parsePage(c)
+	if err != nil {
+		return err
 	}
 
+	filter := &models.EventFilter{EventType: c.QueryParam("eventType")}
+	filter.Since, filter.Until, err = parseTimeRange(c)
 	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, event, Indent)
+	if date := c.QueryParam("date"); len(date) > 0 && !filter.Since.Valid {
+		filter.Since, err = parseNullTime(date)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "date must be a RFC3339 timestamp")
+		}
+	}
+
+	return respondPage(c, "events", page, func(page *models.PageRequest) (interface{}, string, error) {
+		return eh.eventUsecase.Fetch(ctx, filter, page)
+	})
 }
 
 // Store creates a new event.
@@ -71,12 +83,12 @@ func (eh *EventHandler) Store(c echo.Context) error {
 
 	err := c.Bind(event)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	err = eh.eventUsecase.Store(ctx, event)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusCreated, event, Indent)
@@ -92,12 +104,15 @@ func (eh *EventHandler) Update(c echo.Context) error {
 
 	err := c.Bind(event)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
+	// the body must not change which event is updated
+	event.ID = eventID
+
 	err = eh.eventUsecase.Update(ctx, event)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, event, Indent)
@@ -110,7 +125,7 @@ func (eh *EventHandler) Delete(c echo.Context) error {
 
 	err := eh.eventUsecase.Delete(ctx, eventID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, "", Indent)
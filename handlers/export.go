@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/export"
+)
+
+// respondList responds with the given list in the format asked for by the
+// format query parameter or the Accept header (see export.Negotiate). Other
+// formats than JSON are sent as a download named after the given name, e.g.
+// rides.csv.
+func respondList(c echo.Context, name string, rows interface{}) error {
+	format, err := export.Negotiate(c.QueryParam("format"), c.Request().Header.Get(echo.HeaderAccept))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if format == export.JSON {
+		return c.JSONPretty(http.StatusOK, rows, Indent)
+	}
+
+	return download(c, name, format, rows, nil)
+}
+
+// download responds with the given rows as a download in the given format,
+// followed by the rows returned by next, if given, see writeBatches.
+func download(c echo.Context, name string, format export.Format, rows interface{}, next func() (interface{}, error)) error {
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, format.ContentType())
+	header.Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", name+"."+string(format)))
+	c.Response().WriteHeader(http.StatusOK)
+
+	writer, err := export.NewWriter(c.Response(), format, name)
+	if err != nil {
+		return err
+	}
+
+	return writeBatches(c, writer, rows, next)
+}
+
+// streamJSON responds with the given rows, followed by the rows returned by
+// next, as a single JSON array, see writeBatches.
+func streamJSON(c echo.Context, rows interface{}, next func() (interface{}, error)) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSONCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+
+	return writeBatches(c, export.NewJSONWriter(c.Response(), Indent), rows, next)
+}
+
+// writeBatches writes the given rows with the given writer, followed by the
+// rows returned by next, if given, until it returns no rows. Each batch of
+// rows is flushed before the next one is asked for, so that a single batch is
+// held in memory. Once the first batch is written errors can't be responded
+// anymore, the response is then cut short.
+func writeBatches(c echo.Context, writer export.Writer, rows interface{}, next func() (interface{}, error)) error {
+	for rows != nil {
+		err := writer.Write(rows)
+		if err != nil {
+			return err
+		}
+		c.Response().Flush()
+
+		if next == nil {
+			break
+		}
+
+		rows, err = next()
+		if err != nil {
+			return err
+		}
+	}
+
+	return writer.Close()
+}
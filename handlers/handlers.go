@@ -1,18 +1,93 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
 	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
 // Indent is the constant used in JSONPretty responses.
 const Indent = "    "
 
-// Handler is an interface that lets you bind to an echo.Echo instance.
+// Handler is an interface that lets you bind routes to an echo.Group, like the
+// group of an API version.
 type Handler interface {
-	Bind(e *echo.Echo) error
+	Bind(g *echo.Group) error
 }
 
-// ResponseError represents an error response with a (sometimes useful) message.
+// ResponseError represents an error response with a (sometimes useful) message
+// and a stable code that clients can rely on. Validation errors list every
+// invalid field. The request ID can be given when reporting the error.
 type ResponseError struct {
-	Error string `json:"error"`
+	Error     string                `json:"error"`
+	Code      string                `json:"code"`
+	Fields    []usecases.FieldError `json:"fields,omitempty"`
+	RequestID string                `json:"requestId,omitempty"`
+}
+
+// errorStatuses maps usecase error kinds to HTTP statuses.
+var errorStatuses = map[usecases.ErrorKind]int{
+	usecases.KindNotFound:    http.StatusNotFound,
+	usecases.KindConflict:    http.StatusConflict,
+	usecases.KindValidation:  http.StatusUnprocessableEntity,
+	usecases.KindForbidden:   http.StatusForbidden,
+	usecases.KindUnavailable: http.StatusServiceUnavailable,
+}
+
+// ErrorHandler renders the errors returned by handlers and middleware, to be
+// used as the echo.Echo HTTPErrorHandler. Usecase errors use the status and
+// code of their kind, echo errors keep their status, and any other error is an
+// internal server error whose message isn't shown to clients.
+func ErrorHandler(err error, c echo.Context) {
+	if c.Response().Committed {
+		return
+	}
+
+	status := http.StatusInternalServerError
+	response := ResponseError{
+		Error:     "internal server error",
+		Code:      "internal",
+		RequestID: logging.RequestIDFromContext(c.Request().Context()),
+	}
+
+	var domainErr *usecases.Error
+	var httpErr *echo.HTTPError
+	switch {
+	case errors.As(err, &domainErr):
+		if s, ok := errorStatuses[domainErr.Kind]; ok {
+			status = s
+			response.Error = domainErr.Message
+			response.Code = string(domainErr.Kind)
+			response.Fields = domainErr.Fields
+		}
+	case errors.As(err, &httpErr):
+		status = httpErr.Code
+		response.Error = fmt.Sprintf("%v", httpErr.Message)
+		response.Code = statusCode(status)
+	}
+
+	if c.Request().Method == http.MethodHead {
+		err = c.NoContent(status)
+	} else {
+		err = c.JSONPretty(status, response, Indent)
+	}
+	if err != nil {
+		logging.FromContext(c.Request().Context()).Error("writing error response", logging.Fields{"error": err})
+	}
+}
+
+// statusCode returns the error code for the given HTTP status, which is its
+// status text in snake case (e.g. "too_many_requests").
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if len(text) <= 0 {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
 }
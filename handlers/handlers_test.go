@@ -0,0 +1,84 @@
+package handlers_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/handlers"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+func serveError(err error) (*httptest.ResponseRecorder, handlers.ResponseError) {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler
+	e.GET("/rides/:rideID", func(c echo.Context) error {
+		return err
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/rides/ride0", nil))
+
+	response := handlers.ResponseError{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	return rec, response
+}
+
+func TestErrorHandlerMapsKinds(t *testing.T) {
+	tests := []struct {
+		kind   usecases.ErrorKind
+		status int
+	}{
+		{usecases.KindNotFound, http.StatusNotFound},
+		{usecases.KindConflict, http.StatusConflict},
+		{usecases.KindValidation, http.StatusUnprocessableEntity},
+		{usecases.KindForbidden, http.StatusForbidden},
+		{usecases.KindUnavailable, http.StatusServiceUnavailable},
+	}
+
+	for _, test := range tests {
+		err := fmt.Errorf("fetching ride: %w", usecases.Errorf(test.kind, "ride is %s", test.kind))
+		rec, response := serveError(err)
+
+		assert.Equal(t, test.status, rec.Code)
+		assert.Equal(t, string(test.kind), response.Code)
+		assert.Equal(t, fmt.Sprintf("ride is %s", test.kind), response.Error)
+	}
+}
+
+func TestErrorHandlerListsInvalidFields(t *testing.T) {
+	v := usecases.Validator{}
+	v.Required("/title", "")
+	v.MaxLength("/address", "a very long address that doesn't fit", 32)
+	rec, response := serveError(v.Err())
+
+	assert.Equal(t, http.StatusUnprocessableEntity, rec.Code)
+	assert.Equal(t, "validation", response.Code)
+	if assert.Len(t, response.Fields, 2) {
+		assert.Equal(t, usecases.FieldError{Pointer: "/title", Code: usecases.CodeRequired, Message: "title must be non-empty"}, response.Fields[0])
+		assert.Equal(t, "/address", response.Fields[1].Pointer)
+		assert.Equal(t, usecases.CodeTooLong, response.Fields[1].Code)
+	}
+}
+
+func TestErrorHandlerKeepsHTTPErrors(t *testing.T) {
+	rec, response := serveError(echo.NewHTTPError(http.StatusTooManyRequests, "slow down"))
+
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "too_many_requests", response.Code)
+	assert.Equal(t, "slow down", response.Error)
+}
+
+func TestErrorHandlerHidesOtherErrors(t *testing.T) {
+	rec, response := serveError(errors.New("sql: connection refused"))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "internal", response.Code)
+	assert.Equal(t, "internal server error", response.Error)
+}
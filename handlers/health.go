@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// HealthHandler handles HTTP requests for liveness and readiness checks.
+type HealthHandler struct {
+	healthUsecase usecases.HealthUsecase
+}
+
+// NewHealthHandler returns a new HealthHandler instance.
+func NewHealthHandler(healthUsecase usecases.HealthUsecase) *HealthHandler {
+	return &HealthHandler{
+		healthUsecase,
+	}
+}
+
+// Bind sets up the routes for the handler. The routes must not require a key,
+// so that they can be used by the orchestrator.
+func (hh *HealthHandler) Bind(g *echo.Group) error {
+	g.GET("/healthz", hh.Live)
+	g.GET("/readyz", hh.Ready)
+	return nil
+}
+
+// Live reports whether the process is alive.
+func (hh *HealthHandler) Live(c echo.Context) error {
+	ctx := c.Request().Context()
+	return c.JSONPretty(http.StatusOK, hh.healthUsecase.Live(ctx), Indent)
+}
+
+// Ready reports whether the service and its dependencies are ready, with the
+// status of each check. Responds with 503 if any check failed.
+func (hh *HealthHandler) Ready(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	report := hh.healthUsecase.Ready(ctx)
+	if !report.IsOK() {
+		return c.JSONPretty(http.StatusServiceUnavailable, report, Indent)
+	}
+
+	return c.JSONPretty(http.StatusOK, report, Indent)
+}
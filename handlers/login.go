@@ -3,60 +3,379 @@ package handlers
 import (
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/cache"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/throttle"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 
 	"github.com/labstack/echo/v4"
 	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
 )
 
+// msgInvalidCredentials is the only error returned for failed log ins, so that
+// it does not tell whether an email is registered or not.
+const msgInvalidCredentials = "invalid credentials"
+
+// msgInvalidChallenge is returned when the two-factor challenge is unknown or
+// has expired.
+const msgInvalidChallenge = "invalid or expired challenge, please log in again"
+
+// challengeDuration is how long users have for entering their two-factor code
+// after entering their password.
+const challengeDuration = time.Minute * 5
+
+// challengeCacheSize is the maximum number of pending two-factor challenges.
+const challengeCacheSize = 10000
+
+// challengeSize is the number of random bytes in a two-factor challenge.
+const challengeSize = 32
+
+// dummyPasswordHash is compared against when the email is unknown, so that
+// failed log ins take the same time either way.
+var (
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
+)
+
 // LoginHandler handles HTTP requests for log in.
 type LoginHandler struct {
-	keyAuth     *middlew.KeyAuth
-	userUsecase usecases.UserUsecase
+	keyAuth             *middlew.KeyAuth
+	userUsecase         usecases.UserUsecase
+	loginAttemptUsecase usecases.LoginAttemptUsecase
+	twoFactorUsecase    usecases.TwoFactorUsecase
+	throttles           *keyThrottles
+	challenges          *cache.Cache
 }
 
-// NewLoginHandler returns a new LoginHandler instance.
-func NewLoginHandler(keyAuth *middlew.KeyAuth, userUsecase usecases.UserUsecase) *LoginHandler {
-	return &LoginHandler{keyAuth, userUsecase}
+// NewLoginHandler returns a new LoginHandler instance. Failed log ins are
+// throttled per email using accountThrottle, and per client IP using
+// addressThrottle. Users with two-factor authentication enabled must also
+// enter a code, see LoginTwoFactor.
+func NewLoginHandler(
+	keyAuth *middlew.KeyAuth,
+	userUsecase usecases.UserUsecase,
+	loginAttemptUsecase usecases.LoginAttemptUsecase,
+	twoFactorUsecase usecases.TwoFactorUsecase,
+	accountThrottle *throttle.Throttle,
+	addressThrottle *throttle.Throttle) *LoginHandler {
+
+	return &LoginHandler{
+		keyAuth,
+		userUsecase,
+		loginAttemptUsecase,
+		twoFactorUsecase,
+		&keyThrottles{accountThrottle, addressThrottle},
+		cache.New(challengeCacheSize, challengeDuration),
+	}
 }
 
 // Bind creates the required HTTP routes.
-func (lh *LoginHandler) Bind(e *echo.Echo) error {
-	e.POST("/login", lh.Login)
+func (lh *LoginHandler) Bind(g *echo.Group) error {
+	g.POST("/login", lh.Login)
+	g.POST("/login/two-factor", lh.LoginTwoFactor)
+	g.GET("/login/attempts", lh.FetchAttempts, middlew.RequireRoles(models.RoleEmployee))
+	g.POST("/register", lh.Register)
+	g.POST("/logout", lh.Logout)
 	return nil
 }
 
-// Login handles user login.
+// Login handles user login. Every attempt is recorded, and failed attempts
+// are throttled per email and per client IP. If the user has two-factor
+// authentication enabled, a challenge is returned instead of a key, which is
+// exchanged for a key using LoginTwoFactor.
 func (lh *LoginHandler) Login(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	type Credentials struct {
-		Login    string `json:"email"`
-		Password string `json:"password"`
-	}
-
-	credentials := Credentials{}
+	credentials := loginRequest{}
 	err := c.Bind(&credentials)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	credentials.Login = strings.TrimSpace(credentials.Login)
 	credentials.Password = strings.TrimSpace(credentials.Password)
 	if len(credentials.Login) <= 0 || len(credentials.Password) <= 0 {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{"please provide both login and password"}, Indent)
+		return echo.NewHTTPError(http.StatusBadRequest, "please provide both login and password")
 	}
 
+	attempt := &models.LoginAttempt{
+		Email:     credentials.Login,
+		UserAgent: models.NewNullString(c.Request().UserAgent()),
+		Address:   models.NewNullString(c.RealIP()),
+	}
+
+	accountKey := strings.ToLower(credentials.Login)
+	addressKey := c.RealIP()
+
+	allowed, wait := lh.throttles.attempt(accountKey, addressKey)
+	if !allowed {
+		return lh.throttled(c, attempt, wait)
+	}
+
+	failed := false
+	defer func() { lh.throttles.done(accountKey, addressKey, failed) }()
+
 	user, err := lh.userUsecase.GetByEmail(ctx, credentials.Login)
 	if err != nil {
-		return c.JSONPretty(http.StatusUnauthorized, ResponseError{err.Error()}, Indent)
+		compareDummyPassword(credentials.Password)
+		failed = true
+		return lh.loginFailed(c, attempt, "unknown email")
+	}
+
+	attempt.UserID = models.NewNullString(user.ID)
+
+	err = lh.keyAuth.CheckPassword(user, credentials.Password)
+	if err != nil {
+		failed = true
+		return lh.loginFailed(c, attempt, "wrong password")
+	}
+
+	if user.TwoFactorEnabled {
+		challenge, err := crypto.NewToken(challengeSize)
+		if err != nil {
+			return err
+		}
+
+		lh.challenges.Set(challenge, user.ID)
+
+		attempt.Reason = models.NewNullString("two-factor code required")
+		lh.recordAttempt(c, attempt)
+
+		response := &challengeResponse{
+			TwoFactorRequired: true,
+			Challenge:         challenge,
+			ExpiresOn:         time.Now().UTC().Add(challengeDuration),
+		}
+
+		return c.JSONPretty(http.StatusAccepted, response, Indent)
+	}
+
+	return lh.loginSucceeded(c, user, attempt, accountKey)
+}
+
+// LoginTwoFactor handles the second step of the log in for users with
+// two-factor authentication. It takes the challenge returned by Login and a
+// TOTP or recovery code. Failed attempts count towards the same throttles as
+// wrong passwords.
+func (lh *LoginHandler) LoginTwoFactor(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	credentials := twoFactorLoginRequest{}
+	err := c.Bind(&credentials)
+	if err != nil {
+		return err
+	}
+
+	credentials.Challenge = strings.TrimSpace(credentials.Challenge)
+	credentials.Code = strings.TrimSpace(credentials.Code)
+	if len(credentials.Challenge) <= 0 || len(credentials.Code) <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "please provide both challenge and code")
+	}
+
+	userID, ok := lh.challenges.Get(credentials.Challenge)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, msgInvalidChallenge)
+	}
+
+	user, err := lh.userUsecase.GetByID(ctx, userID.(string))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnauthorized, msgInvalidChallenge)
+	}
+
+	attempt := &models.LoginAttempt{
+		Email:     user.Email,
+		UserID:    models.NewNullString(user.ID),
+		UserAgent: models.NewNullString(c.Request().UserAgent()),
+		Address:   models.NewNullString(c.RealIP()),
+	}
+
+	accountKey := strings.ToLower(user.Email)
+	addressKey := c.RealIP()
+
+	allowed, wait := lh.throttles.attempt(accountKey, addressKey)
+	if !allowed {
+		return lh.throttled(c, attempt, wait)
+	}
+
+	failed := false
+	defer func() { lh.throttles.done(accountKey, addressKey, failed) }()
+
+	err = lh.twoFactorUsecase.Verify(ctx, user.ID, credentials.Code)
+	if err != nil {
+		failed = true
+		return lh.loginFailed(c, attempt, "wrong two-factor code")
+	}
+
+	lh.challenges.Delete(credentials.Challenge)
+	return lh.loginSucceeded(c, user, attempt, accountKey)
+}
+
+// FetchAttempts fetches log in attempts, optionally for a given email, since a
+// given time (last 24 hours by default).
+func (lh *LoginHandler) FetchAttempts(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	since := time.Now().UTC().Add(-time.Hour * 24)
+	if len(c.QueryParam("since")) > 0 {
+		var err error
+		since, err = time.Parse(time.RFC3339, c.QueryParam("since"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, "since must be a RFC3339 timestamp")
+		}
+	}
+
+	var err error
+	var attempts []*models.LoginAttempt
+	if email := c.QueryParam("email"); len(email) > 0 {
+		attempts, err = lh.loginAttemptUsecase.FetchForEmailSince(ctx, email, since)
+	} else {
+		attempts, err = lh.loginAttemptUsecase.FetchSince(ctx, since)
+	}
+
+	if err != nil {
+		return err
 	}
 
-	key, err := lh.keyAuth.Login(ctx, user, credentials.Password)
+	return respondList(c, "login-attempts", attempts)
+}
+
+// loginSucceeded issues a new key for the given user, records the attempt and
+// responds with the key.
+func (lh *LoginHandler) loginSucceeded(c echo.Context, user *models.User, attempt *models.LoginAttempt, accountKey string) error {
+	ctx := c.Request().Context()
+
+	key, err := lh.keyAuth.Issue(ctx, user, c.Request().UserAgent(), c.RealIP())
 	if err != nil {
-		return c.JSONPretty(http.StatusUnauthorized, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, map[string]string{"userId": user.ID, "key": key.Encode()}, Indent)
+	lh.throttles.account.Reset(accountKey)
+
+	attempt.Success = true
+	lh.recordAttempt(c, attempt)
+
+	response := newKeyResponse(lh.keyAuth, key)
+	response.TwoFactorSetupRequired = user.NeedsTwoFactor()
+	return c.JSONPretty(http.StatusOK, response, Indent)
+}
+
+// throttled records the throttled attempt and responds with the time until
+// the client can try again.
+func (lh *LoginHandler) throttled(c echo.Context, attempt *models.LoginAttempt, wait time.Duration) error {
+	attempt.Reason = models.NewNullString("throttled")
+	lh.recordAttempt(c, attempt)
+
+	return tooManyRequests(c, wait, "too many log in attempts, try again later")
+}
+
+// loginFailed records the failed attempt and responds with a generic error.
+func (lh *LoginHandler) loginFailed(c echo.Context, attempt *models.LoginAttempt, reason string) error {
+	attempt.Reason = models.NewNullString(reason)
+	lh.recordAttempt(c, attempt)
+
+	return echo.NewHTTPError(http.StatusUnauthorized, msgInvalidCredentials)
+}
+
+// recordAttempt stores the given attempt. Errors are only logged, as they
+// should not prevent users from logging in.
+func (lh *LoginHandler) recordAttempt(c echo.Context, attempt *models.LoginAttempt) {
+	err := lh.loginAttemptUsecase.Store(c.Request().Context(), attempt)
+	if err != nil {
+		logging.FromContext(c.Request().Context()).Error("error recording log in attempt", logging.Fields{"error": err})
+	}
+}
+
+func compareDummyPassword(password string) {
+	dummyPasswordHashOnce.Do(func() {
+		dummyPasswordHash, _ = crypto.HashFromPassword("dummy-password")
+	})
+
+	crypto.CompareHashAndPassword(dummyPasswordHash, password)
+}
+
+// Register handles self-service registration of customers. On success, the
+// new user is logged in right away.
+func (lh *LoginHandler) Register(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	request := &userRequest{}
+	err := c.Bind(request)
+	if err != nil {
+		return err
+	}
+
+	user := &request.User
+	err = lh.userUsecase.Register(ctx, user, request.Password)
+	if err != nil {
+		return err
+	}
+
+	key, err := lh.keyAuth.Issue(ctx, user, c.Request().UserAgent(), c.RealIP())
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusCreated, newKeyResponse(lh.keyAuth, key), Indent)
+}
+
+// Logout handles user logout by revoking the session of the current key.
+func (lh *LoginHandler) Logout(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	key, ok := middlew.KeyFromContext(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "not logged in")
+	}
+
+	err := lh.keyAuth.Logout(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, "", Indent)
+}
+
+// loginRequest represents the JSON request to log in with an email and a
+// password.
+type loginRequest struct {
+	Login    string `json:"email"`
+	Password string `json:"password"`
+}
+
+// twoFactorLoginRequest represents the JSON request to finish logging in with
+// a challenge and a TOTP or recovery code.
+type twoFactorLoginRequest struct {
+	Challenge string `json:"challenge"`
+	Code      string `json:"code"`
+}
+
+// keyResponse represents the JSON response for a successful log in. If
+// TwoFactorSetupRequired is set, the key can only be used for enabling
+// two-factor authentication until it's enabled.
+type keyResponse struct {
+	UserID                 string    `json:"userId"`
+	Key                    string    `json:"key"`
+	ExpiresOn              time.Time `json:"expiresOn"`
+	TwoFactorSetupRequired bool      `json:"twoFactorSetupRequired,omitempty"`
+}
+
+// challengeResponse represents the JSON response for the first step of a log
+// in with two-factor authentication.
+type challengeResponse struct {
+	TwoFactorRequired bool      `json:"twoFactorRequired"`
+	Challenge         string    `json:"challenge"`
+	ExpiresOn         time.Time `json:"expiresOn"`
+}
+
+func newKeyResponse(keyAuth *middlew.KeyAuth, key crypto.Key) *keyResponse {
+	return &keyResponse{
+		UserID:    key.Login,
+		Key:       keyAuth.Encode(key),
+		ExpiresOn: key.ExpiresAt,
+	}
 }
@@ -5,6 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
@@ -22,14 +23,17 @@ func NewMaintenanceHandler(maintenanceUsecase usecases.MaintenanceUsecase) *Main
 }
 
 // Bind sets up the routes for the handler.
-func (mh *MaintenanceHandler) Bind(e *echo.Echo) error {
-	e.GET("/maintenance", mh.Fetch)
-	e.POST("/maintenance", mh.Store)
-	e.GET("/maintenance/:maintenanceID", mh.GetByID)
-	e.PUT("/maintenance/:maintenanceID", mh.Update)
-	e.POST("/maintenance/:maintenanceID/close", mh.Close)
-	e.DELETE("/maintenance/:maintenanceID", mh.Delete)
-	e.GET("/rides/:rideID/maintenance", mh.FetchForRide)
+func (mh *MaintenanceHandler) Bind(g *echo.Group) error {
+	employee := middlew.RequireRoles(models.RoleEmployee)
+	supervisor := middlew.RequireRoles(models.RoleSupervisor)
+
+	g.GET("/maintenance", mh.Fetch, employee)
+	g.POST("/maintenance", mh.Store, employee)
+	g.GET("/maintenance/:maintenanceID", mh.GetByID, employee)
+	g.PUT("/maintenance/:maintenanceID", mh.Update, employee)
+	g.POST("/maintenance/:maintenanceID/close", mh.Close, employee)
+	g.DELETE("/maintenance/:maintenanceID", mh.Delete, supervisor)
+	g.GET("/rides/:rideID/maintenance", mh.FetchForRide, employee)
 	return nil
 }
 
@@ -40,35 +44,47 @@ func (mh *MaintenanceHandler) GetByID(c echo.Context) error {
 
 	maintenance, err := mh.maintenanceUsecase.GetByID(ctx, maintenanceID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, maintenance, Indent)
 }
 
-// Fetch fetches all maintenance.
+// Fetch fetches a page of the maintenance jobs. They can be filtered by ride
+// (rideId), type (maintenanceType), status (open or closed) and start time
+// (since, until as RFC3339).
 func (mh *MaintenanceHandler) Fetch(c echo.Context) error {
+	return mh.fetch(c, c.QueryParam("rideId"))
+}
+
+// FetchForRide fetches a page of the maintenance jobs for the given ride,
+// filtered like Fetch.
+func (mh *MaintenanceHandler) FetchForRide(c echo.Context) error {
+	return mh.fetch(c, c.Param("rideID"))
+}
+
+func (mh *MaintenanceHandler) fetch(c echo.Context, rideID string) error {
 	ctx := c.Request().Context()
 
-	maintenance, err := mh.maintenanceUsecase.Fetch(ctx)
+	page, err := parsePage(c)
 	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, maintenance, Indent)
-}
-
-// FetchForRide fetches all maintenance jobs for the given ride.
-func (mh *MaintenanceHandler) FetchForRide(c echo.Context) error {
-	ctx := c.Request().Context()
-	rideID := c.Param("rideID")
+	filter := &models.MaintenanceFilter{
+		RideID:          rideID,
+		MaintenanceType: c.QueryParam("maintenanceType"),
+		Status:          c.QueryParam("status"),
+	}
 
-	maintenance, err := mh.maintenanceUsecase.FetchForRide(ctx, rideID)
+	filter.Since, filter.Until, err = parseTimeRange(c)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, maintenance, Indent)
+	return respondPage(c, "maintenance", page, func(page *models.PageRequest) (interface{}, string, error) {
+		return mh.maintenanceUsecase.Fetch(ctx, filter, page)
+	})
 }
 
 // Store creates a new maintenance.
@@ -79,12 +95,12 @@ func (mh *MaintenanceHandler) Store(c echo.Context) error {
 
 	err := c.Bind(maintenance)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	err = mh.maintenanceUsecase.Begin(ctx, maintenance)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusCreated, maintenance, Indent)
@@ -100,12 +116,15 @@ func (mh *MaintenanceHandler) Update(c echo.Context) error {
 
 	err := c.Bind(maintenance)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
+	// the body must not change which maintenance job is updated
+	maintenance.ID = maintenanceID
+
 	err = mh.maintenanceUsecase.Update(ctx, maintenance)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, maintenance, Indent)
@@ -122,15 +141,18 @@ func (mh *MaintenanceHandler) Close(c echo.Context) error {
 
 	err := c.Bind(maintenance)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
+	// the body must not change which maintenance job is closed
+	maintenance.ID = maintenanceID
+
 	maintenance, err = mh.maintenanceUsecase.Close(ctx, maintenance.ID)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusCreated, maintenance, Indent)
+	return c.JSONPretty(http.StatusOK, maintenance, Indent)
 }
 
 // Delete deletes a specific maintenance.
@@ -140,7 +162,7 @@ func (mh *MaintenanceHandler) Delete(c echo.Context) error {
 
 	err := mh.maintenanceUsecase.Delete(ctx, maintenanceID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, "", Indent)
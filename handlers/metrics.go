@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// errMetricsDisabled is returned for metrics when no token is configured.
+var errMetricsDisabled = echo.NewHTTPError(http.StatusNotFound, "metrics are not enabled")
+
+// errInvalidMetricsToken is returned for metrics when the token is missing or
+// wrong.
+var errInvalidMetricsToken = echo.NewHTTPError(http.StatusUnauthorized, "invalid or missing metrics token")
+
+// MetricsHandler handles HTTP requests for Prometheus metrics.
+type MetricsHandler struct {
+	gatherer prometheus.Gatherer
+	token    string
+}
+
+// NewMetricsHandler returns a new MetricsHandler instance that exposes the
+// metrics from the given gatherer to callers sending the given token, as a
+// bearer token in the Authorization header. Without a token, metrics are not
+// served at all.
+func NewMetricsHandler(gatherer prometheus.Gatherer, token string) *MetricsHandler {
+	return &MetricsHandler{
+		gatherer,
+		token,
+	}
+}
+
+// Bind sets up the routes for the handler.
+func (mh *MetricsHandler) Bind(g *echo.Group) error {
+	g.GET("/metrics", echo.WrapHandler(promhttp.HandlerFor(mh.gatherer, promhttp.HandlerOpts{})), mh.requireToken)
+	return nil
+}
+
+// requireToken only lets through requests carrying the metrics token.
+func (mh *MetricsHandler) requireToken(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if len(mh.token) <= 0 {
+			return errMetricsDisabled
+		}
+
+		auth := c.Request().Header.Get(echo.HeaderAuthorization)
+		token := strings.TrimPrefix(auth, "Bearer ")
+		if token == auth || subtle.ConstantTimeCompare([]byte(token), []byte(mh.token)) != 1 {
+			return errInvalidMetricsToken
+		}
+
+		return next(c)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/handlers"
+)
+
+func serveMetrics(token, authorization string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler
+	handlers.NewMetricsHandler(prometheus.NewRegistry(), token).Bind(e.Group(""))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if len(authorization) > 0 {
+		req.Header.Set(echo.HeaderAuthorization, authorization)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestMetricsRequireToken(t *testing.T) {
+	token := "0123456789abcdef"
+
+	assert.Equal(t, http.StatusOK, serveMetrics(token, "Bearer "+token).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveMetrics(token, "Bearer fedcba9876543210").Code)
+	assert.Equal(t, http.StatusUnauthorized, serveMetrics(token, token).Code)
+	assert.Equal(t, http.StatusUnauthorized, serveMetrics(token, "").Code)
+	assert.Equal(t, http.StatusNotFound, serveMetrics("", "Bearer ").Code)
+}
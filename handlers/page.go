@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"database/sql"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/export"
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// page is the envelope of lists since v2: a page of rows, and the cursor of
+// the next page, null on the last one.
+type page struct {
+	Data interface{}       `json:"data"`
+	Next models.NullString `json:"next"`
+}
+
+// parsePage parses the limit, sort and cursor query parameters of lists.
+func parsePage(c echo.Context) (*models.PageRequest, error) {
+	page := models.NewPageRequest()
+	page.Sort = c.QueryParam("sort")
+	page.Cursor = c.QueryParam("cursor")
+
+	if limit := c.QueryParam("limit"); len(limit) > 0 {
+		var err error
+		page.Limit, err = strconv.Atoi(limit)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "limit must be an integer")
+		}
+	}
+
+	return page, nil
+}
+
+// parseTimeRange parses the since and until query parameters, as RFC3339
+// timestamps.
+func parseTimeRange(c echo.Context) (models.NullTime, models.NullTime, error) {
+	since, err := parseNullTime(c.QueryParam("since"))
+	if err != nil {
+		return since, since, echo.NewHTTPError(http.StatusBadRequest, "since must be a RFC3339 timestamp")
+	}
+
+	until, err := parseNullTime(c.QueryParam("until"))
+	if err != nil {
+		return since, until, echo.NewHTTPError(http.StatusBadRequest, "until must be a RFC3339 timestamp")
+	}
+
+	return since, until, nil
+}
+
+// parseNullTime parses the given RFC3339 timestamp, an empty value is null.
+func parseNullTime(value string) (models.NullTime, error) {
+	if len(value) <= 0 {
+		return models.NullTime{}, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return models.NullTime{}, err
+	}
+
+	return models.FromSQLNullTime(sql.NullTime{Time: t, Valid: true}), nil
+}
+
+// fetchPage fetches the given page of a list, and returns its rows (a slice)
+// and the cursor of the next page, if any.
+type fetchPage func(page *models.PageRequest) (interface{}, string, error)
+
+// respondPage responds with the given page of a list, fetched with the given
+// function, like respondList. Since v2, JSON responses are wrapped in a page
+// envelope, with the cursor of the next page, if any, also sent as a Link
+// header. Lists in v1, which is deprecated, and downloads (every other format)
+// aren't paginated: they have every row, see allPages. v1 lists start from the
+// first row, downloads from the given page.
+func respondPage(c echo.Context, name string, request *models.PageRequest, fetch fetchPage) error {
+	format, err := export.Negotiate(c.QueryParam("format"), c.Request().Header.Get(echo.HeaderAccept))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+
+	if format != export.JSON {
+		rows, next, err := allPages(request, fetch)
+		if err != nil {
+			return err
+		}
+
+		return download(c, name, format, rows, next)
+	}
+
+	if middlew.VersionFromContext(c) < 2 {
+		request.Cursor = ""
+		rows, next, err := allPages(request, fetch)
+		if err != nil {
+			return err
+		}
+
+		return streamJSON(c, rows, next)
+	}
+
+	rows, next, err := fetch(request)
+	if err != nil {
+		return err
+	}
+
+	if len(next) > 0 {
+		u := *c.Request().URL
+		query := u.Query()
+		query.Set("cursor", next)
+		u.RawQuery = query.Encode()
+		c.Response().Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", u.RequestURI()))
+	}
+
+	return c.JSONPretty(http.StatusOK, &page{rows, models.NewNullString(next)}, Indent)
+}
+
+// allPages fetches the given page of a list, and returns its rows along with
+// a function returning the rows of the following pages, one page at a time,
+// and no rows after the last one (see writeBatches). Pages are the largest
+// allowed, whatever the limit of the given page. The first page is fetched
+// before anything is written, so that errors like invalid filters still get
+// an error response.
+func allPages(request *models.PageRequest, fetch fetchPage) (interface{}, func() (interface{}, error), error) {
+	page := *request
+	page.Limit = models.MaxPageLimit
+
+	rows, cursor, err := fetch(&page)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	next := func() (interface{}, error) {
+		if len(cursor) <= 0 {
+			return nil, nil
+		}
+
+		page.Cursor = cursor
+		rows, nextCursor, err := fetch(&page)
+		cursor = nextCursor
+		return rows, err
+	}
+
+	return rows, next, nil
+}
@@ -0,0 +1,119 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/handlers"
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// eventUsecaseStub returns two pages of events, remembering the filter and
+// the last page it was asked for.
+type eventUsecaseStub struct {
+	usecases.EventUsecase
+	filter *models.EventFilter
+	page   *models.PageRequest
+}
+
+func (eu *eventUsecaseStub) Fetch(ctx context.Context, filter *models.EventFilter, page *models.PageRequest) ([]*models.Event, string, error) {
+	eu.filter, eu.page = filter, page
+	if page.Cursor == "cursor1" {
+		return []*models.Event{{ID: "event1", Title: "Fireworks"}}, "", nil
+	}
+	return []*models.Event{{ID: "event0", Title: "Rainout"}}, "cursor1", nil
+}
+
+func serveEvents(target string) (*httptest.ResponseRecorder, *eventUsecaseStub) {
+	e := echo.New()
+	e.HTTPErrorHandler = handlers.ErrorHandler
+	e.Use(middlew.APIVersions(middlew.Version{Number: 1}, middlew.Version{Number: 2}))
+
+	stub := &eventUsecaseStub{}
+	for _, prefix := range []string{"/v1", "/v2"} {
+		handlers.NewEventHandler(stub).Bind(e.Group(prefix))
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec, stub
+}
+
+func TestPagesAreWrappedSinceV2(t *testing.T) {
+	rec, stub := serveEvents("/v2/events?limit=1&sort=title&since=2020-03-01T00:00:00Z&eventType=Rainout")
+	if !assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String()) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, &models.PageRequest{Limit: 1, Sort: "title"}, stub.page)
+	assert.Equal(t, "Rainout", stub.filter.EventType)
+	assert.True(t, stub.filter.Since.Valid)
+	assert.Equal(t, `</v2/events?cursor=cursor1&eventType=Rainout&limit=1&since=2020-03-01T00%3A00%3A00Z&sort=title>; rel="next"`, rec.Header().Get("Link"))
+
+	response := struct {
+		Data []*models.Event `json:"data"`
+		Next string          `json:"next"`
+	}{}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	assert.Equal(t, "cursor1", response.Next)
+	if assert.Len(t, response.Data, 1) {
+		assert.Equal(t, "event0", response.Data[0].ID)
+	}
+}
+
+func TestListsAreCompleteInV1(t *testing.T) {
+	rec, stub := serveEvents("/v1/events?limit=1&cursor=cursor1")
+	if !assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String()) {
+		t.FailNow()
+	}
+
+	// every page is fetched from the first one, whatever the limit and cursor
+	assert.Equal(t, &models.PageRequest{Limit: models.MaxPageLimit, Cursor: "cursor1"}, stub.page)
+	assert.Empty(t, rec.Header().Get("Link"))
+
+	events := []*models.Event{}
+	assert.Nil(t, json.Unmarshal(rec.Body.Bytes(), &events))
+	if assert.Len(t, events, 2) {
+		assert.Equal(t, "event0", events[0].ID)
+		assert.Equal(t, "event1", events[1].ID)
+	}
+}
+
+func TestPageParametersAreParsed(t *testing.T) {
+	for _, target := range []string{"/v2/events?limit=ten", "/v2/events?since=yesterday", "/v2/events?date=yesterday"} {
+		rec, _ := serveEvents(target)
+		assert.Equal(t, http.StatusBadRequest, rec.Code, target)
+	}
+}
+
+func TestDownloadsHaveEveryPage(t *testing.T) {
+	rec, stub := serveEvents("/v2/events?limit=1&format=csv")
+	if !assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String()) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, &models.PageRequest{Limit: models.MaxPageLimit, Cursor: "cursor1"}, stub.page)
+	assert.Empty(t, rec.Header().Get("Link"))
+
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if assert.Len(t, lines, 3) {
+		assert.True(t, strings.HasPrefix(lines[1], "event0,"), lines[1])
+		assert.True(t, strings.HasPrefix(lines[2], "event1,"), lines[2])
+	}
+
+	rec, _ = serveEvents("/v1/events?format=ndjson")
+	lines = strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.Contains(t, lines[0], `"event0"`)
+		assert.Contains(t, lines[1], `"event1"`)
+	}
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/throttle"
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// PasswordHandler handles HTTP requests for changing and resetting passwords.
+type PasswordHandler struct {
+	keyAuth         *middlew.KeyAuth
+	passwordUsecase usecases.PasswordUsecase
+	resetThrottles  *keyThrottles
+}
+
+// NewPasswordHandler returns a new PasswordHandler instance. Requests for
+// reset tokens are throttled per email using accountThrottle, and per client
+// IP using addressThrottle; every request counts as a failure, as each sends
+// a message.
+func NewPasswordHandler(
+	keyAuth *middlew.KeyAuth,
+	passwordUsecase usecases.PasswordUsecase,
+	accountThrottle *throttle.Throttle,
+	addressThrottle *throttle.Throttle) *PasswordHandler {
+
+	return &PasswordHandler{
+		keyAuth,
+		passwordUsecase,
+		&keyThrottles{accountThrottle, addressThrottle},
+	}
+}
+
+// Bind sets up the routes for the handler.
+func (ph *PasswordHandler) Bind(g *echo.Group) error {
+	g.POST("/users/:userID/password", ph.Change, middlew.RequireSelfOrRoles("userID"))
+	g.POST("/password/forgot", ph.RequestReset)
+	g.POST("/password/reset", ph.Reset)
+	return nil
+}
+
+// Change changes the password of the current user. Every other session is
+// logged out, and a new key is returned.
+func (ph *PasswordHandler) Change(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := c.Param("userID")
+
+	request := passwordChangeRequest{}
+	err := c.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	user, err := ph.passwordUsecase.Change(ctx, userID, request.CurrentPassword, request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	key, err := ph.keyAuth.Issue(ctx, user, c.Request().UserAgent(), c.RealIP())
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, newKeyResponse(ph.keyAuth, key), Indent)
+}
+
+// RequestReset sends a password reset token to the given email. It always
+// succeeds, so that it cannot be used to find out which emails are registered,
+// unless the email or the client are throttled.
+func (ph *PasswordHandler) RequestReset(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	request := passwordForgotRequest{}
+	err := c.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	accountKey := strings.ToLower(strings.TrimSpace(request.Email))
+	addressKey := c.RealIP()
+
+	allowed, wait := ph.resetThrottles.attempt(accountKey, addressKey)
+	if !allowed {
+		return tooManyRequests(c, wait, "too many password reset requests, try again later")
+	}
+	defer ph.resetThrottles.done(accountKey, addressKey, true)
+
+	err = ph.passwordUsecase.RequestReset(ctx, request.Email)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusAccepted, "", Indent)
+}
+
+// Reset sets a new password using a reset token. Every session of the user is
+// logged out.
+func (ph *PasswordHandler) Reset(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	request := passwordResetRequest{}
+	err := c.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	_, err = ph.passwordUsecase.Reset(ctx, request.Token, request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, "", Indent)
+}
+
+// passwordChangeRequest represents the JSON request to change a password.
+type passwordChangeRequest struct {
+	CurrentPassword string `json:"currentPassword"`
+	NewPassword     string `json:"newPassword"`
+}
+
+// passwordForgotRequest represents the JSON request for a password reset token.
+type passwordForgotRequest struct {
+	Email string `json:"email"`
+}
+
+// passwordResetRequest represents the JSON request to reset a password with a
+// reset token.
+type passwordResetRequest struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"newPassword"`
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/reports"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// reportDateLayouts are the layouts accepted for the range of a report, a
+// month or a day in it.
+var reportDateLayouts = []string{"2006-01", "2006-01-02"}
+
+// ReportHandler handles HTTP requests for reports.
+type ReportHandler struct {
+	reportUsecase usecases.ReportUsecase
+}
+
+// NewReportHandler returns a new ReportHandler instance.
+func NewReportHandler(reportUsecase usecases.ReportUsecase) *ReportHandler {
+	return &ReportHandler{
+		reportUsecase,
+	}
+}
+
+// Bind sets up the routes for the handler.
+func (rh *ReportHandler) Bind(g *echo.Group) error {
+	employee := middlew.RequireRoles(models.RoleEmployee)
+
+	g.GET("/reports", rh.Fetch, employee)
+	g.GET("/reports/:name", rh.Run, employee)
+	return nil
+}
+
+// Fetch lists the reports.
+func (rh *ReportHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+	return c.JSONPretty(http.StatusOK, rh.reportUsecase.Fetch(ctx), Indent)
+}
+
+// Run runs a report. Ranged reports can be limited to the months from start
+// to end (both included, as 2006-01 or 2006-01-02).
+func (rh *ReportHandler) Run(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var err error
+	dateRange := reports.Range{}
+	dateRange.Start, err = parseReportDate(c.QueryParam("start"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "start must be a month (2006-01) or a date (2006-01-02)")
+	}
+
+	dateRange.End, err = parseReportDate(c.QueryParam("end"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "end must be a month (2006-01) or a date (2006-01-02)")
+	}
+
+	rows, err := rh.reportUsecase.Run(ctx, c.Param("name"), dateRange)
+	if err != nil {
+		return err
+	}
+
+	return respondList(c, c.Param("name"), rows)
+}
+
+// parseReportDate parses the given month or date, an empty value is null.
+func parseReportDate(value string) (sql.NullTime, error) {
+	if len(value) <= 0 {
+		return sql.NullTime{}, nil
+	}
+
+	var err error
+	for _, layout := range reportDateLayouts {
+		var t time.Time
+		t, err = time.Parse(layout, value)
+		if err == nil {
+			return sql.NullTime{Time: t, Valid: true}, nil
+		}
+	}
+
+	return sql.NullTime{}, err
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
@@ -22,13 +23,13 @@ func NewReviewHandler(reviewUsecase usecases.ReviewUsecase) *ReviewHandler {
 }
 
 // Bind sets up the routes for the handler.
-func (rh *ReviewHandler) Bind(e *echo.Echo) error {
-	e.GET("/reviews", rh.Fetch)
-	e.POST("/reviews", rh.Store)
-	e.GET("/reviews/:reviewID", rh.GetByID)
-	e.PUT("/reviews/:reviewID", rh.Update)
-	e.DELETE("/reviews/:reviewID", rh.Delete)
-	e.GET("/rides/:rideID/reviews", rh.FetchForRide)
+func (rh *ReviewHandler) Bind(g *echo.Group) error {
+	g.GET("/reviews", rh.Fetch)
+	g.POST("/reviews", rh.Store, middlew.RequireRoles(models.RoleCustomer))
+	g.GET("/reviews/:reviewID", rh.GetByID)
+	g.PUT("/reviews/:reviewID", rh.Update, middlew.RequireRoles(models.RoleCustomer))
+	g.DELETE("/reviews/:reviewID", rh.Delete, middlew.RequireRoles(models.RoleCustomer))
+	g.GET("/rides/:rideID/reviews", rh.FetchForRide)
 	return nil
 }
 
@@ -39,7 +40,7 @@ func (rh *ReviewHandler) GetByID(c echo.Context) error {
 
 	review, err := rh.reviewUsecase.GetByID(ctx, reviewID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, review, Indent)
@@ -51,10 +52,10 @@ func (rh *ReviewHandler) Fetch(c echo.Context) error {
 
 	reviews, err := rh.reviewUsecase.Fetch(ctx)
 	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, reviews, Indent)
+	return respondList(c, "reviews", reviews)
 }
 
 // FetchForRide fetches all reviews for the given ride.
@@ -64,10 +65,10 @@ func (rh *ReviewHandler) FetchForRide(c echo.Context) error {
 
 	reviews, err := rh.reviewUsecase.FetchForRide(ctx, rideID)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, reviews, Indent)
+	return respondList(c, "reviews", reviews)
 }
 
 // Store creates a new review.
@@ -78,12 +79,12 @@ func (rh *ReviewHandler) Store(c echo.Context) error {
 
 	err := c.Bind(review)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	err = rh.reviewUsecase.Store(ctx, review)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusCreated, review, Indent)
@@ -99,12 +100,15 @@ func (rh *ReviewHandler) Update(c echo.Context) error {
 
 	err := c.Bind(review)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
+	// the body must not change which review is updated
+	review.ID = reviewID
+
 	err = rh.reviewUsecase.Update(ctx, review)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, review, Indent)
@@ -117,7 +121,7 @@ func (rh *ReviewHandler) Delete(c echo.Context) error {
 
 	err := rh.reviewUsecase.Delete(ctx, reviewID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, "", Indent)
@@ -5,6 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
@@ -24,12 +25,12 @@ func NewRideHandler(rideUsecase usecases.RideUsecase, maintenanceUsecase usecase
 }
 
 // Bind sets up the routes for the handler.
-func (rh *RideHandler) Bind(e *echo.Echo) error {
-	e.GET("/rides", rh.Fetch)
-	e.POST("/rides", rh.Store)
-	e.GET("/rides/:rideID", rh.GetByID)
-	e.PUT("/rides/:rideID", rh.Update)
-	e.DELETE("/rides/:rideID", rh.Delete)
+func (rh *RideHandler) Bind(g *echo.Group) error {
+	g.GET("/rides", rh.Fetch)
+	g.POST("/rides", rh.Store, middlew.RequireRoles(models.RoleEmployee))
+	g.GET("/rides/:rideID", rh.GetByID)
+	g.PUT("/rides/:rideID", rh.Update, middlew.RequireRoles(models.RoleEmployee))
+	g.DELETE("/rides/:rideID", rh.Delete, middlew.RequireRoles(models.RoleSupervisor))
 	return nil
 }
 
@@ -39,10 +40,10 @@ func (rh *RideHandler) Fetch(c echo.Context) error {
 
 	rides, err := rh.rideUsecase.Fetch(ctx)
 	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, rides, Indent)
+	return respondList(c, "rides", presentRides(c, rides))
 }
 
 // Store creates a new ride.
@@ -51,17 +52,17 @@ func (rh *RideHandler) Store(c echo.Context) error {
 
 	ride := &models.Ride{}
 
-	err := c.Bind(ride)
+	err := bindRide(c, ride)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	err = rh.rideUsecase.Store(ctx, ride)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusCreated, ride, Indent)
+	return c.JSONPretty(http.StatusCreated, presentRide(c, ride), Indent)
 }
 
 // GetByID gets a specific ride.
@@ -71,10 +72,10 @@ func (rh *RideHandler) GetByID(c echo.Context) error {
 
 	ride, err := rh.rideUsecase.GetByID(ctx, rideID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, ride, Indent)
+	return c.JSONPretty(http.StatusOK, presentRide(c, ride), Indent)
 }
 
 // Update updates a specific ride.
@@ -85,17 +86,20 @@ func (rh *RideHandler) Update(c echo.Context) error {
 	ride := &models.Ride{}
 	ride.ID = rideID
 
-	err := c.Bind(ride)
+	err := bindRide(c, ride)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
+	// the body must not change which ride is updated
+	ride.ID = rideID
+
 	err = rh.rideUsecase.Update(ctx, ride)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, ride, Indent)
+	return c.JSONPretty(http.StatusOK, presentRide(c, ride), Indent)
 }
 
 // Delete deletes a specific ride.
@@ -105,8 +109,97 @@ func (rh *RideHandler) Delete(c echo.Context) error {
 
 	err := rh.rideUsecase.Delete(ctx, rideID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, "", Indent)
 }
+
+// rideV2 represents a ride in version 2 of the API, where the location is an
+// object instead of separate longitude and latitude fields.
+type rideV2 struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	Description    string            `json:"description"`
+	MinAge         int               `json:"minAge"`
+	MinHeight      int               `json:"minHeight"`
+	Location       location          `json:"location"`
+	Pictures       []*models.Picture `json:"pictures"`
+	Reviews        []*models.Review  `json:"reviews"`
+	ReviewsAverage int               `json:"reviewsAverage"`
+}
+
+// location represents a point on the map.
+type location struct {
+	Longitude float64 `json:"longitude"`
+	Latitude  float64 `json:"latitude"`
+}
+
+func newRideV2(ride *models.Ride) *rideV2 {
+	return &rideV2{
+		ride.ID,
+		ride.Name,
+		ride.Description,
+		ride.MinAge,
+		ride.MinHeight,
+		location{ride.Longitude, ride.Latitude},
+		ride.Pictures,
+		ride.Reviews,
+		ride.ReviewsAverage,
+	}
+}
+
+func (r *rideV2) model() *models.Ride {
+	return &models.Ride{
+		ID:             r.ID,
+		Name:           r.Name,
+		Description:    r.Description,
+		MinAge:         r.MinAge,
+		MinHeight:      r.MinHeight,
+		Longitude:      r.Location.Longitude,
+		Latitude:       r.Location.Latitude,
+		Pictures:       r.Pictures,
+		Reviews:        r.Reviews,
+		ReviewsAverage: r.ReviewsAverage,
+	}
+}
+
+// bindRide binds the request body to the given ride, using the shape of the
+// API version of the request.
+func bindRide(c echo.Context, ride *models.Ride) error {
+	if middlew.VersionFromContext(c) < 2 {
+		return c.Bind(ride)
+	}
+
+	request := newRideV2(ride)
+	err := c.Bind(request)
+	if err != nil {
+		return err
+	}
+
+	*ride = *request.model()
+	return nil
+}
+
+// presentRide returns the given ride in the shape of the API version of the
+// request.
+func presentRide(c echo.Context, ride *models.Ride) interface{} {
+	if middlew.VersionFromContext(c) < 2 {
+		return ride
+	}
+	return newRideV2(ride)
+}
+
+// presentRides returns the given rides in the shape of the API version of the
+// request.
+func presentRides(c echo.Context, rides []*models.Ride) interface{} {
+	if middlew.VersionFromContext(c) < 2 {
+		return rides
+	}
+
+	response := make([]*rideV2, len(rides))
+	for i, ride := range rides {
+		response[i] = newRideV2(ride)
+	}
+	return response
+}
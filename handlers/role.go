@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// RoleHandler handles HTTP requests for employee roles.
+type RoleHandler struct {
+	roleUsecase usecases.RoleUsecase
+}
+
+// NewRoleHandler returns a new RoleHandler instance.
+func NewRoleHandler(roleUsecase usecases.RoleUsecase) *RoleHandler {
+	return &RoleHandler{
+		roleUsecase,
+	}
+}
+
+// Bind sets up the routes for the handler.
+func (rh *RoleHandler) Bind(g *echo.Group) error {
+	supervisor := middlew.RequireRoles(models.RoleSupervisor)
+
+	g.GET("/roles", rh.Fetch, supervisor)
+	g.PUT("/roles/:roleID", rh.Update, supervisor)
+	return nil
+}
+
+// Fetch fetches all roles.
+func (rh *RoleHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	roles, err := rh.roleUsecase.Fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, roles, Indent)
+}
+
+// Update updates the policies of a specific role. Only whether two-factor
+// authentication is required can be changed.
+func (rh *RoleHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	roleID := c.Param("roleID")
+
+	request := roleRequest{}
+	err := c.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	role, err := rh.roleUsecase.UpdateRequiresTwoFactor(ctx, roleID, request.RequiresTwoFactor)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, role, Indent)
+}
+
+// roleRequest represents the JSON request to update the policies of a role.
+type roleRequest struct {
+	RequiresTwoFactor bool `json:"requiresTwoFactor"`
+}
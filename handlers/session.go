@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// SessionHandler handles HTTP requests for log in sessions.
+type SessionHandler struct {
+	keyAuth        *middlew.KeyAuth
+	sessionUsecase usecases.SessionUsecase
+}
+
+// NewSessionHandler returns a new SessionHandler instance.
+func NewSessionHandler(keyAuth *middlew.KeyAuth, sessionUsecase usecases.SessionUsecase) *SessionHandler {
+	return &SessionHandler{
+		keyAuth,
+		sessionUsecase,
+	}
+}
+
+// Bind sets up the routes for the handler.
+func (sh *SessionHandler) Bind(g *echo.Group) error {
+	g.GET("/sessions", sh.Fetch)
+	g.DELETE("/sessions/:sessionID", sh.Revoke)
+	return nil
+}
+
+// Fetch fetches all active sessions for the current user.
+func (sh *SessionHandler) Fetch(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	key, ok := middlew.KeyFromContext(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "not logged in")
+	}
+
+	sessions, err := sh.sessionUsecase.FetchActiveForUser(ctx, key.Login)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, sessions, Indent)
+}
+
+// Revoke revokes a specific session of the current user.
+func (sh *SessionHandler) Revoke(c echo.Context) error {
+	ctx := c.Request().Context()
+	sessionID := c.Param("sessionID")
+
+	key, ok := middlew.KeyFromContext(c)
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized, "not logged in")
+	}
+
+	session, err := sh.sessionUsecase.GetByID(ctx, sessionID)
+	if err != nil && !usecases.IsKind(err, usecases.KindNotFound) {
+		return err
+	}
+	if err != nil || session.UserID != key.Login {
+		return echo.NewHTTPError(http.StatusNotFound, "session with the given ID does not exists")
+	}
+
+	err = sh.keyAuth.Revoke(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, "", Indent)
+}
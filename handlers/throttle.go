@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/throttle"
+)
+
+// keyThrottles throttle an operation both per account (e.g. an email) and per
+// client IP.
+type keyThrottles struct {
+	account *throttle.Throttle
+	address *throttle.Throttle
+}
+
+// attempt reserves an attempt for both the account and the address, and
+// returns true if neither are throttled. Otherwise it reserves none, and
+// returns false and how long until they can try again. Reserved attempts are
+// ended with done once the operation succeeded or failed.
+func (kt *keyThrottles) attempt(accountKey, addressKey string) (bool, time.Duration) {
+	allowed, wait := kt.account.Attempt(accountKey)
+	if !allowed {
+		return false, wait
+	}
+
+	allowed, wait = kt.address.Attempt(addressKey)
+	if !allowed {
+		kt.account.Done(accountKey, false)
+		return false, wait
+	}
+
+	return true, 0
+}
+
+// done ends the attempts reserved with attempt, recording a failure for both
+// the account and the address if the operation failed.
+func (kt *keyThrottles) done(accountKey, addressKey string, failed bool) {
+	kt.account.Done(accountKey, failed)
+	kt.address.Done(addressKey, failed)
+}
+
+// tooManyRequests responds with the given message and the time until the
+// client can try again.
+func tooManyRequests(c echo.Context, wait time.Duration, message string) error {
+	seconds := int(math.Ceil(wait.Seconds()))
+	c.Response().Header().Set("Retry-After", fmt.Sprintf("%d", seconds))
+	return echo.NewHTTPError(http.StatusTooManyRequests, message)
+}
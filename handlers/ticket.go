@@ -5,6 +5,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
@@ -21,46 +22,62 @@ func NewTicketHandler(ticketUsecase usecases.TicketUsecase) *TicketHandler {
 	}
 }
 
+// DevicePaths are the paths devices are allowed on, see
+// middleware.DeviceAuth. Each must check the scope of the device.
+var DevicePaths = []string{
+	"/scans/:ticketID/on/:rideID",
+}
+
 // Bind sets up the routes for the handler.
-func (th *TicketHandler) Bind(e *echo.Echo) error {
-	e.GET("/tickets", th.Fetch)
-	e.POST("/tickets", th.Store)
-	e.GET("/tickets/:rideID", th.GetByID)
-	e.PUT("/tickets/:rideID", th.Update)
-	e.DELETE("/tickets/:rideID", th.Delete)
-
-	e.GET("/scans", th.FetchScans)
-	e.POST("/scans/:ticketID/on/:rideID", th.StoreScan)
-
-	e.GET("/users/:userID/tickets", th.FetchForUser)
-	e.GET("/rides/:rideID/scans", th.FetchScansForRide)
-	e.GET("/users/:userID/scans", th.FetchScansForUser)
+func (th *TicketHandler) Bind(g *echo.Group) error {
+	employee := middlew.RequireRoles(models.RoleEmployee)
+	supervisor := middlew.RequireRoles(models.RoleSupervisor)
+	selfOrEmployee := middlew.RequireSelfOrRoles("userID", models.RoleEmployee)
+
+	g.GET("/tickets", th.Fetch, employee)
+	g.POST("/tickets", th.Store, middlew.RequireRoles(models.RoleCustomer))
+	g.GET("/tickets/:ticketID", th.GetByID, employee)
+	g.PUT("/tickets/:ticketID", th.Update, supervisor)
+	g.DELETE("/tickets/:ticketID", th.Delete, supervisor)
+
+	g.GET("/scans", th.FetchScans, employee)
+	g.POST("/scans/:ticketID/on/:rideID", th.StoreScan, middlew.RequireRolesOrDeviceScope(models.DeviceScanTickets, "rideID", models.RoleEmployee))
+
+	g.GET("/users/:userID/tickets", th.FetchForUser, selfOrEmployee)
+	g.GET("/rides/:rideID/scans", th.FetchScansForRide, employee)
+	g.GET("/users/:userID/scans", th.FetchScansForUser, selfOrEmployee)
 	return nil
 }
 
-// Fetch fetches all tickets.
+// Fetch fetches a page of the tickets. They can be filtered by user (userId)
+// and time of purchase (since, until as RFC3339).
 func (th *TicketHandler) Fetch(c echo.Context) error {
-	ctx := c.Request().Context()
-
-	tickets, err := th.ticketUsecase.Fetch(ctx)
-	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
-	}
-
-	return c.JSONPretty(http.StatusOK, tickets, Indent)
+	return th.fetch(c, c.QueryParam("userId"))
 }
 
-// FetchForUser fetches all tickets for the given user.
+// FetchForUser fetches a page of the tickets of the given user, filtered like
+// Fetch.
 func (th *TicketHandler) FetchForUser(c echo.Context) error {
+	return th.fetch(c, c.Param("userID"))
+}
+
+func (th *TicketHandler) fetch(c echo.Context, userID string) error {
 	ctx := c.Request().Context()
 
-	userID := c.Param("userID")
-	tickets, err := th.ticketUsecase.FetchForUser(ctx, userID)
+	page, err := parsePage(c)
+	if err != nil {
+		return err
+	}
+
+	filter := &models.TicketFilter{UserID: userID}
+	filter.Since, filter.Until, err = parseTimeRange(c)
 	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, tickets, Indent)
+	return respondPage(c, "tickets", page, func(page *models.PageRequest) (interface{}, string, error) {
+		return th.ticketUsecase.Fetch(ctx, filter, page)
+	})
 }
 
 // Store creates a new ticket.
@@ -71,12 +88,12 @@ func (th *TicketHandler) Store(c echo.Context) error {
 
 	err := c.Bind(ticket)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	err = th.ticketUsecase.Store(ctx, ticket)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusCreated, ticket, Indent)
@@ -89,7 +106,7 @@ func (th *TicketHandler) GetByID(c echo.Context) error {
 
 	ticket, err := th.ticketUsecase.GetByID(ctx, ticketID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, ticket, Indent)
@@ -105,12 +122,15 @@ func (th *TicketHandler) Update(c echo.Context) error {
 
 	err := c.Bind(ticket)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
+	// the body must not change which ticket is updated
+	ticket.ID = ticketID
+
 	err = th.ticketUsecase.Update(ctx, ticket)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, ticket, Indent)
@@ -123,22 +143,17 @@ func (th *TicketHandler) Delete(c echo.Context) error {
 
 	err := th.ticketUsecase.Delete(ctx, ticketID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, "", Indent)
 }
 
-// FetchScans fetches all ticket scans.
+// FetchScans fetches a page of the ticket scans. They can be filtered by ride
+// (rideId), user (userId), ticket (ticketId) and time of the scan (since,
+// until as RFC3339).
 func (th *TicketHandler) FetchScans(c echo.Context) error {
-	ctx := c.Request().Context()
-
-	scans, err := th.ticketUsecase.FetchScans(ctx)
-	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
-	}
-
-	return c.JSONPretty(http.StatusOK, scans, Indent)
+	return th.fetchScans(c, c.QueryParam("rideId"), c.QueryParam("userId"))
 }
 
 // StoreScan creates a new ticket scan.
@@ -149,34 +164,44 @@ func (th *TicketHandler) StoreScan(c echo.Context) error {
 
 	scan, err := th.ticketUsecase.ScanTicket(ctx, ticketID, rideID)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusCreated, scan, Indent)
 }
 
-// FetchScansForRide fetches all scans for the given ride.
+// FetchScansForRide fetches a page of the scans on the given ride, filtered
+// like FetchScans.
 func (th *TicketHandler) FetchScansForRide(c echo.Context) error {
+	return th.fetchScans(c, c.Param("rideID"), c.QueryParam("userId"))
+}
+
+// FetchScansForUser fetches a page of the scans of the given user, filtered
+// like FetchScans.
+func (th *TicketHandler) FetchScansForUser(c echo.Context) error {
+	return th.fetchScans(c, c.QueryParam("rideId"), c.Param("userID"))
+}
+
+func (th *TicketHandler) fetchScans(c echo.Context, rideID, userID string) error {
 	ctx := c.Request().Context()
-	rideID := c.Param("rideID")
 
-	scans, err := th.ticketUsecase.FetchScansForRide(ctx, rideID)
+	page, err := parsePage(c)
 	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, scans, Indent)
-}
-
-// FetchScansForUser fetches all scans for the given user.
-func (th *TicketHandler) FetchScansForUser(c echo.Context) error {
-	ctx := c.Request().Context()
-	userID := c.Param("userID")
+	filter := &models.TicketScanFilter{
+		RideID:   rideID,
+		UserID:   userID,
+		TicketID: c.QueryParam("ticketId"),
+	}
 
-	scans, err := th.ticketUsecase.FetchScansForUser(ctx, userID)
+	filter.Since, filter.Until, err = parseTimeRange(c)
 	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, scans, Indent)
+	return respondPage(c, "scans", page, func(page *models.PageRequest) (interface{}, string, error) {
+		return th.ticketUsecase.FetchScans(ctx, filter, page)
+	})
 }
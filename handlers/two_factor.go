@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// TwoFactorHandler handles HTTP requests for two-factor authentication.
+type TwoFactorHandler struct {
+	twoFactorUsecase usecases.TwoFactorUsecase
+}
+
+// NewTwoFactorHandler returns a new TwoFactorHandler instance.
+func NewTwoFactorHandler(twoFactorUsecase usecases.TwoFactorUsecase) *TwoFactorHandler {
+	return &TwoFactorHandler{
+		twoFactorUsecase,
+	}
+}
+
+// TwoFactorSetupPaths are the paths needed for setting up two-factor
+// authentication, see middleware.RequireTwoFactorSetup.
+var TwoFactorSetupPaths = []string{
+	"/users/:userID/two-factor",
+	"/users/:userID/two-factor/enable",
+}
+
+// Bind sets up the routes for the handler.
+func (th *TwoFactorHandler) Bind(g *echo.Group) error {
+	self := middlew.RequireSelfOrRoles("userID")
+
+	g.POST("/users/:userID/two-factor", th.Enroll, self)
+	g.POST("/users/:userID/two-factor/enable", th.Enable, self)
+	g.POST("/users/:userID/two-factor/recovery-codes", th.RegenerateRecoveryCodes, self)
+	g.DELETE("/users/:userID/two-factor", th.Disable, middlew.RequireSelfOrRoles("userID", models.RoleSupervisor))
+	return nil
+}
+
+// Enroll starts setting up two-factor authentication for the current user.
+// The response holds the secret and the URI for authenticator apps.
+func (th *TwoFactorHandler) Enroll(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := c.Param("userID")
+
+	enrollment, err := th.twoFactorUsecase.Enroll(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusCreated, enrollment, Indent)
+}
+
+// Enable enables two-factor authentication for the current user, using a
+// code from the authenticator app. The response holds the recovery codes,
+// which can't be retrieved again.
+func (th *TwoFactorHandler) Enable(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := c.Param("userID")
+
+	request := codeRequest{}
+	err := c.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	recoveryCodes, err := th.twoFactorUsecase.Enable(ctx, userID, request.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, recoveryCodesResponse{recoveryCodes}, Indent)
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the current user.
+func (th *TwoFactorHandler) RegenerateRecoveryCodes(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := c.Param("userID")
+
+	request := codeRequest{}
+	err := c.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	recoveryCodes, err := th.twoFactorUsecase.RegenerateRecoveryCodes(ctx, userID, request.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, recoveryCodesResponse{recoveryCodes}, Indent)
+}
+
+// Disable disables two-factor authentication for a specific user. Users need
+// a code for disabling their own, supervisors don't for other users.
+func (th *TwoFactorHandler) Disable(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID := c.Param("userID")
+
+	request := codeRequest{}
+	err := c.Bind(&request)
+	if err != nil {
+		return err
+	}
+
+	err = th.twoFactorUsecase.Disable(ctx, userID, request.Code)
+	if err != nil {
+		return err
+	}
+
+	return c.JSONPretty(http.StatusOK, "", Indent)
+}
+
+// codeRequest represents the JSON request holding a TOTP or recovery code.
+type codeRequest struct {
+	Code string `json:"code"`
+}
+
+// recoveryCodesResponse represents the JSON response holding new recovery
+// codes.
+type recoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
 
 	"github.com/labstack/echo/v4"
 
+	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
@@ -22,50 +24,50 @@ func NewUserHandler(userUsecase usecases.UserUsecase) *UserHandler {
 }
 
 // Bind sets up the routes for the handler.
-func (uh *UserHandler) Bind(e *echo.Echo) error {
-	e.GET("/users", uh.Fetch)
-	e.GET("/users/customers", uh.FetchCustomers)
-	e.GET("/users/employees", uh.FetchEmployees)
-	e.GET("/users/:userID", uh.GetByID)
-	e.POST("/users", uh.Store)
-	e.PUT("/users/:userID", uh.Update)
+func (uh *UserHandler) Bind(g *echo.Group) error {
+	employee := middlew.RequireRoles(models.RoleEmployee)
+	supervisor := middlew.RequireRoles(models.RoleSupervisor)
+
+	g.GET("/users", uh.Fetch, employee)
+	g.GET("/users/customers", uh.FetchCustomers, employee)
+	g.GET("/users/employees", uh.FetchEmployees, employee)
+	g.GET("/users/:userID", uh.GetByID, middlew.RequireSelfOrRoles("userID", models.RoleEmployee))
+	g.POST("/users", uh.Store, supervisor)
+	g.PUT("/users/:userID", uh.Update, middlew.RequireSelfOrRoles("userID", models.RoleSupervisor))
 	return nil
 }
 
-// Fetch fetches all users.
+// Fetch fetches a page of the users. They can be filtered by role.
 func (uh *UserHandler) Fetch(c echo.Context) error {
-	ctx := c.Request().Context()
-
-	users, err := uh.userUsecase.Fetch(ctx)
-	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
-	}
-
-	return c.JSONPretty(http.StatusOK, users, Indent)
+	return uh.fetch(c, "users", sql.NullBool{})
 }
 
-// FetchCustomers fetches all customers.
+// FetchCustomers fetches a page of the customers, filtered like Fetch.
 func (uh *UserHandler) FetchCustomers(c echo.Context) error {
-	ctx := c.Request().Context()
-
-	users, err := uh.userUsecase.FetchCustomers(ctx)
-	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
-	}
-
-	return c.JSONPretty(http.StatusOK, users, Indent)
+	return uh.fetch(c, "customers", sql.NullBool{Bool: false, Valid: true})
 }
 
-// FetchEmployees fetches all employees.
+// FetchEmployees fetches a page of the employees, filtered like Fetch.
 func (uh *UserHandler) FetchEmployees(c echo.Context) error {
+	return uh.fetch(c, "employees", sql.NullBool{Bool: true, Valid: true})
+}
+
+func (uh *UserHandler) fetch(c echo.Context, name string, isEmployee sql.NullBool) error {
 	ctx := c.Request().Context()
 
-	users, err := uh.userUsecase.FetchEmployees(ctx)
+	page, err := parsePage(c)
 	if err != nil {
-		return c.JSONPretty(http.StatusInternalServerError, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	return c.JSONPretty(http.StatusOK, users, Indent)
+	filter := &models.UserFilter{
+		IsEmployee: isEmployee,
+		Role:       c.QueryParam("role"),
+	}
+
+	return respondPage(c, name, page, func(page *models.PageRequest) (interface{}, string, error) {
+		return uh.userUsecase.Fetch(ctx, filter, page)
+	})
 }
 
 // GetByID gets a specific user.
@@ -73,9 +75,15 @@ func (uh *UserHandler) GetByID(c echo.Context) error {
 	ctx := c.Request().Context()
 	userID := c.Param("userID")
 
+	// the current user was already resolved when validating the key
+	principal, _ := middlew.PrincipalFromContext(c)
+	if principal.IsUser(userID) {
+		return c.JSONPretty(http.StatusOK, principal.User, Indent)
+	}
+
 	user, err := uh.userUsecase.GetByID(ctx, userID)
 	if err != nil {
-		return c.JSONPretty(http.StatusNotFound, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, user, Indent)
@@ -85,16 +93,17 @@ func (uh *UserHandler) GetByID(c echo.Context) error {
 func (uh *UserHandler) Store(c echo.Context) error {
 	ctx := c.Request().Context()
 
-	user := &models.User{}
+	request := &userRequest{}
 
-	err := c.Bind(user)
+	err := c.Bind(request)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
-	err = uh.userUsecase.Store(ctx, user)
+	user := &request.User
+	err = uh.userUsecase.Store(ctx, user, request.Password)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusCreated, user, Indent)
@@ -110,13 +119,31 @@ func (uh *UserHandler) Update(c echo.Context) error {
 
 	err := c.Bind(user)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
+	}
+
+	// the body must not change which user is updated
+	user.ID = userID
+
+	// only supervisors can change employment details, including their own
+	principal, _ := middlew.PrincipalFromContext(c)
+	if !principal.HasRole(models.RoleSupervisor) {
+		user.IsEmployee = principal.User.IsEmployee
+		user.Role = principal.User.Role
+		user.HourlyRate = principal.User.HourlyRate
 	}
 
 	err = uh.userUsecase.Update(ctx, user)
 	if err != nil {
-		return c.JSONPretty(http.StatusBadRequest, ResponseError{err.Error()}, Indent)
+		return err
 	}
 
 	return c.JSONPretty(http.StatusOK, user, Indent)
 }
+
+// userRequest represents the JSON request for creating a user. Passwords are
+// sent in plain text and hashed by the server.
+type userRequest struct {
+	models.User
+	Password string `json:"password"`
+}
@@ -0,0 +1,128 @@
+// Package cache implements an in-memory cache with a size bound and
+// expiration.
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is a fixed size cache whose entries expire after a given time. When
+// full, the least recently used entry is evicted. Cache is safe for concurrent
+// use.
+type Cache struct {
+	size int
+	ttl  time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+}
+
+// entry is a single value in the cache.
+type entry struct {
+	key        string
+	value      interface{}
+	expiration time.Time
+}
+
+// New returns a new Cache instance holding at most size entries, each for at
+// most the given ttl.
+func New(size int, ttl time.Duration) *Cache {
+	return &Cache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value for the given key, if any and not expired.
+func (c *Cache) Get(key string) (interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := elem.Value.(*entry)
+	if !time.Now().Before(e.expiration) {
+		c.remove(elem)
+		return nil, false
+	}
+
+	c.order.MoveToFront(elem)
+	return e.value, true
+}
+
+// Set stores the given value for the cache ttl.
+func (c *Cache) Set(key string, value interface{}) {
+	c.SetUntil(key, value, time.Now().Add(c.ttl))
+}
+
+// SetUntil stores the given value until the given expiration time, or for the
+// cache ttl if that's sooner.
+func (c *Cache) SetUntil(key string, value interface{}, expiration time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	maxExpiration := time.Now().Add(c.ttl)
+	if expiration.After(maxExpiration) {
+		expiration = maxExpiration
+	}
+
+	if elem, ok := c.entries[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expiration = expiration
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&entry{key, value, expiration})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Delete removes the value for the given key.
+func (c *Cache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		c.remove(elem)
+	}
+}
+
+// DeleteFunc removes every value for which the given function returns true.
+func (c *Cache) DeleteFunc(fn func(key string, value interface{}) bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for elem := c.order.Front(); elem != nil; {
+		next := elem.Next()
+		e := elem.Value.(*entry)
+		if fn(e.key, e.value) {
+			c.remove(elem)
+		}
+		elem = next
+	}
+}
+
+// Len returns the number of entries in the cache, including expired entries
+// that have not been removed yet.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*entry).key)
+}
@@ -0,0 +1,104 @@
+package cache_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/cache"
+)
+
+func TestCacheGetSucceeds(t *testing.T) {
+	c := cache.New(2, time.Minute)
+
+	c.Set("key0", 0)
+	c.Set("key1", 1)
+
+	value, ok := c.Get("key0")
+	assert.True(t, ok)
+	assert.Equal(t, 0, value)
+
+	_, ok = c.Get("key2")
+	assert.False(t, ok)
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := cache.New(2, time.Minute)
+
+	c.Set("key0", 0)
+	c.Set("key1", 1)
+	c.Get("key0")
+	c.Set("key2", 2)
+
+	assert.Equal(t, 2, c.Len())
+
+	_, ok := c.Get("key1")
+	assert.False(t, ok)
+
+	_, ok = c.Get("key0")
+	assert.True(t, ok)
+}
+
+func TestCacheExpires(t *testing.T) {
+	c := cache.New(10, time.Millisecond*20)
+
+	c.Set("key0", 0)
+	c.SetUntil("key1", 1, time.Now().Add(-time.Second))
+	c.SetUntil("key2", 2, time.Now().Add(time.Hour))
+
+	_, ok := c.Get("key1")
+	assert.False(t, ok)
+
+	time.Sleep(time.Millisecond * 30)
+
+	// ttl is the upper bound even if a later expiration is given
+	for _, key := range []string{"key0", "key2"} {
+		_, ok := c.Get(key)
+		assert.False(t, ok)
+	}
+}
+
+func TestCacheDeleteFuncSucceeds(t *testing.T) {
+	c := cache.New(10, time.Minute)
+
+	for i := 0; i < 6; i++ {
+		c.Set(fmt.Sprintf("key%d", i), i)
+	}
+
+	c.Delete("key0")
+	c.DeleteFunc(func(key string, value interface{}) bool {
+		return value.(int)%2 == 1
+	})
+
+	assert.Equal(t, 2, c.Len())
+	for _, key := range []string{"key2", "key4"} {
+		_, ok := c.Get(key)
+		assert.True(t, ok)
+	}
+}
+
+func TestCacheConcurrentUseSucceeds(t *testing.T) {
+	c := cache.New(50, time.Minute)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := fmt.Sprintf("key%d", (i*j)%100)
+				c.Set(key, j)
+				c.Get(key)
+				if j%10 == 0 {
+					c.Delete(key)
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.True(t, c.Len() <= 50)
+}
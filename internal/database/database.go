@@ -0,0 +1,58 @@
+// Package database opens connections to the postgres database.
+package database
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/jackc/pgx/stdlib"
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/config"
+)
+
+// Open returns a new *sqlx.DB instance connected to the database given by the
+// config, with its connection pool set up. Queries are logged using the logger
+// from their context (see logging.FromContext).
+func Open(config *config.DatabaseConfig) (*sqlx.DB, error) {
+	connector := &loggingConnector{config.DataSourceName(), stdlib.GetDefaultDriver()}
+	db := sqlx.NewDb(sql.OpenDB(connector), "pgx")
+
+	err := db.Ping()
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("openDatabase: %s", err)
+	}
+
+	db.SetMaxOpenConns(config.MaxOpenConns)
+	db.SetMaxIdleConns(config.MaxIdleConns)
+	db.SetConnMaxLifetime(config.ConnMaxLifetime)
+
+	return db, nil
+}
+
+// CheckSchema returns an error unless the given schema exists and holds the
+// tables of the migrations, so that a missing schema fails on startup instead
+// of on the first request.
+func CheckSchema(db *sqlx.DB, schema string) error {
+	var exists bool
+	err := db.Get(&exists, "SELECT EXISTS (SELECT 1 FROM information_schema.schemata WHERE schema_name = $1)", schema)
+	if err != nil {
+		return fmt.Errorf("checkSchema: %s", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("checkSchema: schema '%s' does not exist, create it using 'backend migrate up'", schema)
+	}
+
+	err = db.Get(&exists, "SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = 'users')", schema)
+	if err != nil {
+		return fmt.Errorf("checkSchema: %s", err)
+	}
+
+	if !exists {
+		return fmt.Errorf("checkSchema: schema '%s' has no tables, create them using 'backend migrate up'", schema)
+	}
+
+	return nil
+}
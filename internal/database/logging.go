@@ -0,0 +1,85 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"strings"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
+)
+
+// loggedConn is the set of driver interfaces implemented by the pgx
+// connections that loggingConn forwards.
+type loggedConn interface {
+	driver.Conn
+	driver.ConnPrepareContext
+	driver.ConnBeginTx
+	driver.ExecerContext
+	driver.QueryerContext
+	driver.Pinger
+}
+
+// loggingConnector opens connections that log the time taken by each query,
+// and failed queries, using the logger from the query context. Arguments are
+// never logged since they may hold credentials.
+type loggingConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+// Connect implements the driver.Connector interface.
+func (lc *loggingConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := lc.driver.Open(lc.dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if conn, ok := conn.(loggedConn); ok {
+		return &loggingConn{conn}, nil
+	}
+	return conn, nil
+}
+
+// Driver implements the driver.Connector interface.
+func (lc *loggingConnector) Driver() driver.Driver {
+	return lc.driver
+}
+
+type loggingConn struct {
+	loggedConn
+}
+
+func (lc *loggingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	result, err := lc.loggedConn.ExecContext(ctx, query, args)
+	logQuery(ctx, query, start, err)
+	return result, err
+}
+
+func (lc *loggingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	rows, err := lc.loggedConn.QueryContext(ctx, query, args)
+	logQuery(ctx, query, start, err)
+	return rows, err
+}
+
+func logQuery(ctx context.Context, query string, start time.Time, err error) {
+	if err == driver.ErrSkip {
+		return
+	}
+
+	logger := logging.FromContext(ctx)
+	fields := logging.Fields{
+		"query":       strings.Join(strings.Fields(query), " "),
+		"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		fields["error"] = err
+		logger.Error("query failed", fields)
+		return
+	}
+
+	logger.Debug("query", fields)
+}
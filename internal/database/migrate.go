@@ -0,0 +1,327 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx"
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/ddl"
+)
+
+// migrationLockKey is the key of the advisory lock held while migrating, so
+// that only one process migrates at a time.
+const migrationLockKey = 338014
+
+// migrationName matches the names of migration files, e.g. 0001_schema.up.sql.
+var migrationName = regexp.MustCompile(`^([0-9]+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned change to the schema, with the SQL to apply it and
+// to revert it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// MigrationStatus is a migration and when it was applied, if it was.
+type MigrationStatus struct {
+	Migration
+	AppliedOn sql.NullTime
+}
+
+// LoadMigrations returns the migrations in the given directory, ordered by
+// version. Every migration must have both an up and a down file.
+func LoadMigrations(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("loadMigrations: %s", err)
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("loadMigrations: '%s' is not named like 0001_name.up.sql", entry.Name())
+		}
+
+		version, _ := strconv.Atoi(match[1])
+		if version <= 0 {
+			return nil, fmt.Errorf("loadMigrations: '%s' has version 0, versions start at 1", entry.Name())
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("loadMigrations: version %d is used by '%s' and '%s'", version, migration.Name, match[2])
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("loadMigrations: %s", err)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if len(migration.Up) <= 0 || len(migration.Down) <= 0 {
+			return nil, fmt.Errorf("loadMigrations: migration %d_%s must have an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Migrator applies and reverts migrations in a schema, recording the applied
+// versions in its schema_migrations table. Every migration runs in its own
+// transaction, with the search path of the connection (see
+// config.DatabaseConfig.DataSourceName).
+type Migrator struct {
+	db         *sqlx.DB
+	schema     string
+	migrations []Migration
+}
+
+// NewMigrator returns a new Migrator instance for the given schema, which is
+// created if it doesn't exist. The migrations must be ordered by version.
+func NewMigrator(db *sqlx.DB, schema string, migrations []Migration) *Migrator {
+	return &Migrator{
+		db,
+		schema,
+		migrations,
+	}
+}
+
+// NewEmbeddedMigrator returns a new Migrator instance for the given schema,
+// with the migrations embedded in the binary (see package ddl).
+func NewEmbeddedMigrator(db *sqlx.DB, schema string) (*Migrator, error) {
+	migrations, err := LoadMigrations(ddl.Migrations, ddl.MigrationsDir)
+	if err != nil {
+		return nil, err
+	}
+	return NewMigrator(db, schema, migrations), nil
+}
+
+// Latest returns the version of the last migration, or zero if there is none.
+func (m *Migrator) Latest() int {
+	if len(m.migrations) <= 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Up applies every migration up to the given version, included, and returns
+// the ones that were applied.
+func (m *Migrator) Up(ctx context.Context, target int) ([]Migration, error) {
+	applied := []Migration{}
+	err := m.withLock(ctx, func(conn *sql.Conn, versions map[int]time.Time) error {
+		for _, migration := range m.migrations {
+			if migration.Version > target {
+				break
+			}
+			if _, ok := versions[migration.Version]; ok {
+				continue
+			}
+
+			err := m.apply(ctx, conn, migration, migration.Up, "INSERT INTO %s (version, name, applied_on) VALUES ($1, $2, now())")
+			if err != nil {
+				return err
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return applied, fmt.Errorf("migrateUp: %w", err)
+	}
+
+	return applied, nil
+}
+
+// Down reverts every applied migration after the given version, latest first,
+// and returns the ones that were reverted.
+func (m *Migrator) Down(ctx context.Context, target int) ([]Migration, error) {
+	reverted := []Migration{}
+	err := m.withLock(ctx, func(conn *sql.Conn, versions map[int]time.Time) error {
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= target {
+				break
+			}
+			if _, ok := versions[migration.Version]; !ok {
+				continue
+			}
+
+			err := m.apply(ctx, conn, migration, migration.Down, "DELETE FROM %s WHERE version = $1 AND name = $2")
+			if err != nil {
+				return err
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return reverted, fmt.Errorf("migrateDown: %w", err)
+	}
+
+	return reverted, nil
+}
+
+// Status returns every migration, and when it was applied.
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	statuses := make([]MigrationStatus, len(m.migrations))
+	err := m.withLock(ctx, func(conn *sql.Conn, versions map[int]time.Time) error {
+		for i, migration := range m.migrations {
+			appliedOn, ok := versions[migration.Version]
+			statuses[i] = MigrationStatus{migration, sql.NullTime{Time: appliedOn, Valid: ok}}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("migrationStatus: %w", err)
+	}
+
+	return statuses, nil
+}
+
+// Previous returns the version of the migration applied before the latest
+// applied one, or zero if there is none, which is the target to revert a
+// single migration.
+func (m *Migrator) Previous(ctx context.Context) (int, error) {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	previous, latest := 0, 0
+	for _, status := range statuses {
+		if status.AppliedOn.Valid {
+			previous, latest = latest, status.Version
+		}
+	}
+
+	return previous, nil
+}
+
+// withLock calls fn on a single connection holding the migration lock, with
+// the applied versions and when they were applied. The migrations table is
+// created if needed, and fn isn't called if the database has versions that
+// are unknown to the migrator, as it's newer than this binary.
+func (m *Migrator) withLock(ctx context.Context, fn func(*sql.Conn, map[int]time.Time) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	_, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLockKey)
+	if err != nil {
+		return fmt.Errorf("locking: %s", err)
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrationLockKey)
+
+	schema := pgx.Identifier{m.schema}.Sanitize()
+	_, err = conn.ExecContext(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema))
+	if err != nil {
+		return fmt.Errorf("creating schema: %s", err)
+	}
+
+	_, err = conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		version integer NOT NULL,
+		name varchar(64) NOT NULL,
+		applied_on timestamp NOT NULL,
+		PRIMARY KEY (version)
+	)`, m.table()))
+	if err != nil {
+		return fmt.Errorf("creating migrations table: %s", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, fmt.Sprintf("SELECT version, applied_on FROM %s", m.table()))
+	if err != nil {
+		return fmt.Errorf("fetching versions: %s", err)
+	}
+	defer rows.Close()
+
+	known := make(map[int]bool, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = true
+	}
+
+	versions := map[int]time.Time{}
+	for rows.Next() {
+		var version int
+		var appliedOn time.Time
+		err = rows.Scan(&version, &appliedOn)
+		if err != nil {
+			return fmt.Errorf("fetching versions: %s", err)
+		}
+
+		if !known[version] {
+			return fmt.Errorf("version %d is applied but unknown, the database is newer than this binary", version)
+		}
+		versions[version] = appliedOn
+	}
+
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("fetching versions: %s", err)
+	}
+	rows.Close()
+
+	return fn(conn, versions)
+}
+
+// apply runs the given SQL of a migration and records it with the given
+// statement, in a transaction.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, migration Migration, query, record string) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("migration %d_%s: %s", migration.Version, migration.Name, err)
+	}
+
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(record, m.table()), migration.Version, migration.Name)
+	if err != nil {
+		return fmt.Errorf("migration %d_%s: %s", migration.Version, migration.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+func (m *Migrator) table() string {
+	return pgx.Identifier{m.schema, "schema_migrations"}.Sanitize()
+}
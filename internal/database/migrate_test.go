@@ -0,0 +1,59 @@
+package database_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/ddl"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/database"
+)
+
+func TestLoadMigrationsOrdersByVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"migrations/0010_vendors.up.sql":   {Data: []byte("CREATE TABLE vendors ();")},
+		"migrations/0010_vendors.down.sql": {Data: []byte("DROP TABLE vendors;")},
+		"migrations/0002_rides.up.sql":     {Data: []byte("CREATE TABLE rides ();")},
+		"migrations/0002_rides.down.sql":   {Data: []byte("DROP TABLE rides;")},
+	}
+
+	migrations, err := database.LoadMigrations(fsys, "migrations")
+	if assert.Nil(t, err) && assert.Len(t, migrations, 2) {
+		assert.Equal(t, database.Migration{Version: 2, Name: "rides", Up: "CREATE TABLE rides ();", Down: "DROP TABLE rides;"}, migrations[0])
+		assert.Equal(t, 10, migrations[1].Version)
+		assert.Equal(t, "vendors", migrations[1].Name)
+	}
+}
+
+func TestLoadMigrationsFails(t *testing.T) {
+	tests := []fstest.MapFS{
+		// no down file
+		{"migrations/0001_rides.up.sql": {Data: []byte("CREATE TABLE rides ();")}},
+		// same version, different names
+		{
+			"migrations/0001_rides.up.sql":     {Data: []byte("CREATE TABLE rides ();")},
+			"migrations/0001_vendors.down.sql": {Data: []byte("DROP TABLE vendors;")},
+		},
+		// not a migration
+		{"migrations/rides.sql": {Data: []byte("CREATE TABLE rides ();")}},
+		// version 0
+		{
+			"migrations/0000_rides.up.sql":   {Data: []byte("CREATE TABLE rides ();")},
+			"migrations/0000_rides.down.sql": {Data: []byte("DROP TABLE rides;")},
+		},
+	}
+
+	for _, fsys := range tests {
+		_, err := database.LoadMigrations(fsys, "migrations")
+		assert.NotNil(t, err)
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := database.LoadMigrations(ddl.Migrations, ddl.MigrationsDir)
+	if assert.Nil(t, err) && assert.NotEmpty(t, migrations) {
+		assert.Equal(t, 1, migrations[0].Version)
+		assert.Equal(t, "schema", migrations[0].Name)
+	}
+}
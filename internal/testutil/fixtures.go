@@ -72,6 +72,78 @@ func MakeTicketRepositoryFixture() (*repos.TicketRepository, *sqlx.DB, func()) {
 	}
 }
 
+func MakeSessionRepositoryFixture() (*repos.SessionRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	sessionRepository := repos.NewSessionRepository(db)
+	return sessionRepository, db, func() {
+		dbTeardown()
+	}
+}
+
+func MakePasswordResetRepositoryFixture() (*repos.PasswordResetRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	passwordResetRepository := repos.NewPasswordResetRepository(db)
+	return passwordResetRepository, db, func() {
+		dbTeardown()
+	}
+}
+
+func MakeLoginAttemptRepositoryFixture() (*repos.LoginAttemptRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	loginAttemptRepository := repos.NewLoginAttemptRepository(db)
+	return loginAttemptRepository, db, func() {
+		dbTeardown()
+	}
+}
+
+func MakeAuditRepositoryFixture() (*repos.AuditRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	auditRepository := repos.NewAuditRepository(db)
+	return auditRepository, db, func() {
+		dbTeardown()
+	}
+}
+
+func MakeDeviceRepositoryFixture() (*repos.DeviceRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	deviceRepository := repos.NewDeviceRepository(db)
+	return deviceRepository, db, func() {
+		dbTeardown()
+	}
+}
+
+func MakeTwoFactorRepositoryFixture() (*repos.TwoFactorRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	twoFactorRepository := repos.NewTwoFactorRepository(db)
+	return twoFactorRepository, db, func() {
+		dbTeardown()
+	}
+}
+
+func MakeRoleRepositoryFixture() (*repos.RoleRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	roleRepository := repos.NewRoleRepository(db)
+	return roleRepository, db, func() {
+		dbTeardown()
+	}
+}
+
+func MakeHealthRepositoryFixture() (*repos.HealthRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	healthRepository := repos.NewHealthRepository(db)
+	return healthRepository, db, func() {
+		dbTeardown()
+	}
+}
+
+func MakeReportRepositoryFixture() (*repos.ReportRepository, *sqlx.DB, func()) {
+	db, dbTeardown := MakeDatabaseFixture()
+	reportRepository := repos.NewReportRepository(db)
+	return reportRepository, db, func() {
+		dbTeardown()
+	}
+}
+
 // Make*RepositoryFixtureWithDB
 // --------------------------------
 
@@ -104,3 +176,38 @@ func MakeTicketRepositoryFixtureWithDB(db *sqlx.DB) (*repos.TicketRepository, fu
 	ticketRepository := repos.NewTicketRepository(db)
 	return ticketRepository, func() {}
 }
+
+func MakeSessionRepositoryFixtureWithDB(db *sqlx.DB) (*repos.SessionRepository, func()) {
+	sessionRepository := repos.NewSessionRepository(db)
+	return sessionRepository, func() {}
+}
+
+func MakePasswordResetRepositoryFixtureWithDB(db *sqlx.DB) (*repos.PasswordResetRepository, func()) {
+	passwordResetRepository := repos.NewPasswordResetRepository(db)
+	return passwordResetRepository, func() {}
+}
+
+func MakeLoginAttemptRepositoryFixtureWithDB(db *sqlx.DB) (*repos.LoginAttemptRepository, func()) {
+	loginAttemptRepository := repos.NewLoginAttemptRepository(db)
+	return loginAttemptRepository, func() {}
+}
+
+func MakeAuditRepositoryFixtureWithDB(db *sqlx.DB) (*repos.AuditRepository, func()) {
+	auditRepository := repos.NewAuditRepository(db)
+	return auditRepository, func() {}
+}
+
+func MakeDeviceRepositoryFixtureWithDB(db *sqlx.DB) (*repos.DeviceRepository, func()) {
+	deviceRepository := repos.NewDeviceRepository(db)
+	return deviceRepository, func() {}
+}
+
+func MakeTwoFactorRepositoryFixtureWithDB(db *sqlx.DB) (*repos.TwoFactorRepository, func()) {
+	twoFactorRepository := repos.NewTwoFactorRepository(db)
+	return twoFactorRepository, func() {}
+}
+
+func MakeRoleRepositoryFixtureWithDB(db *sqlx.DB) (*repos.RoleRepository, func()) {
+	roleRepository := repos.NewRoleRepository(db)
+	return roleRepository, func() {}
+}
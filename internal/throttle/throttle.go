@@ -0,0 +1,165 @@
+// Package throttle implements a throttle of failed attempts, like log ins,
+// with exponential back off and lock outs.
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// maxEntries is the number of tracked keys after which stale entries
+// are dropped.
+const maxEntries = 10000
+
+// Throttle keeps track of failures per key (e.g. an email or an IP address)
+// and tells for how long a key is blocked. Every failure doubles the delay
+// before the key can be tried again, and after too many failures the key is
+// locked out. Throttle is safe for concurrent use.
+//
+// Attempts are reserved before they are made, see Attempt, and their result
+// is reported once known, see Done. Attempts in flight count as possible
+// failures, so that concurrent attempts can't get past the throttle before
+// any of them fails.
+type Throttle struct {
+	maxFailures int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	lockout     time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*throttleEntry
+}
+
+// throttleEntry holds the failures for a key, the attempts in flight and
+// until when it's blocked.
+type throttleEntry struct {
+	failures     int
+	inFlight     int
+	blockedUntil time.Time
+}
+
+// New returns a new Throttle instance. The delay after the first
+// failure is baseDelay, it doubles with every failure up to maxDelay. After
+// maxFailures failures the key is locked out for the lockout duration.
+func New(maxFailures int, baseDelay, maxDelay, lockout time.Duration) *Throttle {
+	return &Throttle{
+		maxFailures: maxFailures,
+		baseDelay:   baseDelay,
+		maxDelay:    maxDelay,
+		lockout:     lockout,
+		entries:     make(map[string]*throttleEntry),
+	}
+}
+
+// Attempt reserves an attempt for the given key and returns true if the key
+// can be tried now. Otherwise it returns false and how long until the key can
+// be tried again. Once a key has failed its attempts are made one at a time,
+// and there are never more attempts in flight than failures left before the
+// lock out. Every reserved attempt must be ended with Done.
+func (t *Throttle) Attempt(key string) (bool, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := t.entries[key]
+	if !ok {
+		if len(t.entries) >= maxEntries {
+			t.prune(now)
+		}
+
+		entry = &throttleEntry{}
+		t.entries[key] = entry
+	}
+
+	if t.isStale(entry, now) {
+		entry.failures = 0
+	}
+
+	if now.Before(entry.blockedUntil) {
+		return false, entry.blockedUntil.Sub(now)
+	}
+
+	if entry.inFlight > 0 && (entry.failures > 0 || entry.failures+entry.inFlight >= t.maxFailures) {
+		return false, t.baseDelay
+	}
+
+	entry.inFlight++
+	return true, 0
+}
+
+// Done ends an attempt reserved with Attempt for the given key, recording a
+// failure if it failed.
+func (t *Throttle) Done(key string, failed bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok || entry.inFlight <= 0 {
+		return
+	}
+
+	entry.inFlight--
+	if !failed {
+		if entry.failures <= 0 && entry.inFlight <= 0 {
+			delete(t.entries, key)
+		}
+		return
+	}
+
+	now := time.Now()
+	entry.failures++
+	if entry.failures >= t.maxFailures {
+		entry.blockedUntil = now.Add(t.lockout)
+		return
+	}
+
+	delay := t.baseDelay << uint(entry.failures-1)
+	if delay <= 0 || delay > t.maxDelay {
+		delay = t.maxDelay
+	}
+
+	entry.blockedUntil = now.Add(delay)
+}
+
+// Reset forgets all failures for the given key. Attempts in flight must still
+// be ended with Done.
+func (t *Throttle) Reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	entry, ok := t.entries[key]
+	if !ok {
+		return
+	}
+
+	if entry.inFlight <= 0 {
+		delete(t.entries, key)
+		return
+	}
+
+	entry.failures = 0
+	entry.blockedUntil = time.Time{}
+}
+
+// Prune forgets every key whose failures can be forgotten, and that has no
+// attempts in flight. Keys are otherwise only pruned once there are too many.
+func (t *Throttle) Prune() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.prune(time.Now())
+}
+
+// isStale returns true if the entry has not been blocked for a whole lockout
+// duration, meaning its failures can be forgotten.
+func (t *Throttle) isStale(entry *throttleEntry, now time.Time) bool {
+	return now.After(entry.blockedUntil.Add(t.lockout))
+}
+
+func (t *Throttle) prune(now time.Time) {
+	for key, entry := range t.entries {
+		if entry.inFlight <= 0 && t.isStale(entry, now) {
+			delete(t.entries, key)
+		}
+	}
+}
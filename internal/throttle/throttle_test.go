@@ -0,0 +1,114 @@
+package throttle_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/throttle"
+)
+
+func TestThrottleBacksOff(t *testing.T) {
+	th := throttle.New(5, time.Millisecond*20, time.Second, time.Second)
+
+	allowed, _ := th.Attempt("key0")
+	assert.True(t, allowed)
+
+	th.Done("key0", true)
+	allowed, wait := th.Attempt("key0")
+	assert.False(t, allowed)
+	assert.True(t, wait > 0 && wait <= time.Millisecond*20)
+
+	time.Sleep(time.Millisecond * 25)
+	allowed, _ = th.Attempt("key0")
+	assert.True(t, allowed)
+
+	th.Done("key0", true)
+	_, wait = th.Attempt("key0")
+	assert.True(t, wait > time.Millisecond*20 && wait <= time.Millisecond*40)
+
+	// other keys are not affected
+	allowed, _ = th.Attempt("key1")
+	assert.True(t, allowed)
+	th.Done("key1", false)
+
+	time.Sleep(time.Millisecond * 50)
+	allowed, _ = th.Attempt("key0")
+	assert.True(t, allowed)
+}
+
+func TestThrottleLocksOut(t *testing.T) {
+	th := throttle.New(3, time.Millisecond, time.Millisecond, time.Hour)
+
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Millisecond * 2)
+		allowed, _ := th.Attempt("key0")
+		assert.True(t, allowed)
+		th.Done("key0", true)
+	}
+
+	allowed, wait := th.Attempt("key0")
+	assert.False(t, allowed)
+	assert.True(t, wait > time.Minute*59)
+
+	th.Reset("key0")
+	allowed, _ = th.Attempt("key0")
+	assert.True(t, allowed)
+}
+
+func TestThrottleCountsAttemptsInFlight(t *testing.T) {
+	th := throttle.New(3, time.Millisecond, time.Millisecond, time.Hour)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, _ := th.Attempt("key0")
+			if ok {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// every attempt is still in flight, and could fail
+	assert.Equal(t, 3, allowed)
+
+	th.Done("key0", true)
+	time.Sleep(time.Millisecond * 2)
+	ok, _ := th.Attempt("key0")
+	assert.False(t, ok, "attempts must be made one at a time once the key failed")
+
+	th.Done("key0", false)
+	th.Done("key0", false)
+	ok, _ = th.Attempt("key0")
+	assert.True(t, ok)
+}
+
+func TestThrottlePruneKeepsBlockedKeys(t *testing.T) {
+	th := throttle.New(3, time.Hour, time.Hour, time.Hour)
+
+	allowed, _ := th.Attempt("key0")
+	assert.True(t, allowed)
+	th.Done("key0", true)
+
+	allowed, _ = th.Attempt("key1")
+	assert.True(t, allowed)
+
+	th.Prune()
+
+	allowed, _ = th.Attempt("key0")
+	assert.False(t, allowed, "blocked keys must not be pruned")
+
+	// attempts in flight are kept as well
+	th.Done("key1", true)
+	allowed, _ = th.Attempt("key1")
+	assert.False(t, allowed)
+}
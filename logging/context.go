@@ -0,0 +1,41 @@
+package logging
+
+import (
+	"context"
+)
+
+type loggerContextKey struct{}
+
+type requestIDContextKey struct{}
+
+// NewContext returns a copy of the given context holding the given logger.
+func NewContext(ctx context.Context, logger *Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the logger stored in the given context, or the default
+// logger if none.
+func FromContext(ctx context.Context) *Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(loggerContextKey{}).(*Logger); ok {
+			return logger
+		}
+	}
+	return defaultLogger
+}
+
+// ContextWithRequestID returns a copy of the given context holding the given
+// request ID.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID stored in the given context, or
+// an empty string if none.
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,156 @@
+// Package logging writes structured logs, one JSON object per line. Loggers
+// are carried in a context.Context, so that everything logged while handling
+// a request has its request ID.
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Level represents the severity of a log entry.
+type Level int
+
+// Log levels, in increasing order of severity.
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+// String returns the name of the level.
+func (l Level) String() string {
+	return levelNames[l]
+}
+
+// ParseLevel returns the level with the given name.
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if levelName == name {
+			return level, nil
+		}
+	}
+	return LevelInfo, fmt.Errorf("parseLevel: unknown level '%s'", name)
+}
+
+// Fields are the key-value pairs of a log entry.
+type Fields map[string]interface{}
+
+// Logger writes log entries at or above its level as JSON lines. Loggers
+// created with With share the writer of their parent.
+type Logger struct {
+	out    io.Writer
+	mu     *sync.Mutex
+	level  Level
+	fields Fields
+}
+
+// New returns a new Logger instance that writes entries at or above the given
+// level to out.
+func New(out io.Writer, level Level) *Logger {
+	return &Logger{
+		out,
+		&sync.Mutex{},
+		level,
+		Fields{},
+	}
+}
+
+var defaultLogger = New(os.Stderr, LevelInfo)
+
+// Default returns the logger used when a context holds none.
+func Default() *Logger {
+	return defaultLogger
+}
+
+// SetDefault replaces the logger used when a context holds none. It's meant to
+// be called once on startup.
+func SetDefault(logger *Logger) {
+	defaultLogger = logger
+}
+
+// With returns a child logger that adds the given fields to every entry.
+func (l *Logger) With(fields Fields) *Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+
+	return &Logger{l.out, l.mu, l.level, merged}
+}
+
+// Enabled returns true if entries with the given level are written.
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+// Debug logs the given message and fields with the debug level.
+func (l *Logger) Debug(msg string, fields ...Fields) {
+	l.log(LevelDebug, msg, fields)
+}
+
+// Info logs the given message and fields with the info level.
+func (l *Logger) Info(msg string, fields ...Fields) {
+	l.log(LevelInfo, msg, fields)
+}
+
+// Warn logs the given message and fields with the warn level.
+func (l *Logger) Warn(msg string, fields ...Fields) {
+	l.log(LevelWarn, msg, fields)
+}
+
+// Error logs the given message and fields with the error level.
+func (l *Logger) Error(msg string, fields ...Fields) {
+	l.log(LevelError, msg, fields)
+}
+
+func (l *Logger) log(level Level, msg string, fields []Fields) {
+	if !l.Enabled(level) {
+		return
+	}
+
+	entry := make(Fields, len(l.fields)+3)
+	for key, value := range l.fields {
+		entry[key] = value
+	}
+	for _, f := range fields {
+		for key, value := range f {
+			entry[key] = value
+		}
+	}
+
+	// errors don't marshal to anything useful
+	for key, value := range entry {
+		if err, ok := value.(error); ok {
+			entry[key] = err.Error()
+		}
+	}
+
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["msg"] = msg
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		line, _ = json.Marshal(Fields{"time": entry["time"], "level": level.String(), "msg": msg, "error": err.Error()})
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.out.Write(append(line, '\n'))
+}
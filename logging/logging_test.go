@@ -0,0 +1,54 @@
+package logging_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
+)
+
+func TestLoggerWritesJSONLines(t *testing.T) {
+	out := &bytes.Buffer{}
+	logger := logging.New(out, logging.LevelInfo).With(logging.Fields{"request_id": "request0"})
+
+	logger.Debug("skipped")
+	logger.Error("failed", logging.Fields{"error": fmt.Errorf("some error")})
+
+	entry := logging.Fields{}
+	err := json.Unmarshal(out.Bytes(), &entry)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "error", entry["level"])
+	assert.Equal(t, "failed", entry["msg"])
+	assert.Equal(t, "some error", entry["error"])
+	assert.Equal(t, "request0", entry["request_id"])
+	assert.NotEmpty(t, entry["time"])
+}
+
+func TestLoggerFromContext(t *testing.T) {
+	logger := logging.New(&bytes.Buffer{}, logging.LevelDebug)
+
+	ctx := logging.NewContext(context.Background(), logger)
+	assert.Equal(t, logger, logging.FromContext(ctx))
+	assert.Equal(t, logging.Default(), logging.FromContext(context.Background()))
+
+	ctx = logging.ContextWithRequestID(ctx, "request0")
+	assert.Equal(t, "request0", logging.RequestIDFromContext(ctx))
+	assert.Equal(t, "", logging.RequestIDFromContext(context.Background()))
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := logging.ParseLevel("warn")
+	assert.Nil(t, err)
+	assert.Equal(t, logging.LevelWarn, level)
+
+	_, err = logging.ParseLevel("verbose")
+	assert.NotNil(t, err)
+}
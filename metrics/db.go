@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"database/sql"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DBStatsCollector collects the connection pool stats of a database.
+type DBStatsCollector struct {
+	db *sql.DB
+
+	maxOpen           *prometheus.Desc
+	open              *prometheus.Desc
+	inUse             *prometheus.Desc
+	idle              *prometheus.Desc
+	waitCount         *prometheus.Desc
+	waitDuration      *prometheus.Desc
+	maxIdleClosed     *prometheus.Desc
+	maxLifetimeClosed *prometheus.Desc
+}
+
+// NewDBStatsCollector returns a new DBStatsCollector instance for the given
+// database.
+func NewDBStatsCollector(db *sql.DB) *DBStatsCollector {
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db", name), help, nil, nil)
+	}
+
+	return &DBStatsCollector{
+		db,
+		desc("max_open_connections", "Maximum number of open connections."),
+		desc("open_connections", "Number of open connections, in use or idle."),
+		desc("in_use_connections", "Number of connections in use."),
+		desc("idle_connections", "Number of idle connections."),
+		desc("wait_count_total", "Number of connections waited for."),
+		desc("wait_duration_seconds_total", "Time spent waiting for connections."),
+		desc("max_idle_closed_total", "Number of connections closed due to the idle limit."),
+		desc("max_lifetime_closed_total", "Number of connections closed due to their maximum lifetime."),
+	}
+}
+
+// Describe implements the prometheus.Collector interface.
+func (dc *DBStatsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- dc.maxOpen
+	ch <- dc.open
+	ch <- dc.inUse
+	ch <- dc.idle
+	ch <- dc.waitCount
+	ch <- dc.waitDuration
+	ch <- dc.maxIdleClosed
+	ch <- dc.maxLifetimeClosed
+}
+
+// Collect implements the prometheus.Collector interface.
+func (dc *DBStatsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats := dc.db.Stats()
+	ch <- prometheus.MustNewConstMetric(dc.maxOpen, prometheus.GaugeValue, float64(stats.MaxOpenConnections))
+	ch <- prometheus.MustNewConstMetric(dc.open, prometheus.GaugeValue, float64(stats.OpenConnections))
+	ch <- prometheus.MustNewConstMetric(dc.inUse, prometheus.GaugeValue, float64(stats.InUse))
+	ch <- prometheus.MustNewConstMetric(dc.idle, prometheus.GaugeValue, float64(stats.Idle))
+	ch <- prometheus.MustNewConstMetric(dc.waitCount, prometheus.CounterValue, float64(stats.WaitCount))
+	ch <- prometheus.MustNewConstMetric(dc.waitDuration, prometheus.CounterValue, stats.WaitDuration.Seconds())
+	ch <- prometheus.MustNewConstMetric(dc.maxIdleClosed, prometheus.CounterValue, float64(stats.MaxIdleClosed))
+	ch <- prometheus.MustNewConstMetric(dc.maxLifetimeClosed, prometheus.CounterValue, float64(stats.MaxLifetimeClosed))
+}
@@ -0,0 +1,78 @@
+// Package metrics defines the Prometheus metrics exposed by the server. HTTP
+// metrics are recorded by a middleware, business metrics by the usecases, so
+// that they're counted whatever the transport.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "theme_park"
+
+// Registry is the registry every metric in this package is registered in.
+var Registry = prometheus.NewRegistry()
+
+// HTTP metrics.
+var (
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of HTTP requests by route, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	HTTPDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of HTTP requests by route and method.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method"})
+)
+
+// Business metrics.
+var (
+	TicketsSold = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "tickets_sold_total",
+		Help:      "Number of tickets sold.",
+	})
+
+	TicketScans = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "ticket_scans_total",
+		Help:      "Number of ticket scans by ride.",
+	}, []string{"ride_id"})
+
+	MaintenanceOpened = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "maintenance_opened_total",
+		Help:      "Number of maintenance jobs opened.",
+	})
+
+	MaintenanceClosed = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "maintenance_closed_total",
+		Help:      "Number of maintenance jobs closed.",
+	})
+
+	LoginFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "login_failures_total",
+		Help:      "Number of failed log in attempts by reason.",
+	}, []string{"reason"})
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPDuration,
+		TicketsSold,
+		TicketScans,
+		MaintenanceOpened,
+		MaintenanceClosed,
+		LoginFailures,
+	)
+}
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// DeviceKeyHeader is the header devices send their key in.
+const DeviceKeyHeader = "X-Device-Key"
+
+var (
+	// errInvalidDeviceKey is returned when the device key is not valid.
+	errInvalidDeviceKey = echo.NewHTTPError(http.StatusUnauthorized, "invalid or revoked device key")
+
+	// errDeviceNotAllowed is returned when a device key is sent to a path
+	// that doesn't accept devices.
+	errDeviceNotAllowed = echo.NewHTTPError(http.StatusForbidden, "devices are not allowed to access this resource")
+)
+
+// DeviceAuth authenticates devices, like ticket scanners, using their keys.
+// Devices can only access the paths they are allowed on.
+type DeviceAuth struct {
+	deviceUsecase usecases.DeviceUsecase
+	allowed       map[string]bool
+}
+
+// NewDeviceAuth returns a new DeviceAuth instance. Devices are only allowed
+// on the given paths (e.g. the ones checking a device scope, see
+// RequireRolesOrDeviceScope), in any API version.
+func NewDeviceAuth(deviceUsecase usecases.DeviceUsecase, allowedPaths ...string) *DeviceAuth {
+	allowed := make(map[string]bool, len(allowedPaths))
+	for _, path := range allowedPaths {
+		allowed[path] = true
+	}
+
+	return &DeviceAuth{
+		deviceUsecase,
+		allowed,
+	}
+}
+
+// HasDeviceKey returns true if the request carries a device key. Such
+// requests should skip user key authentication.
+func HasDeviceKey(c echo.Context) bool {
+	return len(c.Request().Header.Get(DeviceKeyHeader)) > 0
+}
+
+// Middleware returns a middleware that authenticates requests carrying a
+// device key and sets the device as the principal. Requests carrying a device
+// key to any other path than the allowed ones are forbidden. Other requests
+// are let through untouched.
+func (da *DeviceAuth) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !HasDeviceKey(c) {
+				return next(c)
+			}
+
+			if !da.allowed[UnversionedPath(c.Path())] {
+				return errDeviceNotAllowed
+			}
+
+			ctx := c.Request().Context()
+			device, err := da.deviceUsecase.Authenticate(ctx, c.Request().Header.Get(DeviceKeyHeader))
+			if err != nil {
+				return errInvalidDeviceKey
+			}
+
+			SetPrincipal(c, usecases.NewDevicePrincipal(device))
+			return next(c)
+		}
+	}
+}
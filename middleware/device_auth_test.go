@@ -0,0 +1,60 @@
+package middleware_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// deviceUsecaseStub authenticates a single device key.
+type deviceUsecaseStub struct {
+	usecases.DeviceUsecase
+}
+
+func (du *deviceUsecaseStub) Authenticate(ctx context.Context, key string) (*models.Device, error) {
+	if key != "key0" {
+		return nil, errors.New("invalid key")
+	}
+	return &models.Device{ID: "device0"}, nil
+}
+
+func serveDevice(path, key string) *httptest.ResponseRecorder {
+	e := echo.New()
+	e.Use(middleware.NewDeviceAuth(&deviceUsecaseStub{}, "/scans/:ticketID/on/:rideID").Middleware())
+
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+	e.POST("/v1/scans/:ticketID/on/:rideID", handler)
+	e.POST("/v1/tickets", handler)
+
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if len(key) > 0 {
+		req.Header.Set(middleware.DeviceKeyHeader, key)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestDeviceAuthAllowsDevicesOnAllowedPaths(t *testing.T) {
+	assert.Equal(t, http.StatusOK, serveDevice("/v1/scans/ticket0/on/ride0", "key0").Code)
+	assert.Equal(t, http.StatusUnauthorized, serveDevice("/v1/scans/ticket0/on/ride0", "key1").Code)
+}
+
+func TestDeviceAuthDeniesDevicesByDefault(t *testing.T) {
+	assert.Equal(t, http.StatusForbidden, serveDevice("/v1/tickets", "key0").Code)
+
+	// requests without a device key are left to key authentication
+	assert.Equal(t, http.StatusOK, serveDevice("/v1/tickets", "").Code)
+}
@@ -7,61 +7,171 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/cache"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
+// keyContextKey is the key used for storing the validated crypto.Key in the
+// echo context.
+const keyContextKey = "key"
+
+// cacheDuration is the maximum duration a validated key is trusted before
+// checking it against the database again.
+const cacheDuration = time.Minute * 5
+
+// cacheSize is the maximum number of validated keys that are kept.
+const cacheSize = 10000
+
+// ErrInvalidCredentials is returned when logging in with a wrong password.
+var ErrInvalidCredentials = fmt.Errorf("loginError: invalid username or password")
+
 // KeyAuth struct helps with log in and log in validation.
+//
+// Validated keys are cached in memory for up to cacheDuration, and Revoke and
+// InvalidateUser only clear the cache of this process. When running more than
+// one instance, a revoked key (or the key of a changed user) keeps working on
+// the other instances until their cached entry expires, which is at most
+// cacheDuration (5 minutes); the session is then checked against the database
+// again.
 type KeyAuth struct {
-	userUsecase usecases.UserUsecase
-	expirations map[crypto.Key]time.Time
+	userUsecase    usecases.UserUsecase
+	sessionUsecase usecases.SessionUsecase
+	signer         *crypto.Signer
+	keyDuration    time.Duration
+	keys           *cache.Cache
 }
 
-// NewKeyAuth returns a new KeyAuth instance.
-func NewKeyAuth(userUsecase usecases.UserUsecase) *KeyAuth {
+// NewKeyAuth returns a new KeyAuth instance. Keys are signed using the given
+// secret and are valid for the given duration.
+func NewKeyAuth(userUsecase usecases.UserUsecase, sessionUsecase usecases.SessionUsecase, secret []byte, keyDuration time.Duration) *KeyAuth {
 	return &KeyAuth{
 		userUsecase,
-		make(map[crypto.Key]time.Time),
+		sessionUsecase,
+		crypto.NewSigner(secret),
+		keyDuration,
+		cache.New(cacheSize, cacheDuration),
 	}
 }
 
 // Login takes an user and its password and returns a crypto.Key if login was successful.
-func (ka *KeyAuth) Login(ctx context.Context, user *models.User, password string) (crypto.Key, error) {
+func (ka *KeyAuth) Login(ctx context.Context, user *models.User, password, userAgent, address string) (crypto.Key, error) {
+	err := ka.CheckPassword(user, password)
+	if err != nil {
+		return crypto.EmptyKey, err
+	}
+
+	return ka.Issue(ctx, user, userAgent, address)
+}
+
+// CheckPassword returns ErrInvalidCredentials unless the given password is
+// the password of the given user. Used when logging in takes more than one
+// step, e.g. with two-factor authentication.
+func (ka *KeyAuth) CheckPassword(user *models.User, password string) error {
 	if !crypto.CompareHashAndPassword(user.PasswordHash, password) {
-		return crypto.EmptyKey, fmt.Errorf("loginError: invalid username or password")
+		return ErrInvalidCredentials
 	}
+	return nil
+}
+
+// Issue creates a new session for the given user and returns its crypto.Key.
+func (ka *KeyAuth) Issue(ctx context.Context, user *models.User, userAgent, address string) (crypto.Key, error) {
+	session := &models.Session{
+		UserID:    user.ID,
+		UserAgent: models.NewNullString(userAgent),
+		Address:   models.NewNullString(address),
+		ExpiresOn: time.Now().UTC().Add(ka.keyDuration),
+	}
+
+	err := ka.sessionUsecase.Store(ctx, session)
+	if err != nil {
+		return crypto.EmptyKey, fmt.Errorf("loginError: %s", err)
+	}
+
+	stamp := ka.signer.Stamp(user.PasswordHash)
+	key := crypto.NewKey(session.ID, user.ID, stamp, session.IssuedOn, session.ExpiresOn.Sub(session.IssuedOn))
+	return key, nil
+}
 
-	return crypto.Key{Login: user.ID, PasswordHash: user.PasswordHash}, nil
+// Encode returns the signed, encoded version of the given key.
+func (ka *KeyAuth) Encode(key crypto.Key) string {
+	return ka.signer.Encode(key)
+}
+
+// Logout revokes the session for the given key.
+func (ka *KeyAuth) Logout(ctx context.Context, key crypto.Key) error {
+	return ka.Revoke(ctx, key.ID)
+}
+
+// Revoke revokes the session with the given ID, making its key invalid.
+func (ka *KeyAuth) Revoke(ctx context.Context, sessionID string) error {
+	ka.keys.Delete(sessionID)
+	return ka.sessionUsecase.Revoke(ctx, sessionID)
+}
+
+// InvalidateUser drops every cached key for the given user, so that their
+// keys are checked against the database on the next request. It must be
+// called whenever the user, its role or its password change.
+func (ka *KeyAuth) InvalidateUser(userID string) {
+	ka.keys.DeleteFunc(func(sessionID string, value interface{}) bool {
+		return value.(*models.User).ID == userID
+	})
 }
 
 // Validator is a validator function for middleware.KeyAuth from echo.
 func (ka *KeyAuth) Validator(encodedKey string, c echo.Context) (bool, error) {
 
-	key, err := crypto.DecodeKey(encodedKey)
+	key, err := ka.signer.Decode(encodedKey)
 	if err != nil {
 		return false, err
 	}
 
 	// check if key was used before and has not expired
 
-	if exp, ok := ka.expirations[key]; ok && time.Now().Before(exp) {
+	if cached, ok := ka.keys.Get(key.ID); ok {
+		ka.setKey(c, key, cached.(*models.User))
 		return true, nil
 	}
 
 	// key has not been used or has expired
 
+	now := time.Now()
 	ctx := c.Request().Context()
+	session, err := ka.sessionUsecase.GetByID(ctx, key.ID)
+	if err != nil {
+		return false, err
+	}
+
+	if session.UserID != key.Login || !session.IsActive(now) {
+		return false, fmt.Errorf("keyAuthValidator: given key is not valid")
+	}
+
 	user, err := ka.userUsecase.GetByID(ctx, key.Login)
 	if err != nil {
 		return false, err
 	}
 
-	if key.PasswordHash != user.PasswordHash {
+	if key.Stamp != ka.signer.Stamp(user.PasswordHash) {
 		return false, fmt.Errorf("keyAuthValidator: given key is not valid")
 	}
 
-	// key is valid, set up new expiration time
+	// key is valid, trust it until it expires (at most cacheDuration)
 
-	ka.expirations[key] = time.Now().Add(time.Hour * 24)
+	ka.keys.SetUntil(key.ID, user, key.ExpiresAt)
+	ka.setKey(c, key, user)
 	return true, nil
 }
+
+// setKey stores the validated key and the resolved principal (user and role)
+// for the request. The user may be shared between requests and must not be
+// modified.
+func (ka *KeyAuth) setKey(c echo.Context, key crypto.Key, user *models.User) {
+	c.Set(keyContextKey, key)
+	SetPrincipal(c, usecases.NewPrincipal(user))
+}
+
+// KeyFromContext returns the crypto.Key validated for the current request.
+func KeyFromContext(c echo.Context) (crypto.Key, bool) {
+	key, ok := c.Get(keyContextKey).(crypto.Key)
+	return key, ok
+}
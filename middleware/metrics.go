@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/metrics"
+)
+
+// unmatchedRoute is the route label used for requests that match no route, so
+// that unknown paths don't create new series.
+const unmatchedRoute = "unmatched"
+
+// Metrics returns a middleware that records the count and latency of requests
+// by route. Errors are handled here so that the recorded status is the one
+// sent to the client.
+//
+// Echo sets the path of requests that match no route to the request path, so
+// only paths of the routes registered in the given echo instance are used as
+// labels. The routes are read on the first request.
+func Metrics(e *echo.Echo) echo.MiddlewareFunc {
+	var once sync.Once
+	routes := map[string]bool{}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			once.Do(func() {
+				for _, route := range e.Routes() {
+					routes[route.Path] = true
+				}
+			})
+
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if !routes[route] {
+				route = unmatchedRoute
+			}
+
+			method := c.Request().Method
+			status := strconv.Itoa(c.Response().Status)
+			metrics.HTTPRequests.WithLabelValues(route, method, status).Inc()
+			metrics.HTTPDuration.WithLabelValues(route, method).Observe(time.Since(start).Seconds())
+			return nil
+		}
+	}
+}
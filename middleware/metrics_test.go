@@ -0,0 +1,38 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/metrics"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+)
+
+func TestMetricsCountsByRoute(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.Metrics(e))
+	e.GET("/rides/:rideID", func(c echo.Context) error {
+		if c.Param("rideID") == "missing" {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return c.NoContent(http.StatusOK)
+	})
+
+	for _, path := range []string{"/rides/ride0", "/rides/ride1", "/rides/missing", "/unknown"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	ok := metrics.HTTPRequests.WithLabelValues("/rides/:rideID", http.MethodGet, "200")
+	notFound := metrics.HTTPRequests.WithLabelValues("/rides/:rideID", http.MethodGet, "404")
+	assert.Equal(t, float64(2), testutil.ToFloat64(ok))
+	assert.Equal(t, float64(1), testutil.ToFloat64(notFound))
+
+	// paths without a route share a single series
+	unmatched := metrics.HTTPRequests.WithLabelValues("unmatched", http.MethodGet, "404")
+	assert.Equal(t, float64(1), testutil.ToFloat64(unmatched))
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
+)
+
+// validRequestID matches the incoming request IDs that are kept. Others are
+// replaced, so that clients can't inject anything into the logs.
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// RequestLogger returns a middleware that gives every request an ID, taken
+// from the X-Request-ID header if valid, and logs the request once handled.
+// The ID is sent back in the X-Request-ID header, and both the ID and a logger
+// with the ID are stored in the request context (see logging.FromContext).
+// Errors are handled here so that the logged status is the one sent to the
+// client.
+func RequestLogger(logger *logging.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+
+			req := c.Request()
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if !validRequestID.MatchString(requestID) {
+				requestID = uuid.New().String()
+			}
+
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+
+			requestLogger := logger.With(logging.Fields{"request_id": requestID})
+			ctx := logging.ContextWithRequestID(req.Context(), requestID)
+			ctx = logging.NewContext(ctx, requestLogger)
+			c.SetRequest(req.WithContext(ctx))
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			fields := logging.Fields{
+				"method":      req.Method,
+				"uri":         req.RequestURI,
+				"route":       c.Path(),
+				"status":      c.Response().Status,
+				"bytes_out":   c.Response().Size,
+				"remote_ip":   c.RealIP(),
+				"duration_ms": float64(time.Since(start).Microseconds()) / 1000,
+			}
+
+			if err != nil {
+				fields["error"] = err
+			}
+
+			if c.Response().Status >= 500 {
+				requestLogger.Error("request failed", fields)
+			} else {
+				requestLogger.Info("request", fields)
+			}
+
+			return nil
+		}
+	}
+}
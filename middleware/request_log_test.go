@@ -0,0 +1,57 @@
+package middleware_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+)
+
+func TestRequestLoggerKeepsValidRequestID(t *testing.T) {
+	out := &bytes.Buffer{}
+	e := echo.New()
+	e.Use(middleware.RequestLogger(logging.New(out, logging.LevelInfo)))
+	e.GET("/rides", func(c echo.Context) error {
+		assert.Equal(t, "request0", logging.RequestIDFromContext(c.Request().Context()))
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/rides", nil)
+	req.Header.Set(echo.HeaderXRequestID, "request0")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "request0", rec.Header().Get(echo.HeaderXRequestID))
+
+	entry := logging.Fields{}
+	err := json.Unmarshal(out.Bytes(), &entry)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "request0", entry["request_id"])
+		assert.Equal(t, "/rides", entry["route"])
+		assert.Equal(t, float64(http.StatusOK), entry["status"])
+	}
+}
+
+func TestRequestLoggerReplacesInvalidRequestID(t *testing.T) {
+	e := echo.New()
+	e.Use(middleware.RequestLogger(logging.New(&bytes.Buffer{}, logging.LevelInfo)))
+	e.GET("/rides", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusForbidden)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/rides", nil)
+	req.Header.Set(echo.HeaderXRequestID, "request0\n{\"level\":\"error\"}")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderXRequestID))
+	assert.NotContains(t, rec.Header().Get(echo.HeaderXRequestID), "request0")
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// errTwoFactorSetupRequired is returned when the role of the caller requires
+// two-factor authentication, but the caller has not enabled it yet.
+var errTwoFactorSetupRequired = echo.NewHTTPError(http.StatusForbidden, "two-factor authentication is required for your role, please enable it first")
+
+// RequireTwoFactorSetup returns a middleware that only lets users whose role
+// requires two-factor authentication, but who have not enabled it, access the
+// given paths (e.g. the ones for enabling it), in any API version. Other
+// callers are let through.
+func RequireTwoFactorSetup(allowedPaths ...string) echo.MiddlewareFunc {
+	allowed := make(map[string]bool, len(allowedPaths))
+	for _, path := range allowedPaths {
+		allowed[path] = true
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			principal, _ := PrincipalFromContext(c)
+			if principal != nil && principal.User != nil && principal.User.NeedsTwoFactor() && !allowed[UnversionedPath(c.Path())] {
+				return errTwoFactorSetupRequired
+			}
+			return next(c)
+		}
+	}
+}
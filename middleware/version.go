@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// versionContextKey is the key of the API version in echo.Context.
+const versionContextKey = "apiVersion"
+
+// versionPrefix matches the API version prefix of paths, e.g. "/v1".
+var versionPrefix = regexp.MustCompile(`^/v([0-9]+)(/|$)`)
+
+// Version describes a version of the API, whose routes are mounted under
+// Prefix (e.g. "/v1").
+type Version struct {
+	Number int
+
+	// Deprecation is when the version was deprecated, zero if it's not.
+	Deprecation time.Time
+
+	// Sunset is when the version will stop being served, zero if that's not
+	// planned yet.
+	Sunset time.Time
+
+	// Successor is the version clients should migrate to, zero if none.
+	Successor int
+}
+
+// Prefix returns the path prefix of the version, e.g. "/v1".
+func (v Version) Prefix() string {
+	return fmt.Sprintf("/v%d", v.Number)
+}
+
+// IsDeprecated returns true if the version is deprecated.
+func (v Version) IsDeprecated() bool {
+	return !v.Deprecation.IsZero()
+}
+
+// APIVersions returns a middleware that sets the API version of the matched
+// route in the context (see VersionFromContext). Responses from deprecated
+// versions get Deprecation (RFC 9745) and Sunset (RFC 8594) headers, and a
+// link to the same path in the successor version. Once a version is past its
+// sunset, its routes respond with 410.
+func APIVersions(versions ...Version) echo.MiddlewareFunc {
+	byNumber := make(map[int]Version, len(versions))
+	for _, version := range versions {
+		byNumber[version.Number] = version
+	}
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			version, ok := byNumber[pathVersion(c.Path())]
+			if !ok {
+				return next(c)
+			}
+
+			c.Set(versionContextKey, version.Number)
+
+			if version.IsDeprecated() {
+				header := c.Response().Header()
+				header.Set("Deprecation", fmt.Sprintf("@%d", version.Deprecation.Unix()))
+				if !version.Sunset.IsZero() {
+					header.Set("Sunset", version.Sunset.UTC().Format(http.TimeFormat))
+				}
+				if version.Successor > 0 {
+					successor := fmt.Sprintf("/v%d", version.Successor) + UnversionedPath(c.Request().URL.Path)
+					header.Add("Link", fmt.Sprintf("<%s>; rel=\"successor-version\"", successor))
+				}
+			}
+
+			if !version.Sunset.IsZero() && time.Now().After(version.Sunset) {
+				return echo.NewHTTPError(http.StatusGone, fmt.Sprintf("version %d of the API is no longer served", version.Number))
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// VersionFromContext returns the API version of the current route, or zero if
+// the route isn't versioned.
+func VersionFromContext(c echo.Context) int {
+	version, _ := c.Get(versionContextKey).(int)
+	return version
+}
+
+// UnversionedPath returns the given path without its API version prefix, if
+// any, e.g. "/rides/:rideID" for "/v1/rides/:rideID".
+func UnversionedPath(path string) string {
+	match := versionPrefix.FindStringSubmatchIndex(path)
+	if match == nil {
+		return path
+	}
+	return "/" + path[match[1]:]
+}
+
+func pathVersion(path string) int {
+	match := versionPrefix.FindStringSubmatch(path)
+	if match == nil {
+		return 0
+	}
+	number, _ := strconv.Atoi(match[1])
+	return number
+}
@@ -0,0 +1,85 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+)
+
+func serveVersions(path string, versions ...middleware.Version) (*httptest.ResponseRecorder, int) {
+	e := echo.New()
+	e.Use(middleware.APIVersions(versions...))
+
+	version := -1
+	handler := func(c echo.Context) error {
+		version = middleware.VersionFromContext(c)
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/v1/rides/:rideID", handler)
+	e.GET("/v2/rides/:rideID", handler)
+	e.GET("/healthz", handler)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec, version
+}
+
+func TestAPIVersionsDeprecation(t *testing.T) {
+	deprecation := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	sunset := time.Now().Add(time.Hour * 24).Truncate(time.Second)
+	versions := []middleware.Version{
+		{Number: 1, Deprecation: deprecation, Sunset: sunset, Successor: 2},
+		{Number: 2},
+	}
+
+	rec, version := serveVersions("/v1/rides/ride0", versions...)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, version)
+	assert.Equal(t, "@1792281600", rec.Header().Get("Deprecation"))
+	assert.Equal(t, sunset.UTC().Format(http.TimeFormat), rec.Header().Get("Sunset"))
+	assert.Equal(t, `</v2/rides/ride0>; rel="successor-version"`, rec.Header().Get("Link"))
+
+	rec, version = serveVersions("/v2/rides/ride0", versions...)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 2, version)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+	assert.Empty(t, rec.Header().Get("Link"))
+
+	rec, version = serveVersions("/healthz", versions...)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 0, version)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+
+	// without dates the version is not deprecated yet
+	rec, version = serveVersions("/v1/rides/ride0", middleware.Version{Number: 1, Successor: 2}, middleware.Version{Number: 2})
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, version)
+	assert.Empty(t, rec.Header().Get("Deprecation"))
+	assert.Empty(t, rec.Header().Get("Sunset"))
+	assert.Empty(t, rec.Header().Get("Link"))
+}
+
+func TestAPIVersionsSunset(t *testing.T) {
+	deprecation := time.Now().Add(-time.Hour * 48)
+	sunset := time.Now().Add(-time.Hour)
+
+	rec, version := serveVersions("/v1/rides/ride0", middleware.Version{Number: 1, Deprecation: deprecation, Sunset: sunset, Successor: 2}, middleware.Version{Number: 2})
+	assert.Equal(t, http.StatusGone, rec.Code)
+	assert.Equal(t, -1, version)
+	assert.NotEmpty(t, rec.Header().Get("Sunset"))
+}
+
+func TestUnversionedPath(t *testing.T) {
+	assert.Equal(t, "/rides/:rideID", middleware.UnversionedPath("/v1/rides/:rideID"))
+	assert.Equal(t, "/rides", middleware.UnversionedPath("/v12/rides"))
+	assert.Equal(t, "/", middleware.UnversionedPath("/v2"))
+	assert.Equal(t, "/healthz", middleware.UnversionedPath("/healthz"))
+	assert.Equal(t, "/vendors", middleware.UnversionedPath("/vendors"))
+}
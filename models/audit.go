@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+)
+
+// Audit actions.
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+	AuditClose  = "close"
+	AuditScan   = "scan"
+	AuditRevoke = "revoke"
+)
+
+// AuditEntry represents a single mutating operation: who did it, on which
+// entity, and how the entity looked before and after.
+type AuditEntry struct {
+	ID         string     `json:"id"`
+	ActorID    NullString `db:"actor_id" json:"actorId"`
+	Action     string     `json:"action"`
+	EntityType string     `db:"entity_type" json:"entityType"`
+	EntityID   string     `db:"entity_id" json:"entityId"`
+	Before     NullJSON   `json:"before"`
+	After      NullJSON   `json:"after"`
+	RecordedOn time.Time  `db:"recorded_on" json:"recordedOn"`
+}
+
+// AuditFilter holds the filters for fetching audit entries. Empty fields are
+// not filtered on.
+type AuditFilter struct {
+	ActorID    string
+	EntityType string
+	EntityID   string
+	Since      NullTime
+	Until      NullTime
+}
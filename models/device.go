@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+)
+
+// Device operations, used for scoping what a device can do.
+const (
+	DeviceScanTickets = "scan_tickets"
+)
+
+// RoleDevice is the role of principals that are devices instead of users.
+// Devices don't have any of the user roles (see models.Role*).
+const RoleDevice = "Device"
+
+// Device represents a device, like a ticket scanner or a kiosk, that
+// authenticates with its own key instead of an user key.
+type Device struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	SecretHash string         `db:"secret_hash" json:"-"`
+	CreatedOn  time.Time      `db:"created_on" json:"createdOn"`
+	RotatedOn  NullTime       `db:"rotated_on" json:"rotatedOn"`
+	RevokedOn  NullTime       `db:"revoked_on" json:"revokedOn"`
+	Scopes     []*DeviceScope `json:"scopes"`
+}
+
+// DeviceScope represents an operation a device is allowed to do. If RideID is
+// not set, the operation is allowed on any ride.
+type DeviceScope struct {
+	Operation string     `json:"operation"`
+	RideID    NullString `db:"ride_id" json:"rideId"`
+}
+
+// IsActive returns true if the device has not been revoked.
+func (d *Device) IsActive() bool {
+	return !d.RevokedOn.Valid
+}
+
+// Allows returns true if the device is allowed to do the given operation on
+// the given ride.
+func (d *Device) Allows(operation, rideID string) bool {
+	for _, scope := range d.Scopes {
+		if scope.Operation != operation {
+			continue
+		}
+
+		if !scope.RideID.Valid || scope.RideID.String == rideID {
+			return true
+		}
+	}
+
+	return false
+}
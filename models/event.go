@@ -11,9 +11,7 @@ type Event struct {
 	PostedOn    time.Time  `db:"posted_on" json:"postedOn"`
 	EmployeeID  NullString `db:"employee_id" json:"employeeId"`
 
-	Email     NullString `json:"email"`
-	FirstName NullString `db:"first_name" json:"firstName"`
-	LastName  NullString `db:"last_name" json:"lastName"`
+	Employee *UserPublic `db:"employee" json:"employee"`
 }
 
 // EventType struct represents an event type.
@@ -21,3 +19,15 @@ type EventType struct {
 	ID     string `json:"id"`
 	String string `db:"event_type" json:"string"`
 }
+
+// EventTypeSystem is the event type used for events posted by the system. It
+// must exist in the `event_types` table.
+const EventTypeSystem = "System"
+
+// EventFilter holds the filters for fetching events. Empty fields are not
+// filtered on. Since and Until bound the time the events were posted on.
+type EventFilter struct {
+	EventType string
+	Since     NullTime
+	Until     NullTime
+}
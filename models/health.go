@@ -0,0 +1,27 @@
+package models
+
+// Health statuses.
+const (
+	HealthOK   = "ok"
+	HealthFail = "fail"
+)
+
+// HealthReport represents the result of checking whether the service and its
+// dependencies are working.
+type HealthReport struct {
+	Status string         `json:"status"`
+	Checks []*HealthCheck `json:"checks,omitempty"`
+}
+
+// HealthCheck represents the result of a single check in a HealthReport.
+type HealthCheck struct {
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Error    string `json:"error,omitempty"`
+	Duration string `json:"duration"`
+}
+
+// IsOK returns true if every check passed.
+func (hr *HealthReport) IsOK() bool {
+	return hr.Status == HealthOK
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// LoginAttempt represents a single log in attempt, successful or not.
+type LoginAttempt struct {
+	ID          string     `json:"id"`
+	Email       string     `json:"email"`
+	UserID      NullString `db:"user_id" json:"userId"`
+	UserAgent   NullString `db:"user_agent" json:"userAgent"`
+	Address     NullString `json:"address"`
+	Success     bool       `json:"success"`
+	Reason      NullString `json:"reason"`
+	AttemptedOn time.Time  `db:"attempted_on" json:"attemptedOn"`
+}
@@ -6,19 +6,19 @@ import (
 
 // Maintenance is a struct that contains maintenance details for a ride.
 type Maintenance struct {
-	ID              string    `json:"id"`
-	RideID          string    `db:"ride_id" json:"rideId"`
-	RideName        string    `db:"ride_name" json:"rideName"`
-	MaintenanceType string    `db:"maintenance_type" json:"maintenanceType"`
-	Description     string    `json:"description"`
-	Cost            float64   `json:"cost"`
-	Start           time.Time `db:"start_datetime" json:"start"`
-	End             NullTime  `db:"end_datetime" json:"end"`
-	Assignees       []*User   `json:"assignees"`
+	ID              string        `json:"id"`
+	RideID          string        `db:"ride_id" json:"rideId"`
+	RideName        string        `db:"ride_name" json:"rideName"`
+	MaintenanceType string        `db:"maintenance_type" json:"maintenanceType"`
+	Description     string        `json:"description"`
+	Cost            float64       `json:"cost"`
+	Start           time.Time     `db:"start_datetime" json:"start"`
+	End             NullTime      `db:"end_datetime" json:"end"`
+	Assignees       []*UserPublic `json:"assignees"`
 }
 
 // NewMaintenance returns a new Maintenance instance.
-func NewMaintenance(ID, rideID, rideName, maintenanceType, description string, cost float64, start time.Time, assignees []*User) *Maintenance {
+func NewMaintenance(ID, rideID, rideName, maintenanceType, description string, cost float64, start time.Time, assignees []*UserPublic) *Maintenance {
 	return &Maintenance{
 		ID:              ID,
 		RideID:          rideID,
@@ -30,3 +30,19 @@ func NewMaintenance(ID, rideID, rideName, maintenanceType, description string, c
 		Assignees:       assignees,
 	}
 }
+
+// Maintenance job statuses, closed jobs being the ones that ended.
+const (
+	MaintenanceOpen   = "open"
+	MaintenanceClosed = "closed"
+)
+
+// MaintenanceFilter holds the filters for fetching maintenance jobs. Empty
+// fields are not filtered on. Since and Until bound the start of the jobs.
+type MaintenanceFilter struct {
+	RideID          string
+	MaintenanceType string
+	Status          string
+	Since           NullTime
+	Until           NullTime
+}
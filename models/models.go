@@ -15,6 +15,11 @@ func FromSQLNullString(nullString sql.NullString) NullString {
 	return NullString{nullString}
 }
 
+// NewNullString returns a NullString holding s, which is null if s is empty.
+func NewNullString(s string) NullString {
+	return NullString{sql.NullString{String: s, Valid: len(s) > 0}}
+}
+
 func (ns *NullString) UnmarshalJSON(data []byte) error {
 
 	var x *string
@@ -75,3 +80,42 @@ func (nt *NullTime) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(nt.Time)
 }
+
+// NullJSON wraps sql.NullString holding a JSON document, so that it's
+// marshalled as JSON instead of as a string.
+type NullJSON struct {
+	sql.NullString
+}
+
+// NewNullJSON returns a NullJSON holding the JSON encoding of v, which is null
+// if v is nil.
+func NewNullJSON(v interface{}) (NullJSON, error) {
+	if v == nil {
+		return NullJSON{}, nil
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return NullJSON{}, err
+	}
+
+	return NullJSON{sql.NullString{String: string(data), Valid: true}}, nil
+}
+
+func (nj *NullJSON) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		nj.Valid = false
+		return nil
+	}
+
+	nj.String = string(data)
+	nj.Valid = true
+	return nil
+}
+
+func (nj *NullJSON) MarshalJSON() ([]byte, error) {
+	if !nj.Valid {
+		return json.Marshal(nil)
+	}
+	return []byte(nj.String), nil
+}
@@ -0,0 +1,26 @@
+package models
+
+// Bounds of the number of rows in a page.
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+// PageRequest asks for a page of a list: at most Limit rows, sorted by the
+// Sort field (descending if prefixed with "-", the default sort of the list if
+// empty), starting after the row the Cursor of the previous page points to.
+type PageRequest struct {
+	Limit  int
+	Sort   string
+	Cursor string
+}
+
+// NewPageRequest returns a new PageRequest instance for the first page, with
+// the default limit and sort.
+func NewPageRequest() *PageRequest {
+	return &PageRequest{
+		DefaultPageLimit,
+		"",
+		"",
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordReset represents a request to reset the password of an user. Only
+// the hash of the token handed out to the user is kept.
+type PasswordReset struct {
+	ID        string    `json:"id"`
+	UserID    string    `db:"user_id" json:"userId"`
+	TokenHash string    `db:"token_hash" json:"-"`
+	IssuedOn  time.Time `db:"issued_on" json:"issuedOn"`
+	ExpiresOn time.Time `db:"expires_on" json:"expiresOn"`
+	UsedOn    NullTime  `db:"used_on" json:"usedOn"`
+}
+
+// IsUsable returns true if the reset has not expired nor been used at the
+// given time.
+func (pr *PasswordReset) IsUsable(now time.Time) bool {
+	return !pr.UsedOn.Valid && now.Before(pr.ExpiresOn)
+}
@@ -0,0 +1,9 @@
+package models
+
+// Role represents an employee role and its policies.
+type Role struct {
+	ID                string  `json:"id"`
+	Role              string  `json:"role"`
+	HourlyRate        float32 `db:"hourly_rate" json:"hourlyRate"`
+	RequiresTwoFactor bool    `db:"requires_two_factor" json:"requiresTwoFactor"`
+}
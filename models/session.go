@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// Session represents a log in session for an user. Every key handed out on
+// log in maps to exactly one session.
+type Session struct {
+	ID        string     `json:"id"`
+	UserID    string     `db:"user_id" json:"userId"`
+	UserAgent NullString `db:"user_agent" json:"userAgent"`
+	Address   NullString `json:"address"`
+	IssuedOn  time.Time  `db:"issued_on" json:"issuedOn"`
+	ExpiresOn time.Time  `db:"expires_on" json:"expiresOn"`
+	RevokedOn NullTime   `db:"revoked_on" json:"revokedOn"`
+}
+
+// IsActive returns true if the session has not expired nor been revoked at
+// the given time.
+func (s *Session) IsActive(now time.Time) bool {
+	return !s.RevokedOn.Valid && now.Before(s.ExpiresOn)
+}
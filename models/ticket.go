@@ -14,20 +14,35 @@ type Ticket struct {
 	PurchaseReference string    `db:"purchase_reference" json:"purchaseReference"`
 	IsValid           bool      `db:"is_valid" json:"isValid"`
 
-	Email     string     `json:"email"`
-	FirstName NullString `db:"first_name" json:"firstName"`
-	LastName  NullString `db:"last_name" json:"lastName"`
+	User UserPublic `db:"user" json:"user"`
 }
 
 // TicketScan struct contains information about a ticket scan.
 type TicketScan struct {
-	ID       string    `json:"id"`
-	TicketID string    `db:"ticket_id" json:"ticketId"`
-	RideID   string    `db:"ride_id" json:"rideId"`
-	ScanOn   time.Time `db:"scan_datetime" json:"scanOn"`
+	ID       string     `json:"id"`
+	TicketID string     `db:"ticket_id" json:"ticketId"`
+	RideID   string     `db:"ride_id" json:"rideId"`
+	ScanOn   time.Time  `db:"scan_datetime" json:"scanOn"`
+	DeviceID NullString `db:"device_id" json:"deviceId"`
 
-	UserID    string     `db:"user_id" json:"userId"`
-	Email     string     `json:"email"`
-	FirstName NullString `db:"first_name" json:"firstName"`
-	LastName  NullString `db:"last_name" json:"lastName"`
+	UserID string     `db:"user_id" json:"userId"`
+	User   UserPublic `db:"user" json:"user"`
+}
+
+// TicketFilter holds the filters for fetching tickets. Empty fields are not
+// filtered on. Since and Until bound the time the tickets were purchased on.
+type TicketFilter struct {
+	UserID string
+	Since  NullTime
+	Until  NullTime
+}
+
+// TicketScanFilter holds the filters for fetching ticket scans. Empty fields
+// are not filtered on. Since and Until bound the time of the scans.
+type TicketScanFilter struct {
+	RideID   string
+	UserID   string
+	TicketID string
+	Since    NullTime
+	Until    NullTime
 }
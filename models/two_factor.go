@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+// TwoFactor represents the TOTP two-factor authentication of an user. It's
+// pending until the user confirms it with a valid code.
+type TwoFactor struct {
+	UserID    string    `db:"user_id" json:"userId"`
+	Secret    string    `json:"-"`
+	CreatedOn time.Time `db:"created_on" json:"createdOn"`
+	EnabledOn NullTime  `db:"enabled_on" json:"enabledOn"`
+	LastStep  int64     `db:"last_step" json:"-"`
+}
+
+// IsEnabled returns true if the two-factor authentication was confirmed.
+func (tf *TwoFactor) IsEnabled() bool {
+	return tf.EnabledOn.Valid
+}
+
+// TwoFactorEnrollment represents what an user needs for adding the two-factor
+// authentication to an authenticator app.
+type TwoFactorEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+// RecoveryCode represents a single use code that can be used instead of a
+// TOTP code, e.g. when the authenticator app is lost.
+type RecoveryCode struct {
+	ID       string   `json:"id"`
+	UserID   string   `db:"user_id" json:"userId"`
+	CodeHash string   `db:"code_hash" json:"-"`
+	UsedOn   NullTime `db:"used_on" json:"usedOn"`
+}
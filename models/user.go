@@ -5,13 +5,24 @@ import (
 	"time"
 )
 
+// Authorization roles. Every user is a customer, employees are users that
+// are also in the `employees` table, and supervisors are employees with the
+// "Supervisor" role from the `roles` table.
+const (
+	RoleCustomer   = "Customer"
+	RoleEmployee   = "Employee"
+	RoleSupervisor = "Supervisor"
+)
+
 // User struct represents an user in the system. Note that this struct might
-// be the composition of one or more tables.
+// be the composition of one or more tables. This is the full shape of an user,
+// meant for the user itself and for employees. Credentials are never
+// serialized.
 type User struct {
 	ID           string    `json:"id"`
 	Email        string    `json:"email"`
-	PasswordSalt string    `db:"password_salt" json:"passwordSalt"`
-	PasswordHash string    `db:"password_hash" json:"passwordHash"`
+	PasswordSalt string    `db:"password_salt" json:"-"`
+	PasswordHash string    `db:"password_hash" json:"-"`
 	RegisteredOn time.Time `db:"registered_on" json:"registeredOn"`
 
 	Gender      NullString `json:"gender"`
@@ -24,6 +35,17 @@ type User struct {
 	IsEmployee bool       `db:"is_employee" json:"isEmployee"`
 	Role       NullString `json:"role"`
 	HourlyRate float32    `db:"hourly_rate" json:"hourlyRate"`
+
+	TwoFactorEnabled  bool `db:"two_factor_enabled" json:"twoFactorEnabled"`
+	RequiresTwoFactor bool `db:"requires_two_factor" json:"requiresTwoFactor"`
+}
+
+// UserPublic struct represents the public shape of an user, used whenever an
+// user is shown to other users (e.g. maintenance assignees, event authors).
+type UserPublic struct {
+	ID        string     `json:"id"`
+	FirstName NullString `db:"first_name" json:"firstName"`
+	LastName  NullString `db:"last_name" json:"lastName"`
 }
 
 // NewCustomer returns a new User instance that is a customer.
@@ -51,3 +73,38 @@ func NewEmployee(ID, email, passwordSalt, passwordHash, role string, hourlyRate
 		HourlyRate:   hourlyRate,
 	}
 }
+
+// NeedsTwoFactor returns true if the role of the user requires two-factor
+// authentication but the user has not enabled it yet.
+func (u *User) NeedsTwoFactor() bool {
+	return u.RequiresTwoFactor && !u.TwoFactorEnabled
+}
+
+// HasRole returns true if the user has the given authorization role.
+func (u *User) HasRole(role string) bool {
+	switch role {
+	case RoleCustomer:
+		return true
+	case RoleEmployee:
+		return u.IsEmployee
+	case RoleSupervisor:
+		return u.IsEmployee && u.Role.Valid && u.Role.String == RoleSupervisor
+	}
+	return false
+}
+
+// Public returns the public shape of the user.
+func (u *User) Public() *UserPublic {
+	return &UserPublic{
+		ID:        u.ID,
+		FirstName: u.FirstName,
+		LastName:  u.LastName,
+	}
+}
+
+// UserFilter holds the filters for fetching users. Empty fields are not
+// filtered on.
+type UserFilter struct {
+	IsEmployee sql.NullBool
+	Role       string
+}
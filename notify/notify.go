@@ -0,0 +1,20 @@
+// Package notify delivers messages to users, such as password reset tokens.
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// Message represents a message to be delivered to an user.
+type Message struct {
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	Body    string    `json:"body"`
+	SentOn  time.Time `json:"sentOn"`
+}
+
+// Notifier defines the interface for delivering messages.
+type Notifier interface {
+	Notify(ctx context.Context, message *Message) error
+}
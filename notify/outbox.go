@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// OutboxNotifier implements the Notifier interface by appending messages, one
+// JSON object per line, to a local file. It's meant for development and for
+// testing without a mail server.
+type OutboxNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewOutboxNotifier returns a new OutboxNotifier instance that writes to the
+// file at the given path.
+func NewOutboxNotifier(path string) *OutboxNotifier {
+	return &OutboxNotifier{path: path}
+}
+
+// Notify appends the given message to the outbox file.
+func (on *OutboxNotifier) Notify(ctx context.Context, message *Message) error {
+	if message.SentOn.IsZero() {
+		message.SentOn = time.Now().UTC()
+	}
+
+	messageBytes, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("notify: %s", err)
+	}
+
+	on.mu.Lock()
+	defer on.mu.Unlock()
+
+	file, err := os.OpenFile(on.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("notify: %s", err)
+	}
+
+	_, err = file.Write(append(messageBytes, '\n'))
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("notify: %s", err)
+	}
+
+	return file.Close()
+}
@@ -0,0 +1,66 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/notify"
+)
+
+func TestOutboxNotifierSucceeds(t *testing.T) {
+	dir, err := ioutil.TempDir("", "outbox")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "outbox.jsonl")
+	notifier := notify.NewOutboxNotifier(path)
+
+	tests := []*notify.Message{
+		{To: "customer0@email.com", Subject: "subject0", Body: "body0"},
+		{To: "customer1@email.com", Subject: "subject1", Body: "body1\nwith new line"},
+	}
+
+	for _, tt := range tests {
+		err := notifier.Notify(context.Background(), tt)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+	}
+
+	file, err := os.Open(path)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	defer file.Close()
+
+	messages := []*notify.Message{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		message := &notify.Message{}
+		err := json.Unmarshal(scanner.Bytes(), message)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+		messages = append(messages, message)
+	}
+
+	if !assert.Len(t, messages, len(tests)) {
+		t.FailNow()
+	}
+
+	for idx, tt := range tests {
+		assert.Equal(t, tt.To, messages[idx].To)
+		assert.Equal(t, tt.Subject, messages[idx].Subject)
+		assert.Equal(t, tt.Body, messages[idx].Body)
+		assert.False(t, messages[idx].SentOn.IsZero())
+	}
+}
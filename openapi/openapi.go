@@ -0,0 +1,293 @@
+// Package openapi builds OpenAPI 3 documents from a list of echo routes, with
+// schemas generated from Go types.
+package openapi
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Version is the version of the OpenAPI specification used.
+const Version = "3.0.3"
+
+// Document is an OpenAPI document.
+type Document struct {
+	OpenAPI    string                           `json:"openapi"`
+	Info       Info                             `json:"info"`
+	Paths      map[string]map[string]*Operation `json:"paths"`
+	Components Components                       `json:"components"`
+	Security   []SecurityRequirement            `json:"security,omitempty"`
+	Tags       []Tag                            `json:"tags,omitempty"`
+
+	generator *Generator
+	errorBody *Schema
+}
+
+// Info holds the title and version of the API.
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Tag groups operations in the UI.
+type Tag struct {
+	Name string `json:"name"`
+}
+
+// Components holds the schemas and security schemes that are referenced
+// elsewhere in the document.
+type Components struct {
+	Schemas         map[string]*Schema        `json:"schemas"`
+	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+}
+
+// SecurityScheme describes how requests are authenticated.
+type SecurityScheme struct {
+	Type   string `json:"type"`
+	Scheme string `json:"scheme,omitempty"`
+	Name   string `json:"name,omitempty"`
+	In     string `json:"in,omitempty"`
+}
+
+// SecurityRequirement lists the security schemes, by name, that a request
+// must satisfy.
+type SecurityRequirement map[string][]string
+
+// Operation is a single method on a path.
+type Operation struct {
+	Summary     string                 `json:"summary,omitempty"`
+	Tags        []string               `json:"tags,omitempty"`
+	Parameters  []Parameter            `json:"parameters,omitempty"`
+	RequestBody *RequestBody           `json:"requestBody,omitempty"`
+	Responses   map[string]Response    `json:"responses"`
+	Security    *[]SecurityRequirement `json:"security,omitempty"`
+	Deprecated  bool                   `json:"deprecated,omitempty"`
+}
+
+// Parameter is a path or query parameter.
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody is the body of a request.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response is the response for a status code.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body for a content type.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Route documents a route registered in echo.
+type Route struct {
+	Method  string
+	Path    string // as registered in echo, e.g. "/rides/:rideID"
+	Summary string
+	Tag     string
+	Query   []Parameter
+
+	// Request and Response are samples of the JSON bodies (e.g.
+	// &models.Ride{} or []*models.Ride{}), nil if there is none.
+	Request  interface{}
+	Response interface{}
+
+	// Status is the status of a successful response, http.StatusOK if zero.
+	Status int
+
+	// OtherResponses are samples of the JSON bodies of other responses, by
+	// status, besides errors.
+	OtherResponses map[int]interface{}
+
+	// ContentType is the type of a successful response, "application/json"
+	// if empty.
+	ContentType string
+
+	// AlternateTypes are other content types a successful response can be
+	// sent as, e.g. "text/csv", documented as files.
+	AlternateTypes []string
+
+	// Public routes don't require a key.
+	Public bool
+
+	// Deprecated routes are still served, but will be removed.
+	Deprecated bool
+}
+
+// New returns a new Document. The given generator is used for the schemas of
+// request and response bodies, and errorBody is a sample of the JSON body of
+// error responses.
+func New(info Info, generator *Generator, errorBody interface{}) *Document {
+	d := &Document{
+		OpenAPI: Version,
+		Info:    info,
+		Paths:   map[string]map[string]*Operation{},
+		Components: Components{
+			Schemas:         generator.Schemas(),
+			SecuritySchemes: map[string]SecurityScheme{},
+		},
+		generator: generator,
+	}
+	d.errorBody = generator.Schema(errorBody)
+	return d
+}
+
+// AddSecurityScheme adds a security scheme that every route but public ones
+// require. If several are added, any of them can be used.
+func (d *Document) AddSecurityScheme(name string, scheme SecurityScheme) {
+	d.Components.SecuritySchemes[name] = scheme
+	d.Security = append(d.Security, SecurityRequirement{name: {}})
+}
+
+// Add adds the given routes to the document.
+func (d *Document) Add(routes ...Route) {
+	for _, route := range routes {
+		d.add(route)
+	}
+}
+
+func (d *Document) add(route Route) {
+	operation := &Operation{
+		Summary:    route.Summary,
+		Responses:  map[string]Response{},
+		Deprecated: route.Deprecated,
+	}
+
+	if len(route.Tag) > 0 {
+		operation.Tags = []string{route.Tag}
+		d.addTag(route.Tag)
+	}
+
+	for _, name := range pathParams(route.Path) {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:     name,
+			In:       "path",
+			Required: true,
+			Schema:   &Schema{Type: "string"},
+		})
+	}
+
+	for _, param := range route.Query {
+		param.In = "query"
+		if param.Schema == nil {
+			param.Schema = &Schema{Type: "string"}
+		}
+		operation.Parameters = append(operation.Parameters, param)
+	}
+
+	if route.Request != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]MediaType{"application/json": {d.generator.Schema(route.Request)}},
+		}
+	}
+
+	status := route.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	contentType := route.ContentType
+	if len(contentType) <= 0 {
+		contentType = "application/json"
+	}
+
+	response := Response{Description: http.StatusText(status)}
+	if route.Response != nil {
+		response.Content = map[string]MediaType{contentType: {d.generator.Schema(route.Response)}}
+	} else if contentType != "application/json" {
+		response.Content = map[string]MediaType{contentType: {&Schema{Type: "string"}}}
+	}
+
+	for _, alternate := range route.AlternateTypes {
+		if response.Content == nil {
+			response.Content = map[string]MediaType{}
+		}
+		response.Content[alternate] = MediaType{&Schema{Type: "string", Format: "binary"}}
+	}
+
+	operation.Responses[strconv.Itoa(status)] = response
+	for status, body := range route.OtherResponses {
+		operation.Responses[strconv.Itoa(status)] = Response{
+			Description: http.StatusText(status),
+			Content:     map[string]MediaType{"application/json": {d.generator.Schema(body)}},
+		}
+	}
+	operation.Responses["default"] = Response{
+		Description: "Error",
+		Content:     map[string]MediaType{"application/json": {d.errorBody}},
+	}
+
+	if route.Public {
+		operation.Security = &[]SecurityRequirement{}
+	}
+
+	path := Path(route.Path)
+	if d.Paths[path] == nil {
+		d.Paths[path] = map[string]*Operation{}
+	}
+	d.Paths[path][strings.ToLower(route.Method)] = operation
+}
+
+func (d *Document) addTag(name string) {
+	for _, tag := range d.Tags {
+		if tag.Name == name {
+			return
+		}
+	}
+	d.Tags = append(d.Tags, Tag{name})
+}
+
+// Has returns true if the document has an operation for the given method and
+// echo path.
+func (d *Document) Has(method, path string) bool {
+	_, ok := d.Paths[Path(path)][strings.ToLower(method)]
+	return ok
+}
+
+// Operations returns every operation in the document as "METHOD /path", with
+// paths as written in echo, sorted.
+func (d *Document) Operations() []string {
+	operations := []string{}
+	for path, item := range d.Paths {
+		for method := range item {
+			operations = append(operations, strings.ToUpper(method)+" "+echoParam.ReplaceAllString(path, ":$1"))
+		}
+	}
+	sort.Strings(operations)
+	return operations
+}
+
+var (
+	pathParam = regexp.MustCompile(`:(\w+)`)
+	echoParam = regexp.MustCompile(`\{(\w+)\}`)
+)
+
+// Path returns the OpenAPI path for the given echo path, e.g. "/rides/{rideID}"
+// for "/rides/:rideID".
+func Path(path string) string {
+	return pathParam.ReplaceAllString(path, "{$1}")
+}
+
+func pathParams(path string) []string {
+	names := []string{}
+	for _, match := range pathParam.FindAllStringSubmatch(path, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
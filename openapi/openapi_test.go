@@ -0,0 +1,63 @@
+package openapi_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/openapi"
+)
+
+type base struct {
+	ID string `json:"id"`
+}
+
+type ride struct {
+	base
+	Name      string    `json:"name"`
+	MinAge    int       `json:"minAge"`
+	OpenedOn  time.Time `json:"openedOn"`
+	Secret    string    `json:"-"`
+	Tags      []string  `json:"tags"`
+	Reviews   []*review `json:"reviews"`
+	unchecked bool
+}
+
+type review struct {
+	Rating int `json:"rating"`
+}
+
+func TestGeneratorFollowsJSONEncoding(t *testing.T) {
+	generator := openapi.NewGenerator()
+	schema := generator.Schema([]*ride{})
+
+	assert.Equal(t, "array", schema.Type)
+	assert.Equal(t, "#/components/schemas/Ride", schema.Items.Ref)
+
+	rideSchema := generator.Schemas()["Ride"]
+	if assert.NotNil(t, rideSchema) {
+		assert.Len(t, rideSchema.Properties, 6)
+		assert.Equal(t, "string", rideSchema.Properties["id"].Type)
+		assert.Equal(t, "integer", rideSchema.Properties["minAge"].Type)
+		assert.Equal(t, "date-time", rideSchema.Properties["openedOn"].Format)
+		assert.Equal(t, "#/components/schemas/Review", rideSchema.Properties["reviews"].Items.Ref)
+	}
+}
+
+func TestDocumentHasRoutes(t *testing.T) {
+	document := openapi.New(openapi.Info{Title: "test", Version: "1"}, openapi.NewGenerator(), struct {
+		Error string `json:"error"`
+	}{})
+	document.Add(openapi.Route{Method: "GET", Path: "/rides/:rideID", Response: &ride{}})
+
+	assert.True(t, document.Has("GET", "/rides/:rideID"))
+	assert.False(t, document.Has("DELETE", "/rides/:rideID"))
+	assert.Equal(t, []string{"GET /rides/:rideID"}, document.Operations())
+
+	operation := document.Paths["/rides/{rideID}"]["get"]
+	if assert.Len(t, operation.Parameters, 1) {
+		assert.Equal(t, "rideID", operation.Parameters[0].Name)
+		assert.Equal(t, "path", operation.Parameters[0].In)
+	}
+}
@@ -0,0 +1,161 @@
+package openapi
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Schema is a JSON schema, as used by OpenAPI.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// Generator generates schemas from Go types, following their JSON encoding.
+// Named struct types are added as components and referenced by name.
+type Generator struct {
+	schemas   map[string]*Schema
+	types     map[string]reflect.Type
+	overrides map[reflect.Type]*Schema
+}
+
+// NewGenerator returns a new Generator.
+func NewGenerator() *Generator {
+	return &Generator{
+		map[string]*Schema{},
+		map[string]reflect.Type{},
+		map[reflect.Type]*Schema{
+			reflect.TypeOf(time.Time{}): {Type: "string", Format: "date-time"},
+		},
+	}
+}
+
+// Override sets the schema used for the type of the given sample, for types
+// with a custom JSON encoding.
+func (g *Generator) Override(sample interface{}, schema *Schema) {
+	g.overrides[reflect.TypeOf(sample)] = schema
+}
+
+// Schemas returns the component schemas generated so far, by name.
+func (g *Generator) Schemas() map[string]*Schema {
+	return g.schemas
+}
+
+// Schema returns the schema for the type of the given sample.
+func (g *Generator) Schema(sample interface{}) *Schema {
+	return g.schemaFor(reflect.TypeOf(sample))
+}
+
+func (g *Generator) schemaFor(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if schema, ok := g.overrides[t]; ok {
+		copied := *schema
+		return &copied
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.Struct:
+		if len(t.Name()) <= 0 {
+			return g.structSchema(t)
+		}
+		return g.component(t)
+	}
+
+	// interfaces and anything else can be any value
+	return &Schema{}
+}
+
+// component adds the given named struct type as a component, if not already
+// added, and returns a reference to it.
+func (g *Generator) component(t reflect.Type) *Schema {
+	name := componentName(t)
+	ref := &Schema{Ref: "#/components/schemas/" + name}
+
+	if existing, ok := g.types[name]; ok {
+		if existing != t {
+			panic(fmt.Sprintf("openapi: %s and %s have the same component name", existing, t))
+		}
+		return ref
+	}
+
+	g.types[name] = t
+	g.schemas[name] = &Schema{} // placeholder for recursive types
+	*g.schemas[name] = *g.structSchema(t)
+	return ref
+}
+
+func (g *Generator) structSchema(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	g.addFields(schema, t)
+	return schema
+}
+
+func (g *Generator) addFields(schema *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+
+		if field.Anonymous && len(name) <= 0 {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				g.addFields(schema, embedded)
+				continue
+			}
+		}
+
+		if len(field.PkgPath) > 0 {
+			continue // unexported
+		}
+
+		if len(name) <= 0 {
+			name = field.Name
+		}
+
+		schema.Properties[name] = g.schemaFor(field.Type)
+	}
+}
+
+// componentName returns the name of the component for the given type, which
+// is its name with the first letter in upper case.
+func componentName(t reflect.Type) string {
+	name := []rune(t.Name())
+	name[0] = unicode.ToUpper(name[0])
+	return string(name)
+}
@@ -0,0 +1,23 @@
+-- frequently_ridden_rides_monthly: the most ridden ride of each month.
+-- $1 and $2 are the first and last months, either can be null.
+SELECT
+    EXTRACT(YEAR FROM t.year_month)::integer AS year,
+    EXTRACT(MONTH FROM t.year_month)::integer AS month,
+    TRIM(TO_CHAR(t.year_month, 'Month')) AS month_name,
+    t.ride_id AS ride_id,
+    rides.name AS ride_name,
+    t.times_ridden AS times_ridden
+FROM (
+    SELECT
+        DATE_TRUNC('month', scan_datetime) AS year_month,
+        tickets_on_rides.ride_id AS ride_id,
+        COUNT(*) AS times_ridden,
+        RANK() OVER (PARTITION BY DATE_TRUNC('month', scan_datetime) ORDER BY COUNT(*) DESC) AS rnk
+    FROM tickets_on_rides
+    WHERE ($1::timestamp IS NULL OR scan_datetime >= DATE_TRUNC('month', $1::timestamp))
+        AND ($2::timestamp IS NULL OR scan_datetime < DATE_TRUNC('month', $2::timestamp) + '1 month'::interval)
+    GROUP BY DATE_TRUNC('month', scan_datetime), ride_id
+) AS t
+JOIN rides ON rides.id = t.ride_id
+WHERE t.rnk = 1
+ORDER BY year DESC, month DESC
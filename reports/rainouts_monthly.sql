@@ -0,0 +1,14 @@
+-- rainouts_monthly: how many rainout events were posted each month.
+-- $1 and $2 are the first and last months, either can be null.
+SELECT
+    EXTRACT(YEAR FROM posted_on)::integer AS year,
+    EXTRACT(MONTH FROM posted_on)::integer AS month,
+    TRIM(TO_CHAR(posted_on, 'Month')) AS month_name,
+    COUNT(*) AS total_rainout
+FROM events
+JOIN event_types ON event_types.id = events.event_type_id
+WHERE event_types.event_type ILIKE '%rainout%'
+    AND ($1::timestamp IS NULL OR posted_on >= DATE_TRUNC('month', $1::timestamp))
+    AND ($2::timestamp IS NULL OR posted_on < DATE_TRUNC('month', $2::timestamp) + '1 month'::interval)
+GROUP BY year, month, month_name
+ORDER BY year DESC, month DESC
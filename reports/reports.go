@@ -0,0 +1,146 @@
+// Package reports holds the queries of the reports, embedded from the SQL
+// files in this folder, and the types of their rows.
+package reports
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+//go:embed *.sql
+var queries embed.FS
+
+// Range is the range of months a report covers, from the month of Start to
+// the month of End, both included. Either can be null for no bound.
+type Range struct {
+	Start sql.NullTime
+	End   sql.NullTime
+}
+
+// IsSet returns true if either bound of the range is set.
+func (r Range) IsSet() bool {
+	return r.Start.Valid || r.End.Valid
+}
+
+// Report is a named query. Ranged reports take the start and end of a Range
+// as their $1 and $2 parameters, which can be null.
+type Report struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Ranged      bool   `json:"ranged"`
+
+	query   string
+	newRows func() interface{}
+}
+
+// Query returns the SQL of the report.
+func (r *Report) Query() string {
+	return r.query
+}
+
+// NewRows returns a pointer to an empty slice of the rows of the report, to
+// scan the results into.
+func (r *Report) NewRows() interface{} {
+	return r.newRows()
+}
+
+// CustomerScanCount is a row of the customer_scan_count report.
+type CustomerScanCount struct {
+	UserID        string            `db:"user_id" json:"userId"`
+	UserEmail     string            `db:"user_email" json:"userEmail"`
+	UserFirstName models.NullString `db:"user_first_name" json:"userFirstName"`
+	UserLastName  models.NullString `db:"user_last_name" json:"userLastName"`
+	ScanCount     int               `db:"scan_count" json:"scanCount"`
+}
+
+// Month identifies the month of a row in monthly reports.
+type Month struct {
+	Year      int    `db:"year" json:"year"`
+	Month     int    `db:"month" json:"month"`
+	MonthName string `db:"month_name" json:"monthName"`
+}
+
+// MonthlyTopRide is a row of the frequently_ridden_rides_monthly report.
+type MonthlyTopRide struct {
+	Month
+	RideID      string `db:"ride_id" json:"rideId"`
+	RideName    string `db:"ride_name" json:"rideName"`
+	TimesRidden int    `db:"times_ridden" json:"timesRidden"`
+}
+
+// MonthlyRainouts is a row of the rainouts_monthly report.
+type MonthlyRainouts struct {
+	Month
+	TotalRainouts int `db:"total_rainout" json:"totalRainouts"`
+}
+
+// MonthlyRevenue is a row of the revenue_maintenance_vs_tickets report.
+type MonthlyRevenue struct {
+	Month
+	TicketSales      float64 `db:"ticket_sales" json:"ticketSales"`
+	MaintenanceCosts float64 `db:"maintenance_costs" json:"maintenanceCosts"`
+	Revenue          float64 `db:"revenue" json:"revenue"`
+}
+
+// MonthlyRideMaintenance is a row of the ride_maintenance_monthly report.
+type MonthlyRideMaintenance struct {
+	Month
+	RideID             string `db:"ride_id" json:"rideId"`
+	RideName           string `db:"ride_name" json:"rideName"`
+	MaintenanceStarted int    `db:"maintenance_start_total" json:"maintenanceStarted"`
+	MaintenanceClosed  int    `db:"maintenance_end_total" json:"maintenanceClosed"`
+}
+
+// RideReviewSummary is a row of the ride_reviews report. The average is null
+// for rides without reviews.
+type RideReviewSummary struct {
+	RideID        string   `db:"ride_id" json:"rideId"`
+	RideName      string   `db:"ride_name" json:"rideName"`
+	ReviewCount   int      `db:"review_count" json:"reviewCount"`
+	ReviewAverage *float64 `db:"review_avg" json:"reviewAverage"`
+}
+
+// all holds every report, in the order they are listed.
+var all = []*Report{
+	newReport("customer_scan_count", "How many times each customer had a ticket scanned", false, func() interface{} { return &[]*CustomerScanCount{} }),
+	newReport("frequently_ridden_rides_monthly", "The most ridden ride of each month", true, func() interface{} { return &[]*MonthlyTopRide{} }),
+	newReport("rainouts_monthly", "How many rainouts were posted each month", true, func() interface{} { return &[]*MonthlyRainouts{} }),
+	newReport("revenue_maintenance_vs_tickets", "Ticket sales against maintenance costs, for each month", true, func() interface{} { return &[]*MonthlyRevenue{} }),
+	newReport("ride_maintenance_monthly", "Maintenance jobs started and closed on each ride, for each month", true, func() interface{} { return &[]*MonthlyRideMaintenance{} }),
+	newReport("ride_reviews", "How many reviews each ride has, and their average rating", false, func() interface{} { return &[]*RideReviewSummary{} }),
+}
+
+// newReport returns a new Report instance with the query in the SQL file of
+// the same name. It panics if there is no such file.
+func newReport(name, description string, ranged bool, newRows func() interface{}) *Report {
+	query, err := queries.ReadFile(name + ".sql")
+	if err != nil {
+		panic(fmt.Sprintf("reports: %s", err))
+	}
+
+	return &Report{
+		name,
+		description,
+		ranged,
+		string(query),
+		newRows,
+	}
+}
+
+// All returns every report.
+func All() []*Report {
+	return all
+}
+
+// Get returns the report with the given name, if any.
+func Get(name string) (*Report, bool) {
+	for _, report := range all {
+		if report.Name == name {
+			return report, true
+		}
+	}
+	return nil, false
+}
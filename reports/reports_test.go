@@ -0,0 +1,36 @@
+package reports_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/reports"
+)
+
+func TestReportsUseBoundParameters(t *testing.T) {
+	names := map[string]bool{}
+	for _, report := range reports.All() {
+		assert.False(t, names[report.Name], "%s is listed twice", report.Name)
+		names[report.Name] = true
+
+		query := report.Query()
+		assert.NotContains(t, query, "{{", report.Name)
+		assert.Equal(t, report.Ranged, strings.Contains(query, "$1"), report.Name)
+		assert.Equal(t, report.Ranged, strings.Contains(query, "$2"), report.Name)
+		assert.NotContains(t, query, "$3", report.Name)
+		assert.NotNil(t, report.NewRows(), report.Name)
+	}
+}
+
+func TestGet(t *testing.T) {
+	report, ok := reports.Get("ride_reviews")
+	if assert.True(t, ok) {
+		assert.Equal(t, "ride_reviews", report.Name)
+		assert.False(t, report.Ranged)
+	}
+
+	_, ok = reports.Get("../ddl/migrations/0001_schema.up")
+	assert.False(t, ok)
+}
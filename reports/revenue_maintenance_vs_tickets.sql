@@ -0,0 +1,28 @@
+-- revenue_maintenance_vs_tickets: ticket sales against maintenance costs, for
+-- each month. $1 and $2 are the first and last months, either can be null.
+SELECT
+    EXTRACT(YEAR FROM COALESCE(tickets_sold.year_month, maintenance_costs.year_month))::integer AS year,
+    EXTRACT(MONTH FROM COALESCE(tickets_sold.year_month, maintenance_costs.year_month))::integer AS month,
+    TRIM(TO_CHAR(COALESCE(tickets_sold.year_month, maintenance_costs.year_month), 'Month')) AS month_name,
+    COALESCE(tickets_sold.total, 0)::float8 AS ticket_sales,
+    COALESCE(maintenance_costs.total, 0)::float8 AS maintenance_costs,
+    (COALESCE(tickets_sold.total, 0) - COALESCE(maintenance_costs.total, 0))::float8 AS revenue
+FROM (
+    SELECT
+        DATE_TRUNC('month', tickets.purchased_on) AS year_month,
+        SUM(tickets.purchase_price) AS total
+    FROM tickets
+    GROUP BY year_month
+) AS tickets_sold
+FULL OUTER JOIN (
+    SELECT
+        DATE_TRUNC('month', rides_maintenance.end_datetime) AS year_month,
+        SUM(rides_maintenance.cost) AS total
+    FROM rides_maintenance
+    WHERE rides_maintenance.end_datetime IS NOT NULL
+    GROUP BY year_month
+) AS maintenance_costs
+ON maintenance_costs.year_month = tickets_sold.year_month
+WHERE ($1::timestamp IS NULL OR COALESCE(tickets_sold.year_month, maintenance_costs.year_month) >= DATE_TRUNC('month', $1::timestamp))
+    AND ($2::timestamp IS NULL OR COALESCE(tickets_sold.year_month, maintenance_costs.year_month) <= DATE_TRUNC('month', $2::timestamp))
+ORDER BY year DESC, month DESC
@@ -1,6 +1,8 @@
+-- ride_maintenance_monthly: maintenance jobs started and closed on each ride,
+-- for each month. $1 and $2 are the first and last months, either can be null.
 SELECT
-    EXTRACT(YEAR FROM maintenance_start.year_month) AS year,
-    EXTRACT(MONTH FROM maintenance_start.year_month) AS month,
+    EXTRACT(YEAR FROM maintenance_start.year_month)::integer AS year,
+    EXTRACT(MONTH FROM maintenance_start.year_month)::integer AS month,
     TRIM(TO_CHAR(maintenance_start.year_month, 'Month')) AS month_name,
     rides.id AS ride_id,
     rides.name AS ride_name,
@@ -12,25 +14,20 @@ FROM (
         DATE_TRUNC('month', rides_maintenance.start_datetime) AS year_month,
         rides_maintenance.ride_id AS ride_id,
         COUNT(*) AS total
-    FROM theme_park.rides_maintenance
-    WHERE 1=1
-    {{ if isSet "start" }}
-        AND rides_maintenance.start_datetime >= DATE_TRUNC('month', '{{.start}}'::timestamptz)
-    {{ end }}
-    {{ if isSet "end" }}
-        AND rides_maintenance.start_datetime <= DATE_TRUNC('month', '{{.end}}'::timestamptz + '1 month'::interval)
-    {{ end }}
+    FROM rides_maintenance
+    WHERE ($1::timestamp IS NULL OR rides_maintenance.start_datetime >= DATE_TRUNC('month', $1::timestamp))
+        AND ($2::timestamp IS NULL OR rides_maintenance.start_datetime < DATE_TRUNC('month', $2::timestamp) + '1 month'::interval)
     GROUP BY year_month, ride_id
 ) AS maintenance_start
 -- join for rides information
-JOIN theme_park.rides ON rides.id = maintenance_start.ride_id
+JOIN rides ON rides.id = maintenance_start.ride_id
 -- sub-query to get total maintenance closed per-month
 LEFT JOIN (
     SELECT
         DATE_TRUNC('month', rides_maintenance.end_datetime) AS year_month,
         rides_maintenance.ride_id AS ride_id,
         COUNT(*) AS total
-    FROM theme_park.rides_maintenance
+    FROM rides_maintenance
     WHERE rides_maintenance.end_datetime IS NOT NULL
     GROUP BY year_month, ride_id
 ) AS maintenance_end
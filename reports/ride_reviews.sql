@@ -0,0 +1,11 @@
+-- ride_reviews: how many reviews each ride has, and their average rating
+-- (null if none).
+SELECT
+    rides.id AS ride_id,
+    rides.name AS ride_name,
+    COUNT(reviews.*) AS review_count,
+    AVG(reviews.rating)::float8 AS review_avg
+FROM rides
+LEFT JOIN reviews ON reviews.ride_id = rides.id
+GROUP BY rides.id
+ORDER BY ride_name ASC
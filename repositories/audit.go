@@ -0,0 +1,13 @@
+package repositories
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// AuditRepository defines the interface for working with the audit log.
+type AuditRepository interface {
+	Fetch(ctx context.Context, filter *models.AuditFilter) ([]*models.AuditEntry, error)
+	Store(ctx context.Context, entry *models.AuditEntry) error
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"context"
+
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// DeviceRepository defines the interface for working with devices.
+type DeviceRepository interface {
+	GetByID(ctx context.Context, ID string) (*models.Device, error)
+	Fetch(ctx context.Context) ([]*models.Device, error)
+
+	Store(ctx context.Context, device *models.Device) error
+	Update(ctx context.Context, device *models.Device) error
+	UpdateSecret(ctx context.Context, ID, secretHash string, rotatedOn time.Time) error
+	Revoke(ctx context.Context, ID string, revokedOn time.Time) error
+}
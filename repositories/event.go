@@ -1,18 +1,19 @@
 package repositories
 
 import (
-	"time"
+	"context"
 
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 )
 
-// EventRepository defines the interface for interacting with events.
+// EventRepository defines the interface for interacting with events. Events
+// are fetched a page at a time (every event if the page is nil), along with
+// the cursor of the next page, empty on the last one.
 type EventRepository interface {
-	GetByID(ID string) (*models.Event, error)
-	Fetch() ([]*models.Event, error)
-	FetchSince(since time.Time) ([]*models.Event, error)
-	Store(event *models.Event) error
-	Update(event *models.Event) error
-	Delete(eventID string) error
-	AvailableEventTypes() ([]*models.EventType, error)
+	GetByID(ctx context.Context, ID string) (*models.Event, error)
+	Fetch(ctx context.Context, filter *models.EventFilter, page *models.PageRequest) ([]*models.Event, string, error)
+	Store(ctx context.Context, event *models.Event) error
+	Update(ctx context.Context, event *models.Event) error
+	Delete(ctx context.Context, eventID string) error
+	AvailableEventTypes(ctx context.Context) ([]*models.EventType, error)
 }
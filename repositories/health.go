@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+)
+
+// HealthRepository defines the interface for checking that the database is
+// reachable and set up.
+type HealthRepository interface {
+	Ping(ctx context.Context) error
+	SchemaExists(ctx context.Context, schema string) (bool, error)
+	EventTypeExists(ctx context.Context, eventType string) (bool, error)
+	RoleExists(ctx context.Context, role string) (bool, error)
+}
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// LoginAttemptRepository defines the interface for working with log in
+// attempts.
+type LoginAttemptRepository interface {
+	FetchSince(ctx context.Context, since time.Time) ([]*models.LoginAttempt, error)
+	FetchForEmailSince(ctx context.Context, email string, since time.Time) ([]*models.LoginAttempt, error)
+
+	Store(ctx context.Context, attempt *models.LoginAttempt) error
+}
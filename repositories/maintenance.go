@@ -1,16 +1,19 @@
 package repositories
 
 import (
+	"context"
+
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 )
 
-// MaintenanceRepository defines the interface for working with maintenance jobs.
+// MaintenanceRepository defines the interface for working with maintenance
+// jobs. Jobs are fetched a page at a time (every job if the page is nil),
+// along with the cursor of the next page, empty on the last one.
 type MaintenanceRepository interface {
-	GetByID(ID string) (*models.Maintenance, error)
-	Fetch() ([]*models.Maintenance, error)
-	FetchForRide(rideID string) ([]*models.Maintenance, error)
-	Store(*models.Maintenance) error
-	Update(*models.Maintenance) error
-	Delete(ID string) error
-	AvailableMaintenanceTypes() ([]string, error)
+	GetByID(ctx context.Context, ID string) (*models.Maintenance, error)
+	Fetch(ctx context.Context, filter *models.MaintenanceFilter, page *models.PageRequest) ([]*models.Maintenance, string, error)
+	Store(ctx context.Context, maintenance *models.Maintenance) error
+	Update(ctx context.Context, maintenance *models.Maintenance) error
+	Delete(ctx context.Context, ID string) error
+	AvailableMaintenanceTypes(ctx context.Context) ([]string, error)
 }
@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// PasswordResetRepository defines the interface for working with password
+// resets.
+type PasswordResetRepository interface {
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordReset, error)
+
+	Store(ctx context.Context, reset *models.PasswordReset) error
+	Redeem(ctx context.Context, reset *models.PasswordReset, usedOn time.Time, passwordSalt, passwordHash string) error
+}
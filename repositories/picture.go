@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 )
 
@@ -8,11 +10,11 @@ import (
 // picture collections. Pictures can be individually fetched and deleted,
 // however, the rest of operations are usually done on top of collections.
 type PictureRepository interface {
-	GetByID(ID string) (*models.Picture, error)
-	Delete(ID string) error
+	GetByID(ctx context.Context, ID string) (*models.Picture, error)
+	Delete(ctx context.Context, ID string) error
 
-	FetchByCollectionID(collectionID string) ([]*models.Picture, error)
-	Store(collectionID string, picture *models.Picture) error
-	UpdateCollectionOrdering(collectionID string, fromIndex, toIndex int) error
-	DeleteCollection(collectionID string) error
+	FetchByCollectionID(ctx context.Context, collectionID string) ([]*models.Picture, error)
+	Store(ctx context.Context, collectionID string, picture *models.Picture) error
+	UpdateCollectionOrdering(ctx context.Context, collectionID string, fromIndex, toIndex int) error
+	DeleteCollection(ctx context.Context, collectionID string) error
 }
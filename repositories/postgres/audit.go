@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+var selectAuditEntries = psql.
+	Select("audit_log.*").
+	From("audit_log").
+	OrderBy("audit_log.recorded_on DESC")
+
+// AuditRepository implements the AuditRepository interface for postgres.
+type AuditRepository struct {
+	db *sqlx.DB
+}
+
+// NewAuditRepository creates a new AuditRepository instance using the given
+// database instance.
+func NewAuditRepository(db *sqlx.DB) *AuditRepository {
+	return &AuditRepository{db}
+}
+
+// Fetch fetches the audit entries matching the given filter.
+func (ar *AuditRepository) Fetch(ctx context.Context, filter *models.AuditFilter) ([]*models.AuditEntry, error) {
+	db := ar.db
+	udb := db.Unsafe()
+
+	builder := selectAuditEntries
+	if len(filter.ActorID) > 0 {
+		builder = builder.Where(sq.Eq{"audit_log.actor_id": filter.ActorID})
+	}
+
+	if len(filter.EntityType) > 0 {
+		builder = builder.Where(sq.Eq{"audit_log.entity_type": filter.EntityType})
+	}
+
+	if len(filter.EntityID) > 0 {
+		builder = builder.Where(sq.Eq{"audit_log.entity_id": filter.EntityID})
+	}
+
+	if filter.Since.Valid {
+		builder = builder.Where(sq.GtOrEq{"audit_log.recorded_on": filter.Since.Time})
+	}
+
+	if filter.Until.Valid {
+		builder = builder.Where(sq.Lt{"audit_log.recorded_on": filter.Until.Time})
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("selectAuditEntries: %w", mapError("audit entry", err))
+	}
+
+	entries := []*models.AuditEntry{}
+	err = udb.SelectContext(ctx, &entries, query, args...)
+	if err != nil {
+		return nil, mapError("audit entry", err)
+	}
+
+	return entries, nil
+}
+
+// Store creates a new audit entry.
+func (ar *AuditRepository) Store(ctx context.Context, entry *models.AuditEntry) error {
+	db := ar.db
+
+	insertAuditEntry, _, _ := psql.
+		Insert("audit_log").
+		Columns("ID", "actor_ID", "action", "entity_type", "entity_ID", "before", "after", "recorded_on").
+		Values("?", "?", "?", "?", "?", "?", "?", "?").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, insertAuditEntry, entry.ID, entry.ActorID, entry.Action, entry.EntityType, entry.EntityID, entry.Before, entry.After, entry.RecordedOn)
+	if err != nil {
+		return fmt.Errorf("insertAuditEntry: %w", mapError("audit entry", err))
+	}
+
+	return nil
+}
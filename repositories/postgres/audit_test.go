@@ -0,0 +1,108 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestAuditEntries(db *sqlx.DB) []string {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE audit_log CASCADE")
+
+	now := time.Now().UTC()
+	entry0 := generator.MustInsertAuditEntry(tx, "actor0", models.AuditCreate, "ride", "ride0", now.Add(-time.Hour*48))
+	entry1 := generator.MustInsertAuditEntry(tx, "actor0", models.AuditUpdate, "ride", "ride0", now.Add(-time.Hour))
+	entry2 := generator.MustInsertAuditEntry(tx, "actor1", models.AuditDelete, "ticket", "ticket0", now)
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+
+	return []string{entry0, entry1, entry2}
+}
+
+// Tests
+// --------------------------------
+
+func TestAuditFetchSucceeds(t *testing.T) {
+	auditRepository, db, teardown := testutil.MakeAuditRepositoryFixture()
+	defer teardown()
+
+	entryIDs := setupTestAuditEntries(db)
+	since := models.FromSQLNullTime(sql.NullTime{Time: time.Now().UTC().Add(-time.Hour * 24), Valid: true})
+
+	tests := []struct {
+		filter   models.AuditFilter
+		expected []string
+	}{
+		{models.AuditFilter{}, []string{entryIDs[2], entryIDs[1], entryIDs[0]}},
+		{models.AuditFilter{ActorID: "actor0"}, []string{entryIDs[1], entryIDs[0]}},
+		{models.AuditFilter{EntityType: "ticket"}, []string{entryIDs[2]}},
+		{models.AuditFilter{EntityType: "ride", EntityID: "ride0", Since: since}, []string{entryIDs[1]}},
+		{models.AuditFilter{Until: since}, []string{entryIDs[0]}},
+	}
+
+	for _, tt := range tests {
+		entries, err := auditRepository.Fetch(context.Background(), &tt.filter)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		actual := []string{}
+		for _, entry := range entries {
+			actual = append(actual, entry.ID)
+		}
+
+		assert.Equal(t, tt.expected, actual)
+	}
+}
+
+func TestAuditStoreSucceeds(t *testing.T) {
+	auditRepository, db, teardown := testutil.MakeAuditRepositoryFixture()
+	defer teardown()
+
+	setupTestAuditEntries(db)
+
+	after, err := models.NewNullJSON(map[string]string{"id": "ride1"})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	expectedEntry := &models.AuditEntry{
+		ID:         "some-entry-id",
+		ActorID:    models.NewNullString("actor2"),
+		Action:     models.AuditCreate,
+		EntityType: "ride",
+		EntityID:   "ride1",
+		After:      after,
+		RecordedOn: time.Now().UTC().Truncate(time.Second),
+	}
+
+	err = auditRepository.Store(context.Background(), expectedEntry)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	entries, err := auditRepository.Fetch(context.Background(), &models.AuditFilter{ActorID: "actor2"})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, expectedEntry, entries[0])
+	}
+}
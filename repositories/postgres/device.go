@@ -0,0 +1,233 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+var selectDevices = psql.Select("devices.*").From("devices").OrderBy("devices.created_on DESC")
+
+var selectDeviceScopes = psql.
+	Select("device_scopes.*").
+	From("device_scopes").
+	OrderBy("device_scopes.operation ASC", "device_scopes.ride_id ASC")
+
+// DeviceRepository implements the DeviceRepository interface for postgres.
+type DeviceRepository struct {
+	db *sqlx.DB
+}
+
+// NewDeviceRepository creates a new DeviceRepository instance using the given
+// database instance.
+func NewDeviceRepository(db *sqlx.DB) *DeviceRepository {
+	return &DeviceRepository{db}
+}
+
+// GetByID fetches a device, including its scopes, using the given ID.
+func (dr *DeviceRepository) GetByID(ctx context.Context, ID string) (*models.Device, error) {
+	db := dr.db
+	udb := db.Unsafe()
+
+	query, _ := selectDevices.Where("devices.ID = ?").MustSql()
+
+	device := models.Device{}
+	err := udb.GetContext(ctx, &device, query, ID)
+	if err != nil {
+		return nil, mapError("device", err)
+	}
+
+	query, _ = selectDeviceScopes.Where("device_scopes.device_ID = ?").MustSql()
+
+	device.Scopes = []*models.DeviceScope{}
+	err = udb.SelectContext(ctx, &device.Scopes, query, ID)
+	if err != nil {
+		return nil, fmt.Errorf("selectDeviceScopes: %w", mapError("device", err))
+	}
+
+	return &device, nil
+}
+
+// Fetch fetches all devices, including their scopes.
+func (dr *DeviceRepository) Fetch(ctx context.Context) ([]*models.Device, error) {
+	db := dr.db
+	udb := db.Unsafe()
+
+	query, _ := selectDevices.MustSql()
+
+	devices := []*models.Device{}
+	err := udb.SelectContext(ctx, &devices, query)
+	if err != nil {
+		return nil, mapError("device", err)
+	}
+
+	type DeviceScope struct {
+		DeviceID string `db:"device_id"`
+		models.DeviceScope
+	}
+
+	query, _ = selectDeviceScopes.MustSql()
+
+	scopes := []*DeviceScope{}
+	err = udb.SelectContext(ctx, &scopes, query)
+	if err != nil {
+		return nil, fmt.Errorf("selectDeviceScopes: %w", mapError("device", err))
+	}
+
+	devicesMap := make(map[string]*models.Device)
+	for _, device := range devices {
+		device.Scopes = []*models.DeviceScope{}
+		devicesMap[device.ID] = device
+	}
+
+	for _, scope := range scopes {
+		if device, ok := devicesMap[scope.DeviceID]; ok {
+			device.Scopes = append(device.Scopes, &scope.DeviceScope)
+		}
+	}
+
+	return devices, nil
+}
+
+// Store creates a new device, including its scopes.
+func (dr *DeviceRepository) Store(ctx context.Context, device *models.Device) error {
+	db := dr.db
+
+	insertDevice, _, _ := psql.
+		Insert("devices").
+		Columns("ID", "name", "secret_hash", "created_on", "rotated_on", "revoked_on").
+		Values("?", "?", "?", "?", "?", "?").
+		ToSql()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return mapError("device", err)
+	}
+	defer tx.Rollback()
+
+	{
+		_, err = tx.ExecContext(ctx, insertDevice, device.ID, device.Name, device.SecretHash, device.CreatedOn, device.RotatedOn, device.RevokedOn)
+		if err != nil {
+			return fmt.Errorf("insertDevice: %w", mapError("device", err))
+		}
+
+		err = insertDeviceScopes(ctx, tx, device)
+		if err != nil {
+			return mapError("device", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return mapError("device", err)
+	}
+
+	return nil
+}
+
+// Update updates the name and scopes of an existing device.
+func (dr *DeviceRepository) Update(ctx context.Context, device *models.Device) error {
+	db := dr.db
+
+	updateDevice, _, _ := psql.
+		Update("devices").
+		Set("name", "?").
+		Where("ID = ?").
+		ToSql()
+
+	deleteScopes, _, _ := psql.
+		Delete("device_scopes").
+		Where("device_ID = ?").
+		ToSql()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return mapError("device", err)
+	}
+	defer tx.Rollback()
+
+	{
+		_, err = tx.ExecContext(ctx, updateDevice, device.Name, device.ID)
+		if err != nil {
+			return fmt.Errorf("updateDevice: %w", mapError("device", err))
+		}
+
+		_, err = tx.ExecContext(ctx, deleteScopes, device.ID)
+		if err != nil {
+			return fmt.Errorf("deleteScopes: %w", mapError("device", err))
+		}
+
+		err = insertDeviceScopes(ctx, tx, device)
+		if err != nil {
+			return mapError("device", err)
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return mapError("device", err)
+	}
+
+	return nil
+}
+
+// UpdateSecret replaces the secret hash of the given device.
+func (dr *DeviceRepository) UpdateSecret(ctx context.Context, ID, secretHash string, rotatedOn time.Time) error {
+	db := dr.db
+
+	updateSecret, _, _ := psql.
+		Update("devices").
+		Set("secret_hash", "?").
+		Set("rotated_on", "?").
+		Where("ID = ?").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, updateSecret, secretHash, rotatedOn, ID)
+	if err != nil {
+		return fmt.Errorf("updateSecret: %w", mapError("device", err))
+	}
+
+	return nil
+}
+
+// Revoke revokes the device with the given ID. Devices that were already
+// revoked keep their original revocation time.
+func (dr *DeviceRepository) Revoke(ctx context.Context, ID string, revokedOn time.Time) error {
+	db := dr.db
+
+	revokeDevice, _, _ := psql.
+		Update("devices").
+		Set("revoked_on", "?").
+		Where("ID = ?").
+		Where("revoked_on IS NULL").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, revokeDevice, revokedOn, ID)
+	if err != nil {
+		return fmt.Errorf("revokeDevice: %w", mapError("device", err))
+	}
+
+	return nil
+}
+
+func insertDeviceScopes(ctx context.Context, tx *sql.Tx, device *models.Device) error {
+	insertScope, _, _ := psql.
+		Insert("device_scopes").
+		Columns("device_ID", "operation", "ride_ID").
+		Values("?", "?", "?").
+		ToSql()
+
+	for _, scope := range device.Scopes {
+		_, err := tx.ExecContext(ctx, insertScope, device.ID, scope.Operation, scope.RideID)
+		if err != nil {
+			return fmt.Errorf("insertScope: %w", mapError("device", err))
+		}
+	}
+
+	return nil
+}
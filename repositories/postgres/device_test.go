@@ -0,0 +1,171 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestDevices(db *sqlx.DB) ([]string, []string) {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE devices CASCADE")
+	tx.MustExec("TRUNCATE TABLE rides CASCADE")
+
+	ride0 := generator.MustInsertRide(tx)
+	ride1 := generator.MustInsertRide(tx)
+
+	// scopes per device = device index
+	device0 := generator.MustInsertDevice(tx, "device0", "hash0")
+	device1 := generator.MustInsertDevice(tx, "device1", "hash1")
+	device2 := generator.MustInsertDevice(tx, "device2", "hash2")
+
+	generator.MustInsertDeviceScope(tx, device1, models.DeviceScanTickets, "")
+	generator.MustInsertDeviceScope(tx, device2, models.DeviceScanTickets, ride0)
+	generator.MustInsertDeviceScope(tx, device2, models.DeviceScanTickets, ride1)
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+
+	return []string{ride0, ride1}, []string{device0, device1, device2}
+}
+
+// Tests
+// --------------------------------
+
+func TestDeviceGetByIDSucceeds(t *testing.T) {
+	deviceRepository, db, teardown := testutil.MakeDeviceRepositoryFixture()
+	defer teardown()
+
+	_, deviceIDs := setupTestDevices(db)
+
+	for idx, deviceID := range deviceIDs {
+		device, err := deviceRepository.GetByID(context.Background(), deviceID)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, deviceID, device.ID)
+		assert.NotEmpty(t, device.SecretHash)
+		assert.True(t, device.IsActive())
+		assert.Len(t, device.Scopes, idx)
+	}
+}
+
+func TestDeviceFetchSucceeds(t *testing.T) {
+	deviceRepository, db, teardown := testutil.MakeDeviceRepositoryFixture()
+	defer teardown()
+
+	_, deviceIDs := setupTestDevices(db)
+
+	devices, err := deviceRepository.Fetch(context.Background())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Len(t, devices, len(deviceIDs))
+
+	scopes := 0
+	for _, device := range devices {
+		scopes += len(device.Scopes)
+	}
+
+	assert.Equal(t, 3, scopes)
+}
+
+func TestDeviceStoreSucceeds(t *testing.T) {
+	deviceRepository, db, teardown := testutil.MakeDeviceRepositoryFixture()
+	defer teardown()
+
+	rideIDs, _ := setupTestDevices(db)
+
+	expectedDevice := &models.Device{
+		ID:         "some-device-id",
+		Name:       "some-device",
+		SecretHash: "some-secret-hash",
+		CreatedOn:  time.Now().UTC().Truncate(time.Second),
+		Scopes: []*models.DeviceScope{
+			{Operation: models.DeviceScanTickets, RideID: models.NewNullString(rideIDs[0])},
+		},
+	}
+
+	err := deviceRepository.Store(context.Background(), expectedDevice)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	device, err := deviceRepository.GetByID(context.Background(), expectedDevice.ID)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, expectedDevice, device)
+}
+
+func TestDeviceUpdateSucceeds(t *testing.T) {
+	deviceRepository, db, teardown := testutil.MakeDeviceRepositoryFixture()
+	defer teardown()
+
+	_, deviceIDs := setupTestDevices(db)
+
+	expectedDevice, err := deviceRepository.GetByID(context.Background(), deviceIDs[2])
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	expectedDevice.Name = "new-name"
+	expectedDevice.Scopes = []*models.DeviceScope{
+		{Operation: models.DeviceScanTickets},
+	}
+
+	err = deviceRepository.Update(context.Background(), expectedDevice)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	device, err := deviceRepository.GetByID(context.Background(), expectedDevice.ID)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, expectedDevice, device)
+}
+
+func TestDeviceUpdateSecretAndRevokeSucceeds(t *testing.T) {
+	deviceRepository, db, teardown := testutil.MakeDeviceRepositoryFixture()
+	defer teardown()
+
+	_, deviceIDs := setupTestDevices(db)
+	deviceID := deviceIDs[0]
+
+	err := deviceRepository.UpdateSecret(context.Background(), deviceID, "new-hash", time.Now().UTC())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	err = deviceRepository.Revoke(context.Background(), deviceID, time.Now().UTC())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	device, err := deviceRepository.GetByID(context.Background(), deviceID)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "new-hash", device.SecretHash)
+	assert.True(t, device.RotatedOn.Valid)
+	assert.False(t, device.IsActive())
+}
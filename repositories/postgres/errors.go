@@ -0,0 +1,59 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// mapError maps database errors onto usecase errors (see usecases.ErrorKind),
+// using the given entity name in messages. Missing rows are not found errors,
+// constraint violations are conflict or validation errors, and failures to
+// reach the database are unavailable errors. Other errors, and errors that
+// already have a kind, are returned as is.
+func mapError(entity string, err error) error {
+	if err == nil || usecases.KindOf(err) != "" {
+		return err
+	}
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return &usecases.Error{Kind: usecases.KindNotFound, Message: fmt.Sprintf("%s not found", entity), Err: err}
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) || errors.Is(err, driver.ErrBadConn) {
+		return &usecases.Error{Kind: usecases.KindUnavailable, Message: "database did not respond in time", Err: err}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return &usecases.Error{Kind: usecases.KindUnavailable, Message: "database is unavailable", Err: err}
+	}
+
+	var pgErr pgx.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch {
+	case pgErr.Code == "23505": // unique_violation
+		return &usecases.Error{Kind: usecases.KindConflict, Message: fmt.Sprintf("%s already exists", entity), Err: err}
+	case pgErr.Code == "23503" && strings.HasPrefix(pgErr.Message, "insert or update"): // foreign_key_violation
+		return &usecases.Error{Kind: usecases.KindValidation, Message: fmt.Sprintf("%s references something that does not exist", entity), Err: err}
+	case pgErr.Code == "23503":
+		return &usecases.Error{Kind: usecases.KindConflict, Message: fmt.Sprintf("%s is still in use", entity), Err: err}
+	case pgErr.Code == "23502", pgErr.Code == "23514", strings.HasPrefix(pgErr.Code, "22"): // not_null_violation, check_violation, data_exception
+		return &usecases.Error{Kind: usecases.KindValidation, Message: fmt.Sprintf("%s is not valid", entity), Err: err}
+	case strings.HasPrefix(pgErr.Code, "08"), pgErr.Code == "57014", strings.HasPrefix(pgErr.Code, "53"): // connection_exception, query_canceled, insufficient_resources
+		return &usecases.Error{Kind: usecases.KindUnavailable, Message: "database is unavailable", Err: err}
+	}
+
+	return err
+}
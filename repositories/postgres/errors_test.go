@@ -0,0 +1,45 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+func TestMapErrorKinds(t *testing.T) {
+	tests := []struct {
+		err  error
+		kind usecases.ErrorKind
+	}{
+		{sql.ErrNoRows, usecases.KindNotFound},
+		{fmt.Errorf("query: %w", sql.ErrNoRows), usecases.KindNotFound},
+		{context.DeadlineExceeded, usecases.KindUnavailable},
+		{pgx.PgError{Code: "23505"}, usecases.KindConflict},
+		{pgx.PgError{Code: "23503", Message: "insert or update on table \"tickets\" violates foreign key constraint"}, usecases.KindValidation},
+		{pgx.PgError{Code: "23503", Message: "update or delete on table \"rides\" violates foreign key constraint"}, usecases.KindConflict},
+		{pgx.PgError{Code: "23502"}, usecases.KindValidation},
+		{pgx.PgError{Code: "22P02"}, usecases.KindValidation},
+		{pgx.PgError{Code: "57014"}, usecases.KindUnavailable},
+		{pgx.PgError{Code: "42P01"}, ""},
+		{errors.New("failed"), ""},
+	}
+
+	for _, test := range tests {
+		err := mapError("ride", test.err)
+		assert.Equal(t, test.kind, usecases.KindOf(err), "%v", test.err)
+		assert.True(t, errors.Is(err, test.err) || err == test.err)
+	}
+}
+
+func TestMapErrorKeepsKind(t *testing.T) {
+	err := usecases.Errorf(usecases.KindForbidden, "not allowed")
+	assert.Equal(t, err, mapError("ride", err))
+	assert.Nil(t, mapError("ride", nil))
+}
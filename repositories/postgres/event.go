@@ -1,7 +1,7 @@
 package postgres
 
 import (
-	"time"
+	"context"
 	"fmt"
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
@@ -10,12 +10,30 @@ import (
 )
 
 var selectEvents = psql.
-	Select("events.*", "event_types.*", "users.email", "user_details.first_name", "user_details.last_name").
+	Select(
+		"events.*",
+		"event_types.event_type",
+		`COALESCE(events.employee_id, '') AS "employee.id"`,
+		`user_details.first_name AS "employee.first_name"`,
+		`user_details.last_name AS "employee.last_name"`,
+	).
 	From("events").
 	Join("event_types ON event_types.ID = events.event_type_id").
-	LeftJoin("users ON users.id = events.employee_id").
-	LeftJoin("user_details ON user_details.user_id = events.employee_id").
-	OrderBy("events.posted_on DESC")
+	LeftJoin("user_details ON user_details.user_id = events.employee_id")
+
+var eventSorting = &sorting{
+	fields: map[string]sortField{
+		"postedOn": {"events.posted_on", "timestamp", func(row interface{}) string {
+			return timeValue(row.(*models.Event).PostedOn)
+		}},
+		"title": {"events.title", "text", func(row interface{}) string {
+			return row.(*models.Event).Title
+		}},
+	},
+	defaultSort: "-postedOn",
+	id:          "events.id",
+	idOf:        func(row interface{}) string { return row.(*models.Event).ID },
+}
 
 // EventRepository implements the EventRepository interface for postgres.
 type EventRepository struct {
@@ -28,55 +46,68 @@ func NewEventRepository(db *sqlx.DB) *EventRepository {
 }
 
 // GetByID fetches an event using the given ID.
-func (er *EventRepository) GetByID(ID string) (*models.Event, error) {
+func (er *EventRepository) GetByID(ctx context.Context, ID string) (*models.Event, error) {
 	db := er.db
 	udb := db.Unsafe()
 
 	query, _ := selectEvents.Where(sq.Eq{"events.id": ID}).MustSql()
 
 	event := models.Event{}
-	err := udb.Get(&event, query, ID)
+	err := udb.GetContext(ctx, &event, query, ID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("event", err)
 	}
 
+	clearMissingEmployees(&event)
 	return &event, nil
 }
 
-// Fetch fetches all events.
-func (er *EventRepository) Fetch() ([]*models.Event, error) {
+// Fetch fetches a page of the events matching the given filter.
+func (er *EventRepository) Fetch(ctx context.Context, filter *models.EventFilter, page *models.PageRequest) ([]*models.Event, string, error) {
 	db := er.db
 	udb := db.Unsafe()
 
-	query, _ := selectEvents.MustSql()
+	builder := selectEvents
+	if len(filter.EventType) > 0 {
+		builder = builder.Where(sq.Eq{"event_types.event_type": filter.EventType})
+	}
 
-	events := []*models.Event{}
-	err := udb.Select(&events, query)
-	if err != nil {
-		return nil, err
+	if filter.Since.Valid {
+		builder = builder.Where(sq.GtOrEq{"events.posted_on": filter.Since.Time})
 	}
 
-	return events, nil
-}
+	if filter.Until.Valid {
+		builder = builder.Where(sq.Lt{"events.posted_on": filter.Until.Time})
+	}
 
-// FetchSince fetches all events since given time.
-func (er *EventRepository) FetchSince(since time.Time) ([]*models.Event, error) {
-	db := er.db
-	udb := db.Unsafe()
+	builder, err := eventSorting.paginate(builder, page)
+	if err != nil {
+		return nil, "", err
+	}
 
-	query, _ := selectEvents.Where(sq.GtOrEq{"events.posted_on": "$1"}).MustSql()
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("selectEvents: %w", mapError("event", err))
+	}
 
 	events := []*models.Event{}
-	err := udb.Select(&events, query, since)
+	err = udb.SelectContext(ctx, &events, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", mapError("event", err)
+	}
+
+	count, more := eventSorting.next(page, len(events))
+	events = events[:count]
+	clearMissingEmployees(events...)
+	if !more {
+		return events, "", nil
 	}
 
-	return events, nil
+	return events, eventSorting.cursor(page, events[count-1]), nil
 }
 
 // Store creates a new event.
-func (er *EventRepository) Store(event *models.Event) error {
+func (er *EventRepository) Store(ctx context.Context, event *models.Event) error {
 	db := er.db
 
 	selectEventTypeID, _ := psql.
@@ -86,9 +117,9 @@ func (er *EventRepository) Store(event *models.Event) error {
 		MustSql()
 
 	var eventTypeID string
-	err := er.db.Get(&eventTypeID, selectEventTypeID, event.EventType)
+	err := er.db.GetContext(ctx, &eventTypeID, selectEventTypeID, event.EventType)
 	if err != nil {
-		return fmt.Errorf("selectEventTypeID: %s", err)
+		return fmt.Errorf("selectEventTypeID: %w", mapError("event", err))
 	}
 	if len(eventTypeID) <= 0 {
 		return fmt.Errorf("selectEventTypeID: could not find valid ID for '%s'", event.EventType)
@@ -100,16 +131,16 @@ func (er *EventRepository) Store(event *models.Event) error {
 		Values("?", "?", "?", "?", "?", "?").
 		ToSql()
 
-	_, err = db.Exec(query, event.ID, event.EmployeeID, eventTypeID, event.Title, event.Description, event.PostedOn)
+	_, err = db.ExecContext(ctx, query, event.ID, event.EmployeeID, eventTypeID, event.Title, event.Description, event.PostedOn)
 	if err != nil {
-		return err
+		return mapError("event", err)
 	}
 
 	return nil
 }
 
 // Update updates an existing event.
-func (er *EventRepository) Update(event *models.Event) error {
+func (er *EventRepository) Update(ctx context.Context, event *models.Event) error {
 	db := er.db
 
 	selectEventTypeID, _ := psql.
@@ -119,9 +150,9 @@ func (er *EventRepository) Update(event *models.Event) error {
 		MustSql()
 
 	var eventTypeID string
-	err := er.db.Get(&eventTypeID, selectEventTypeID, event.EventType)
+	err := er.db.GetContext(ctx, &eventTypeID, selectEventTypeID, event.EventType)
 	if err != nil {
-		return fmt.Errorf("selectEventTypeID: %s", err)
+		return fmt.Errorf("selectEventTypeID: %w", mapError("event", err))
 	}
 	if len(eventTypeID) <= 0 {
 		return fmt.Errorf("selectEventTypeID: could not find valid ID for '%s'", event.EventType)
@@ -137,39 +168,49 @@ func (er *EventRepository) Update(event *models.Event) error {
 		Where(sq.Eq{"id": "$6"}).
 		ToSql()
 
-	_, err = db.Exec(query, event.EmployeeID, eventTypeID, event.Title, event.Description, event.PostedOn, event.ID)
+	_, err = db.ExecContext(ctx, query, event.EmployeeID, eventTypeID, event.Title, event.Description, event.PostedOn, event.ID)
 	if err != nil {
-		return err
+		return mapError("event", err)
 	}
 
 	return nil
 }
 
 // Delete deletes an existing event.
-func (er *EventRepository) Delete(eventID string) error {
+func (er *EventRepository) Delete(ctx context.Context, eventID string) error {
 	db := er.db
 
 	query, _, _ := psql.Delete("events").Where(sq.Eq{"id": "$1"}).ToSql()
 
-	_, err := db.Exec(query, eventID)
+	_, err := db.ExecContext(ctx, query, eventID)
 	if err != nil {
-		return err
+		return mapError("event", err)
 	}
 
 	return nil
 }
 
 // AvailableEventTypes returns the available event types.
-func (er *EventRepository) AvailableEventTypes() ([]*models.EventType, error) {
+func (er *EventRepository) AvailableEventTypes(ctx context.Context) ([]*models.EventType, error) {
 	db := er.db
 
 	query, _ := psql.Select("event_types.*").From("event_types").OrderBy("event_type ASC").MustSql()
 
 	eventTypes := []*models.EventType{}
-	err := db.Select(&eventTypes, query)
+	err := db.SelectContext(ctx, &eventTypes, query)
 	if err != nil {
-		return nil, err
+		return nil, mapError("event", err)
 	}
 
 	return eventTypes, nil
 }
+
+// clearMissingEmployees unsets the employee for events that were not posted
+// by an employee (e.g. posted by the system).
+func clearMissingEmployees(events ...*models.Event) {
+	for _, event := range events {
+		if !event.EmployeeID.Valid {
+			event.Employee = nil
+		}
+	}
+}
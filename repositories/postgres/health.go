@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// HealthRepository implements the HealthRepository interface for postgres.
+type HealthRepository struct {
+	db *sqlx.DB
+}
+
+// NewHealthRepository creates a new HealthRepository instance using the given
+// database instance.
+func NewHealthRepository(db *sqlx.DB) *HealthRepository {
+	return &HealthRepository{db}
+}
+
+// Ping checks that the database is reachable.
+func (hr *HealthRepository) Ping(ctx context.Context) error {
+	err := hr.db.PingContext(ctx)
+	if err != nil {
+		return fmt.Errorf("ping: %s", err)
+	}
+	return nil
+}
+
+// SchemaExists returns true if the given schema exists.
+func (hr *HealthRepository) SchemaExists(ctx context.Context, schema string) (bool, error) {
+	query, _ := psql.
+		Select("1").
+		From("information_schema.schemata").
+		Where("schema_name = ?").
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		MustSql()
+
+	return hr.exists(ctx, query, schema)
+}
+
+// EventTypeExists returns true if the given event type exists.
+func (hr *HealthRepository) EventTypeExists(ctx context.Context, eventType string) (bool, error) {
+	query, _ := psql.
+		Select("1").
+		From("event_types").
+		Where("event_type = ?").
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		MustSql()
+
+	return hr.exists(ctx, query, eventType)
+}
+
+// RoleExists returns true if the given employee role exists.
+func (hr *HealthRepository) RoleExists(ctx context.Context, role string) (bool, error) {
+	query, _ := psql.
+		Select("1").
+		From("roles").
+		Where("role = ?").
+		Prefix("SELECT EXISTS (").
+		Suffix(")").
+		MustSql()
+
+	return hr.exists(ctx, query, role)
+}
+
+func (hr *HealthRepository) exists(ctx context.Context, query string, args ...interface{}) (bool, error) {
+	var exists bool
+	err := hr.db.GetContext(ctx, &exists, query, args...)
+	if err != nil {
+		return false, err
+	}
+	return exists, nil
+}
@@ -0,0 +1,79 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestHealth(db *sqlx.DB) {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE event_types CASCADE")
+	tx.MustExec("TRUNCATE TABLE roles CASCADE")
+
+	generator.MustInsertEventType(tx, models.EventTypeSystem)
+	generator.MustInsertRole(tx, models.RoleSupervisor)
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Tests
+// --------------------------------
+
+func TestHealthPingSucceeds(t *testing.T) {
+	healthRepository, _, teardown := testutil.MakeHealthRepositoryFixture()
+	defer teardown()
+
+	err := healthRepository.Ping(context.Background())
+	assert.Nil(t, err)
+}
+
+func TestHealthSchemaExists(t *testing.T) {
+	healthRepository, _, teardown := testutil.MakeHealthRepositoryFixture()
+	defer teardown()
+
+	exists, err := healthRepository.SchemaExists(context.Background(), "theme_park")
+	if assert.Nil(t, err) {
+		assert.True(t, exists)
+	}
+
+	exists, err = healthRepository.SchemaExists(context.Background(), "does_not_exist")
+	if assert.Nil(t, err) {
+		assert.False(t, exists)
+	}
+}
+
+func TestHealthReferenceRowsExist(t *testing.T) {
+	healthRepository, db, teardown := testutil.MakeHealthRepositoryFixture()
+	defer teardown()
+
+	setupTestHealth(db)
+
+	exists, err := healthRepository.EventTypeExists(context.Background(), models.EventTypeSystem)
+	if assert.Nil(t, err) {
+		assert.True(t, exists)
+	}
+
+	exists, err = healthRepository.RoleExists(context.Background(), models.RoleSupervisor)
+	if assert.Nil(t, err) {
+		assert.True(t, exists)
+	}
+
+	exists, err = healthRepository.RoleExists(context.Background(), "Worker")
+	if assert.Nil(t, err) {
+		assert.False(t, exists)
+	}
+}
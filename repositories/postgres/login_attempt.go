@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+var selectLoginAttempts = psql.
+	Select("login_attempts.*").
+	From("login_attempts").
+	OrderBy("login_attempts.attempted_on DESC")
+
+// LoginAttemptRepository implements the LoginAttemptRepository interface for
+// postgres.
+type LoginAttemptRepository struct {
+	db *sqlx.DB
+}
+
+// NewLoginAttemptRepository creates a new LoginAttemptRepository instance
+// using the given database instance.
+func NewLoginAttemptRepository(db *sqlx.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db}
+}
+
+// FetchSince fetches all log in attempts since the given time.
+func (lr *LoginAttemptRepository) FetchSince(ctx context.Context, since time.Time) ([]*models.LoginAttempt, error) {
+	db := lr.db
+	udb := db.Unsafe()
+
+	query, _ := selectLoginAttempts.Where("login_attempts.attempted_on >= ?").MustSql()
+
+	attempts := []*models.LoginAttempt{}
+	err := udb.SelectContext(ctx, &attempts, query, since)
+	if err != nil {
+		return nil, mapError("log in attempt", err)
+	}
+
+	return attempts, nil
+}
+
+// FetchForEmailSince fetches all log in attempts for the given email since
+// the given time.
+func (lr *LoginAttemptRepository) FetchForEmailSince(ctx context.Context, email string, since time.Time) ([]*models.LoginAttempt, error) {
+	db := lr.db
+	udb := db.Unsafe()
+
+	query, _ := selectLoginAttempts.
+		Where("login_attempts.email = ?").
+		Where("login_attempts.attempted_on >= ?").
+		MustSql()
+
+	attempts := []*models.LoginAttempt{}
+	err := udb.SelectContext(ctx, &attempts, query, email, since)
+	if err != nil {
+		return nil, mapError("log in attempt", err)
+	}
+
+	return attempts, nil
+}
+
+// Store creates a new log in attempt.
+func (lr *LoginAttemptRepository) Store(ctx context.Context, attempt *models.LoginAttempt) error {
+	db := lr.db
+
+	insertLoginAttempt, _, _ := psql.
+		Insert("login_attempts").
+		Columns("ID", "email", "user_ID", "user_agent", "address", "success", "reason", "attempted_on").
+		Values("?", "?", "?", "?", "?", "?", "?", "?").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, insertLoginAttempt, attempt.ID, attempt.Email, attempt.UserID, attempt.UserAgent, attempt.Address, attempt.Success, attempt.Reason, attempt.AttemptedOn)
+	if err != nil {
+		return fmt.Errorf("insertLoginAttempt: %w", mapError("log in attempt", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,110 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestLoginAttempts(db *sqlx.DB) []string {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE login_attempts CASCADE")
+
+	// attempts per email = email index + 1, the first one always old
+	now := time.Now().UTC()
+	attempt0 := generator.MustInsertLoginAttempt(tx, "customer0@email.com", false, now.Add(-time.Hour*48))
+	attempt1 := generator.MustInsertLoginAttempt(tx, "customer1@email.com", false, now.Add(-time.Hour*48))
+	attempt2 := generator.MustInsertLoginAttempt(tx, "customer1@email.com", true, now)
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+
+	return []string{attempt0, attempt1, attempt2}
+}
+
+// Tests
+// --------------------------------
+
+func TestLoginAttemptFetchSinceSucceeds(t *testing.T) {
+	loginAttemptRepository, db, teardown := testutil.MakeLoginAttemptRepositoryFixture()
+	defer teardown()
+
+	attemptIDs := setupTestLoginAttempts(db)
+
+	attempts, err := loginAttemptRepository.FetchSince(context.Background(), time.Now().UTC().Add(-time.Hour*24))
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	if assert.Len(t, attempts, 1) {
+		assert.Equal(t, attemptIDs[2], attempts[0].ID)
+	}
+
+	attempts, err = loginAttemptRepository.FetchSince(context.Background(), time.Time{})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Len(t, attempts, len(attemptIDs))
+}
+
+func TestLoginAttemptFetchForEmailSinceSucceeds(t *testing.T) {
+	loginAttemptRepository, db, teardown := testutil.MakeLoginAttemptRepositoryFixture()
+	defer teardown()
+
+	setupTestLoginAttempts(db)
+
+	emails := []string{"customer0@email.com", "customer1@email.com"}
+	for idx, email := range emails {
+		attempts, err := loginAttemptRepository.FetchForEmailSince(context.Background(), email, time.Time{})
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Len(t, attempts, idx+1)
+	}
+}
+
+func TestLoginAttemptStoreSucceeds(t *testing.T) {
+	loginAttemptRepository, db, teardown := testutil.MakeLoginAttemptRepositoryFixture()
+	defer teardown()
+
+	setupTestLoginAttempts(db)
+
+	expectedAttempt := &models.LoginAttempt{
+		ID:          "some-attempt-id",
+		Email:       "customer2@email.com",
+		UserAgent:   models.NewNullString("some-user-agent"),
+		Address:     models.NewNullString("127.0.0.1"),
+		Success:     false,
+		Reason:      models.NewNullString("unknown email"),
+		AttemptedOn: time.Now().UTC().Truncate(time.Second),
+	}
+
+	err := loginAttemptRepository.Store(context.Background(), expectedAttempt)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	attempts, err := loginAttemptRepository.FetchForEmailSince(context.Background(), expectedAttempt.Email, time.Time{})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	if assert.Len(t, attempts, 1) {
+		assert.Equal(t, expectedAttempt, attempts[0])
+	}
+}
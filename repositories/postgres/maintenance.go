@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 
@@ -13,8 +14,21 @@ var selectMaintenance = psql.
 	Select("rides_maintenance.*", "maintenance_types.maintenance_type", "rides.name AS ride_name").
 	From("rides_maintenance").
 	Join("rides ON rides.ID = rides_maintenance.ride_ID").
-	LeftJoin("maintenance_types ON maintenance_types.ID = rides_maintenance.maintenance_type_ID").
-	OrderBy("rides_maintenance.start_datetime DESC")
+	LeftJoin("maintenance_types ON maintenance_types.ID = rides_maintenance.maintenance_type_ID")
+
+var maintenanceSorting = &sorting{
+	fields: map[string]sortField{
+		"start": {"rides_maintenance.start_datetime", "timestamp", func(row interface{}) string {
+			return timeValue(row.(*models.Maintenance).Start)
+		}},
+		"cost": {"COALESCE(rides_maintenance.cost, 0)", "numeric", func(row interface{}) string {
+			return floatValue(row.(*models.Maintenance).Cost)
+		}},
+	},
+	defaultSort: "-start",
+	id:          "rides_maintenance.id",
+	idOf:        func(row interface{}) string { return row.(*models.Maintenance).ID },
+}
 
 // MaintenanceRepository implements the MaintenanceRepository interface for postgres.
 type MaintenanceRepository struct {
@@ -28,55 +42,77 @@ func NewMaintenanceRepository(db *sqlx.DB) *MaintenanceRepository {
 }
 
 // GetByID fetches a maintenance from the database using the given ID.
-func (rr *MaintenanceRepository) GetByID(ID string) (*models.Maintenance, error) {
+func (rr *MaintenanceRepository) GetByID(ctx context.Context, ID string) (*models.Maintenance, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
 	query, _ := selectMaintenance.Where(sq.Eq{"rides_maintenance.ID": ID}).MustSql()
 
 	maintenance := models.Maintenance{}
-	err := udb.Get(&maintenance, query, ID)
+	err := udb.GetContext(ctx, &maintenance, query, ID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("maintenance job", err)
 	}
 
 	return &maintenance, nil
 }
 
-// Fetch fetches all maintenance from the database.
-func (rr *MaintenanceRepository) Fetch() ([]*models.Maintenance, error) {
+// Fetch fetches a page of the maintenance jobs matching the given filter.
+func (rr *MaintenanceRepository) Fetch(ctx context.Context, filter *models.MaintenanceFilter, page *models.PageRequest) ([]*models.Maintenance, string, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
-	query, _ := selectMaintenance.MustSql()
+	builder := selectMaintenance
+	if len(filter.RideID) > 0 {
+		builder = builder.Where(sq.Eq{"rides_maintenance.ride_id": filter.RideID})
+	}
 
-	maintenance := []*models.Maintenance{}
-	err := udb.Select(&maintenance, query)
-	if err != nil {
-		return nil, err
+	if len(filter.MaintenanceType) > 0 {
+		builder = builder.Where(sq.Eq{"maintenance_types.maintenance_type": filter.MaintenanceType})
 	}
 
-	return maintenance, nil
-}
+	switch filter.Status {
+	case models.MaintenanceOpen:
+		builder = builder.Where(sq.Eq{"rides_maintenance.end_datetime": nil})
+	case models.MaintenanceClosed:
+		builder = builder.Where(sq.NotEq{"rides_maintenance.end_datetime": nil})
+	}
 
-// FetchForRide is similar to Fetch, but fetches for the given ride rather than all entries.
-func (rr *MaintenanceRepository) FetchForRide(rideID string) ([]*models.Maintenance, error) {
-	db := rr.db
-	udb := db.Unsafe()
+	if filter.Since.Valid {
+		builder = builder.Where(sq.GtOrEq{"rides_maintenance.start_datetime": filter.Since.Time})
+	}
 
-	query, _ := selectMaintenance.Where(sq.Eq{"rides_maintenance.ride_id": rideID}).MustSql()
+	if filter.Until.Valid {
+		builder = builder.Where(sq.Lt{"rides_maintenance.start_datetime": filter.Until.Time})
+	}
+
+	builder, err := maintenanceSorting.paginate(builder, page)
+	if err != nil {
+		return nil, "", err
+	}
+
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("selectMaintenance: %w", mapError("maintenance job", err))
+	}
 
 	maintenance := []*models.Maintenance{}
-	err := udb.Select(&maintenance, query, rideID)
+	err = udb.SelectContext(ctx, &maintenance, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", mapError("maintenance job", err)
+	}
+
+	count, more := maintenanceSorting.next(page, len(maintenance))
+	maintenance = maintenance[:count]
+	if !more {
+		return maintenance, "", nil
 	}
 
-	return maintenance, nil
+	return maintenance, maintenanceSorting.cursor(page, maintenance[count-1]), nil
 }
 
 // Store creates an entry for the given maintenance model in the database.
-func (rr *MaintenanceRepository) Store(maintenance *models.Maintenance) error {
+func (rr *MaintenanceRepository) Store(ctx context.Context, maintenance *models.Maintenance) error {
 	db := rr.db
 
 	selectMaintenanceTypeID, _ := psql.
@@ -86,9 +122,9 @@ func (rr *MaintenanceRepository) Store(maintenance *models.Maintenance) error {
 		MustSql()
 
 	var maintenanceTypeID sql.NullString
-	err := rr.db.Get(&maintenanceTypeID, selectMaintenanceTypeID, maintenance.MaintenanceType)
+	err := rr.db.GetContext(ctx, &maintenanceTypeID, selectMaintenanceTypeID, maintenance.MaintenanceType)
 	if err != nil {
-		return fmt.Errorf("selectMaintenanceTypeID: %s", err)
+		return fmt.Errorf("selectMaintenanceTypeID: %w", mapError("maintenance job", err))
 	}
 	if !maintenanceTypeID.Valid {
 		return fmt.Errorf("selectMaintenanceTypeID: could not find valid ID for '%s'", maintenance.MaintenanceType)
@@ -100,16 +136,16 @@ func (rr *MaintenanceRepository) Store(maintenance *models.Maintenance) error {
 		Values("?", "?", "?", "?", "?", "?", "?").
 		ToSql()
 
-	_, err = db.Exec(insertMaintenance, maintenance.ID, maintenance.RideID, maintenanceTypeID, maintenance.Description, maintenance.Cost, maintenance.Start, maintenance.End)
+	_, err = db.ExecContext(ctx, insertMaintenance, maintenance.ID, maintenance.RideID, maintenanceTypeID, maintenance.Description, maintenance.Cost, maintenance.Start, maintenance.End)
 	if err != nil {
-		return fmt.Errorf("inserMaintenance: %s", err)
+		return fmt.Errorf("inserMaintenance: %w", mapError("maintenance job", err))
 	}
 
 	return nil
 }
 
 // Update updates an existing entry in the database for the given Maintenance model.
-func (rr *MaintenanceRepository) Update(maintenance *models.Maintenance) error {
+func (rr *MaintenanceRepository) Update(ctx context.Context, maintenance *models.Maintenance) error {
 	db := rr.db
 
 	selectMaintenanceTypeID, _ := psql.
@@ -119,9 +155,9 @@ func (rr *MaintenanceRepository) Update(maintenance *models.Maintenance) error {
 		MustSql()
 
 	var maintenanceTypeID sql.NullString
-	err := rr.db.Get(&maintenanceTypeID, selectMaintenanceTypeID, maintenance.MaintenanceType)
+	err := rr.db.GetContext(ctx, &maintenanceTypeID, selectMaintenanceTypeID, maintenance.MaintenanceType)
 	if err != nil {
-		return fmt.Errorf("selectMaintenanceTypeID: %s", err)
+		return fmt.Errorf("selectMaintenanceTypeID: %w", mapError("maintenance job", err))
 	}
 	if !maintenanceTypeID.Valid {
 		return fmt.Errorf("selectMaintenanceTypeID: could not find valid ID for '%s'", maintenance.MaintenanceType)
@@ -138,38 +174,38 @@ func (rr *MaintenanceRepository) Update(maintenance *models.Maintenance) error {
 		Where("id = ?").
 		ToSql()
 
-	_, err = db.Exec(updateMaintenance, maintenance.RideID, maintenanceTypeID, maintenance.Description, maintenance.Cost, maintenance.Start, maintenance.End, maintenance.ID)
+	_, err = db.ExecContext(ctx, updateMaintenance, maintenance.RideID, maintenanceTypeID, maintenance.Description, maintenance.Cost, maintenance.Start, maintenance.End, maintenance.ID)
 	if err != nil {
-		return fmt.Errorf("updateMaintenance: %s", err)
+		return fmt.Errorf("updateMaintenance: %w", mapError("maintenance job", err))
 	}
 
 	return nil
 }
 
 // Delete deletes an existing entry in the database for the given Maintenance ID.
-func (rr *MaintenanceRepository) Delete(ID string) error {
+func (rr *MaintenanceRepository) Delete(ctx context.Context, ID string) error {
 	db := rr.db
 
 	deleteMaintenance, _, _ := psql.Delete("rides_maintenance").Where("ID = ?").ToSql()
 
-	_, err := db.Exec(deleteMaintenance, ID)
+	_, err := db.ExecContext(ctx, deleteMaintenance, ID)
 	if err != nil {
-		return fmt.Errorf("deleteMaintenance: %s", err)
+		return fmt.Errorf("deleteMaintenance: %w", mapError("maintenance job", err))
 	}
 
 	return nil
 }
 
 // AvailableMaintenanceTypes returns all the available maintenance types sorted in lexical order.
-func (rr *MaintenanceRepository) AvailableMaintenanceTypes() ([]string, error) {
+func (rr *MaintenanceRepository) AvailableMaintenanceTypes(ctx context.Context) ([]string, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
 	query, _ := psql.Select("DISTINCT maintenance_type").From("maintenance_types").OrderBy("maintenance_type ASC").MustSql()
 	rows := []string{}
-	err := udb.Select(&rows, query)
+	err := udb.SelectContext(ctx, &rows, query)
 	if err != nil {
-		return nil, err
+		return nil, mapError("maintenance job", err)
 	}
 
 	return rows, nil
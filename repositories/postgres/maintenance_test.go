@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"database/sql"
 	"testing"
 
@@ -53,7 +54,7 @@ func TestMaintenanceGetByIDSucceeds(t *testing.T) {
 	_, maintenanceIDs := setupTestMaintenance(db)
 
 	for _, maintenanceID := range maintenanceIDs {
-		maintenance, err := maintenanceRepository.GetByID(maintenanceID)
+		maintenance, err := maintenanceRepository.GetByID(context.Background(), maintenanceID)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -73,7 +74,7 @@ func TestMaintenanceFetchSucceeds(t *testing.T) {
 
 	setupTestMaintenance(db)
 
-	maintenance, err := maintenanceRepository.Fetch()
+	maintenance, _, err := maintenanceRepository.Fetch(context.Background(), &models.MaintenanceFilter{}, nil)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -88,7 +89,7 @@ func TestMaintenanceFetchForRideSucceeds(t *testing.T) {
 	rideIDs, _ := setupTestMaintenance(db)
 	rideID := rideIDs[0]
 
-	maintenance, err := maintenanceRepository.FetchForRide(rideID)
+	maintenance, _, err := maintenanceRepository.Fetch(context.Background(), &models.MaintenanceFilter{RideID: rideID}, nil)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -103,17 +104,17 @@ func TestMaintenanceStoreSucceeds(t *testing.T) {
 	rideIDs, _ := setupTestMaintenance(db)
 	rideID := rideIDs[0]
 
-	users := []*models.User{
-		models.NewEmployee("user--D", "user--D--email", "user--D--passS", "user--D--passH", "Ride Manager", 22),
+	users := []*models.UserPublic{
+		models.NewEmployee("user--D", "user--D--email", "user--D--passS", "user--D--passH", "Ride Manager", 22).Public(),
 	}
 
 	maintenance := models.NewMaintenance("maintenance--ID", rideID, "Ride name", "Tune Up", "description", 60, time.Now(), users)
-	err := maintenanceRepository.Store(maintenance)
+	err := maintenanceRepository.Store(context.Background(), maintenance)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	maintenanceOut, err := maintenanceRepository.GetByID(maintenance.ID)
+	maintenanceOut, err := maintenanceRepository.GetByID(context.Background(), maintenance.ID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -132,24 +133,24 @@ func TestMaintenanceUpdateSucceeds(t *testing.T) {
 	rideID := rideIDs[len(rideIDs)-1]
 	maintenanceID := maintenanceIDs[0]
 
-	maintenance, err := maintenanceRepository.GetByID(maintenanceID)
+	maintenance, err := maintenanceRepository.GetByID(context.Background(), maintenanceID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	users := []*models.User{
-		models.NewEmployee("user--D", "user--D--email", "user--D--passS", "user--D--passH", "Ride Manager", 22),
+	users := []*models.UserPublic{
+		models.NewEmployee("user--D", "user--D--email", "user--D--passS", "user--D--passH", "Ride Manager", 22).Public(),
 	}
 
 	expectedMaintenance := models.NewMaintenance(maintenance.ID, rideID, "new name", "Replacement", "new description", 70, maintenance.Start, users)
 	expectedMaintenance.End = models.FromSQLNullTime(sql.NullTime{Time: time.Now(), Valid: true})
 
-	err = maintenanceRepository.Update(expectedMaintenance)
+	err = maintenanceRepository.Update(context.Background(), expectedMaintenance)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	updatedMaintenance, err := maintenanceRepository.GetByID(maintenanceID)
+	updatedMaintenance, err := maintenanceRepository.GetByID(context.Background(), maintenanceID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -169,12 +170,12 @@ func TestMaintenanceDeleteSucceeds(t *testing.T) {
 	_, maintenanceIDs := setupTestMaintenance(db)
 	maintenanceID := maintenanceIDs[0]
 
-	err := maintenanceRepository.Delete(maintenanceID)
+	err := maintenanceRepository.Delete(context.Background(), maintenanceID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	maintenance, err := maintenanceRepository.GetByID(maintenanceID)
+	maintenance, err := maintenanceRepository.GetByID(context.Background(), maintenanceID)
 	assert.Nil(t, maintenance)
 	assert.NotNil(t, err)
 }
@@ -185,7 +186,7 @@ func TestMaintenanceGetAllMaintenanceTypesSucceeds(t *testing.T) {
 
 	setupTestMaintenance(db)
 
-	maintenanceTypes, err := maintenanceRepository.AvailableMaintenanceTypes()
+	maintenanceTypes, err := maintenanceRepository.AvailableMaintenanceTypes(context.Background())
 
 	if !assert.Nil(t, err) {
 		t.FailNow()
@@ -0,0 +1,161 @@
+package postgres
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// sortField is a field a list can be sorted by: the column (or expression) it
+// is sorted on, its SQL type, and its value in a row as text, which is kept in
+// cursors. The column must not be null.
+type sortField struct {
+	column  string
+	sqlType string
+	value   func(row interface{}) string
+}
+
+// sorting holds the fields a list can be sorted by (the only ones accepted),
+// its default sort, and its unique ID column, which breaks ties so that pages
+// never skip or repeat rows.
+type sorting struct {
+	fields      map[string]sortField
+	defaultSort string
+	id          string
+	idOf        func(row interface{}) string
+}
+
+// cursor points to the last row of a page, by its value of the sort field and
+// its ID.
+type cursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"i"`
+}
+
+// paginate adds the order, the limit and the start of the given page to the
+// given builder (keyset pagination). One row more than the limit is selected
+// to know whether there is a next page, see next. A nil page selects every
+// row in the default order.
+func (s *sorting) paginate(builder sq.SelectBuilder, page *models.PageRequest) (sq.SelectBuilder, error) {
+	if page == nil {
+		field, desc, _ := s.field(s.defaultSort)
+		return builder.OrderBy(orderBy(desc, field.column, s.id)...), nil
+	}
+
+	v := &usecases.Validator{}
+	field, desc, ok := s.field(s.sortOf(page))
+	v.Check(ok, "/sort", usecases.CodeInvalid, "sort must be one of %s", strings.Join(s.names(), ", "))
+
+	if ok && len(page.Cursor) > 0 {
+		c, err := decodeCursor(page.Cursor)
+		if v.Check(err == nil && c.Sort == s.sortOf(page), "/cursor", usecases.CodeInvalid, "cursor is not valid for this sort") {
+			operator := ">"
+			if desc {
+				operator = "<"
+			}
+			builder = builder.Where(fmt.Sprintf("(%s, %s) %s (CAST(? AS %s), ?)", field.column, s.id, operator, field.sqlType), c.Value, c.ID)
+		}
+	}
+
+	err := v.Err()
+	if err != nil {
+		return builder, err
+	}
+
+	return builder.OrderBy(orderBy(desc, field.column, s.id)...).Limit(uint64(page.Limit) + 1), nil
+}
+
+// next returns how many of the given number of selected rows are in the given
+// page, and whether there is a next page.
+func (s *sorting) next(page *models.PageRequest, rows int) (int, bool) {
+	if page == nil || rows <= page.Limit {
+		return rows, false
+	}
+	return page.Limit, true
+}
+
+// cursor returns the cursor of the page after the given one, which ended on
+// the given row.
+func (s *sorting) cursor(page *models.PageRequest, row interface{}) string {
+	field, _, _ := s.field(s.sortOf(page))
+
+	encoded, _ := json.Marshal(&cursor{s.sortOf(page), field.value(row), s.idOf(row)})
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// sortOf returns the sort of the given page, or the default one.
+func (s *sorting) sortOf(page *models.PageRequest) string {
+	if len(page.Sort) <= 0 {
+		return s.defaultSort
+	}
+	return page.Sort
+}
+
+// field returns the field of the given sort, and whether it is descending.
+func (s *sorting) field(sort string) (sortField, bool, bool) {
+	name := strings.TrimPrefix(sort, "-")
+	field, ok := s.fields[name]
+	return field, name != sort, ok
+}
+
+// names returns the names of the fields, sorted.
+func (s *sorting) names() []string {
+	names := make([]string, 0, len(s.fields))
+	for name := range s.fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// decodeCursor decodes the given cursor, as encoded by sorting.cursor.
+func decodeCursor(encoded string) (*cursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &cursor{}
+	err = json.Unmarshal(decoded, c)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// orderBy returns the ORDER BY clauses for the given columns, in the given
+// direction.
+func orderBy(desc bool, columns ...string) []string {
+	direction := " ASC"
+	if desc {
+		direction = " DESC"
+	}
+
+	clauses := make([]string, len(columns))
+	for i, column := range columns {
+		clauses[i] = column + direction
+	}
+	return clauses
+}
+
+// timeValue formats the given time as a value of timestamp columns, which
+// have no time zone and a precision of microseconds.
+func timeValue(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.999999")
+}
+
+// floatValue formats the given float as a value of numeric columns.
+func floatValue(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+func TestPaginateOrdersAndLimits(t *testing.T) {
+	builder, err := ticketSorting.paginate(psql.Select("*").From("tickets"), &models.PageRequest{Limit: 10, Sort: "purchasePrice"})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	query, _, _ := builder.ToSql()
+	assert.Equal(t, "SELECT * FROM tickets ORDER BY tickets.purchase_price ASC, tickets.id ASC LIMIT 11", query)
+
+	builder, _ = ticketSorting.paginate(psql.Select("*").From("tickets"), nil)
+	query, _, _ = builder.ToSql()
+	assert.Equal(t, "SELECT * FROM tickets ORDER BY tickets.purchased_on DESC, tickets.id DESC", query)
+}
+
+func TestPaginateStartsAfterCursor(t *testing.T) {
+	purchasedOn := time.Date(2020, 3, 1, 12, 30, 0, 500000000, time.UTC)
+	page := &models.PageRequest{Limit: 1}
+	page.Cursor = ticketSorting.cursor(page, &models.Ticket{ID: "ticket0", PurchasedOn: purchasedOn})
+
+	builder, err := ticketSorting.paginate(psql.Select("*").From("tickets").Where("tickets.user_id = ?", "user0"), page)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	query, args, _ := builder.ToSql()
+	assert.Equal(t, "SELECT * FROM tickets WHERE tickets.user_id = $1 AND (tickets.purchased_on, tickets.id) < (CAST($2 AS timestamp), $3) ORDER BY tickets.purchased_on DESC, tickets.id DESC LIMIT 2", query)
+	assert.Equal(t, []interface{}{"user0", "2020-03-01 12:30:00.5", "ticket0"}, args)
+}
+
+func TestPaginateRejectsInvalidPages(t *testing.T) {
+	page := &models.PageRequest{Limit: 1, Sort: "-purchasedOn"}
+	cursor := ticketSorting.cursor(page, &models.Ticket{ID: "ticket0"})
+
+	tests := []struct {
+		page    models.PageRequest
+		pointer string
+	}{
+		{models.PageRequest{Limit: 1, Sort: "user_id"}, "/sort"},
+		{models.PageRequest{Limit: 1, Cursor: "not a cursor"}, "/cursor"},
+		{models.PageRequest{Limit: 1, Sort: "purchasedOn", Cursor: cursor}, "/cursor"},
+	}
+
+	for _, tt := range tests {
+		_, err := ticketSorting.paginate(psql.Select("*").From("tickets"), &tt.page)
+
+		e, ok := err.(*usecases.Error)
+		if assert.True(t, ok, "%+v", tt.page) && assert.Len(t, e.Fields, 1) {
+			assert.Equal(t, usecases.KindValidation, e.Kind)
+			assert.Equal(t, tt.pointer, e.Fields[0].Pointer)
+		}
+	}
+
+	_, err := ticketSorting.paginate(psql.Select("*").From("tickets"), &models.PageRequest{Limit: 1, Cursor: cursor})
+	assert.Nil(t, err)
+}
+
+func TestSortingNext(t *testing.T) {
+	page := &models.PageRequest{Limit: 2}
+
+	count, more := ticketSorting.next(page, 3)
+	assert.Equal(t, 2, count)
+	assert.True(t, more)
+
+	count, more = ticketSorting.next(page, 2)
+	assert.Equal(t, 2, count)
+	assert.False(t, more)
+
+	count, more = ticketSorting.next(nil, 5)
+	assert.Equal(t, 5, count)
+	assert.False(t, more)
+}
@@ -0,0 +1,127 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+var selectPasswordResets = psql.Select("password_resets.*").From("password_resets")
+
+// PasswordResetRepository implements the PasswordResetRepository interface
+// for postgres.
+type PasswordResetRepository struct {
+	db *sqlx.DB
+}
+
+// NewPasswordResetRepository creates a new PasswordResetRepository instance
+// using the given database instance.
+func NewPasswordResetRepository(db *sqlx.DB) *PasswordResetRepository {
+	return &PasswordResetRepository{db}
+}
+
+// GetByTokenHash fetches a password reset using the given token hash.
+func (pr *PasswordResetRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+	db := pr.db
+	udb := db.Unsafe()
+
+	query, _ := selectPasswordResets.Where("password_resets.token_hash = ?").MustSql()
+
+	reset := models.PasswordReset{}
+	err := udb.GetContext(ctx, &reset, query, tokenHash)
+	if err != nil {
+		return nil, mapError("password reset", err)
+	}
+
+	return &reset, nil
+}
+
+// Store creates a new password reset.
+func (pr *PasswordResetRepository) Store(ctx context.Context, reset *models.PasswordReset) error {
+	db := pr.db
+
+	insertPasswordReset, _, _ := psql.
+		Insert("password_resets").
+		Columns("ID", "user_ID", "token_hash", "issued_on", "expires_on", "used_on").
+		Values("?", "?", "?", "?", "?", "?").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, insertPasswordReset, reset.ID, reset.UserID, reset.TokenHash, reset.IssuedOn, reset.ExpiresOn, reset.UsedOn)
+	if err != nil {
+		return fmt.Errorf("insertPasswordReset: %w", mapError("password reset", err))
+	}
+
+	return nil
+}
+
+// Redeem marks the given password reset as used, and sets the password of
+// its user and revokes their sessions, all in one transaction. Only one caller
+// can redeem a reset, any other gets an error and changes nothing.
+func (pr *PasswordResetRepository) Redeem(ctx context.Context, reset *models.PasswordReset, usedOn time.Time, passwordSalt, passwordHash string) error {
+	db := pr.db
+
+	markUsed, _, _ := psql.
+		Update("password_resets").
+		Set("used_on", "?").
+		Where("ID = ?").
+		Where("used_on IS NULL").
+		ToSql()
+
+	updatePass, _, _ := psql.
+		Update("users").
+		Set("password_salt", "?").
+		Set("password_hash", "?").
+		Where("ID = ?").
+		ToSql()
+
+	revokeSessions, _, _ := psql.
+		Update("sessions").
+		Set("revoked_on", "?").
+		Where("user_ID = ?").
+		Where("revoked_on IS NULL").
+		ToSql()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return mapError("password reset", err)
+	}
+	defer tx.Rollback()
+
+	{
+		result, err := tx.ExecContext(ctx, markUsed, usedOn, reset.ID)
+		if err != nil {
+			return fmt.Errorf("markUsed: %w", mapError("password reset", err))
+		}
+
+		affected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("markUsed: %w", mapError("password reset", err))
+		}
+
+		if affected != 1 {
+			return usecases.Errorf(usecases.KindNotFound, "markUsed: password reset does not exist or was already used")
+		}
+
+		_, err = tx.ExecContext(ctx, updatePass, passwordSalt, passwordHash, reset.UserID)
+		if err != nil {
+			return fmt.Errorf("updatePass: %w", mapError("user", err))
+		}
+
+		_, err = tx.ExecContext(ctx, revokeSessions, usedOn, reset.UserID)
+		if err != nil {
+			return fmt.Errorf("revokeSessions: %w", mapError("session", err))
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return mapError("password reset", err)
+	}
+
+	return nil
+}
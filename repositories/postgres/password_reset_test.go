@@ -0,0 +1,139 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestPasswordResets(db *sqlx.DB) ([]string, []string) {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE users CASCADE")
+
+	customer0 := generator.MustInsertCustomer(tx, "customer0", "customer0@email.com")
+
+	now := time.Now().UTC()
+	reset0 := generator.MustInsertPasswordReset(tx, customer0, "hash0", now.Add(-time.Hour*2), time.Hour)
+	reset1 := generator.MustInsertPasswordReset(tx, customer0, "hash1", now, time.Hour)
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+
+	return []string{customer0}, []string{reset0, reset1}
+}
+
+// Tests
+// --------------------------------
+
+func TestPasswordResetGetByTokenHashSucceeds(t *testing.T) {
+	passwordResetRepository, db, teardown := testutil.MakePasswordResetRepositoryFixture()
+	defer teardown()
+
+	userIDs, resetIDs := setupTestPasswordResets(db)
+
+	tests := []struct {
+		tokenHash string
+		usable    bool
+	}{
+		{"hash0", false},
+		{"hash1", true},
+	}
+
+	for idx, tt := range tests {
+		reset, err := passwordResetRepository.GetByTokenHash(context.Background(), tt.tokenHash)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, resetIDs[idx], reset.ID)
+		assert.Equal(t, userIDs[0], reset.UserID)
+		assert.Equal(t, tt.usable, reset.IsUsable(time.Now().UTC()))
+	}
+}
+
+func TestPasswordResetGetByTokenHashFails(t *testing.T) {
+	passwordResetRepository, db, teardown := testutil.MakePasswordResetRepositoryFixture()
+	defer teardown()
+
+	setupTestPasswordResets(db)
+
+	_, err := passwordResetRepository.GetByTokenHash(context.Background(), "does-not-exist")
+	assert.NotNil(t, err)
+}
+
+func TestPasswordResetStoreSucceeds(t *testing.T) {
+	passwordResetRepository, db, teardown := testutil.MakePasswordResetRepositoryFixture()
+	defer teardown()
+
+	userIDs, _ := setupTestPasswordResets(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expectedReset := &models.PasswordReset{
+		ID:        "some-reset-id",
+		UserID:    userIDs[0],
+		TokenHash: "some-token-hash",
+		IssuedOn:  now,
+		ExpiresOn: now.Add(time.Hour),
+	}
+
+	err := passwordResetRepository.Store(context.Background(), expectedReset)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	reset, err := passwordResetRepository.GetByTokenHash(context.Background(), expectedReset.TokenHash)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, expectedReset, reset)
+}
+
+func TestPasswordResetRedeemOnlyOnce(t *testing.T) {
+	passwordResetRepository, db, teardown := testutil.MakePasswordResetRepositoryFixture()
+	defer teardown()
+
+	userIDs, _ := setupTestPasswordResets(db)
+	sessionID := generator.MustInsertSession(db, userIDs[0], time.Now().UTC(), time.Hour)
+
+	reset, err := passwordResetRepository.GetByTokenHash(context.Background(), "hash1")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	err = passwordResetRepository.Redeem(context.Background(), reset, time.Now().UTC(), "", "newHash")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	err = passwordResetRepository.Redeem(context.Background(), reset, time.Now().UTC(), "", "otherHash")
+	assert.NotNil(t, err)
+
+	reset, err = passwordResetRepository.GetByTokenHash(context.Background(), "hash1")
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, reset.UsedOn.Valid)
+
+	var passwordHash string
+	db.Get(&passwordHash, "SELECT password_hash FROM users WHERE ID = $1", userIDs[0])
+	assert.Equal(t, "newHash", passwordHash)
+
+	var revoked bool
+	db.Get(&revoked, "SELECT revoked_on IS NOT NULL FROM sessions WHERE ID = $1", sessionID)
+	assert.True(t, revoked)
+}
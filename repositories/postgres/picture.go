@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
@@ -18,37 +19,37 @@ func NewPictureRepository(db *sqlx.DB) *PictureRepository {
 }
 
 // GetByID fetches a single picture using the given ID.
-func (pr *PictureRepository) GetByID(ID string) (*models.Picture, error) {
+func (pr *PictureRepository) GetByID(ctx context.Context, ID string) (*models.Picture, error) {
 	db := pr.db
 	udb := db.Unsafe()
 
 	query, _ := psql.Select("pictures.*").From("pictures").Where("pictures.ID = ?").MustSql()
 
 	picture := models.Picture{}
-	err := udb.Get(&picture, query, ID)
+	err := udb.GetContext(ctx, &picture, query, ID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("picture", err)
 	}
 
 	return &picture, nil
 }
 
 // Delete deletes a single picture using the given ID.
-func (pr *PictureRepository) Delete(ID string) error {
+func (pr *PictureRepository) Delete(ctx context.Context, ID string) error {
 	db := pr.db
 
 	deletePicture, _, _ := psql.Delete("pictures").Where("ID = ?").ToSql()
 
-	_, err := db.Exec(deletePicture, ID)
+	_, err := db.ExecContext(ctx, deletePicture, ID)
 	if err != nil {
-		return fmt.Errorf("deletePicture: %s", err)
+		return fmt.Errorf("deletePicture: %w", mapError("picture", err))
 	}
 
 	return nil
 }
 
 // FetchByCollectionID returns a collection of pictures.
-func (pr *PictureRepository) FetchByCollectionID(collectionID string) ([]*models.Picture, error) {
+func (pr *PictureRepository) FetchByCollectionID(ctx context.Context, collectionID string) ([]*models.Picture, error) {
 	db := pr.db
 	udb := db.Unsafe()
 
@@ -60,22 +61,22 @@ func (pr *PictureRepository) FetchByCollectionID(collectionID string) ([]*models
 		MustSql()
 
 	pictures := []*models.Picture{}
-	err := udb.Select(&pictures, query, collectionID)
+	err := udb.SelectContext(ctx, &pictures, query, collectionID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("picture", err)
 	}
 
 	return pictures, nil
 }
 
 // Store stores the given picture under the given collection ID.
-func (pr *PictureRepository) Store(collectionID string, picture *models.Picture) error {
+func (pr *PictureRepository) Store(ctx context.Context, collectionID string, picture *models.Picture) error {
 	db := pr.db
 
 	ensureCollection, _, _ := psql.Insert("picture_collections").Columns("ID").Values("?").Suffix("ON CONFLICT DO NOTHING").ToSql()
-	_, err := db.Exec(ensureCollection, collectionID)
+	_, err := db.ExecContext(ctx, ensureCollection, collectionID)
 	if err != nil {
-		return fmt.Errorf("ensureCollection: %s", err)
+		return fmt.Errorf("ensureCollection: %w", mapError("picture", err))
 	}
 
 	insertPicture, _, _ := psql.
@@ -90,43 +91,43 @@ func (pr *PictureRepository) Store(collectionID string, picture *models.Picture)
 		Select(psql.Select("?, ?, COUNT(*)").From("pictures_in_collection").Where("collection_ID = ?")).
 		ToSql()
 
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return mapError("picture", err)
 	}
 
 	{
-		_, err = tx.Exec(ensureCollection, collectionID)
+		_, err = tx.ExecContext(ctx, ensureCollection, collectionID)
 		if err != nil {
-			return fmt.Errorf("ensureCollection: %s", err)
+			return fmt.Errorf("ensureCollection: %w", mapError("picture", err))
 		}
 
-		_, err = tx.Exec(insertPicture, picture.ID, picture.Format, picture.Data)
+		_, err = tx.ExecContext(ctx, insertPicture, picture.ID, picture.Format, picture.Data)
 		if err != nil {
-			return fmt.Errorf("insertPicture: %s", err)
+			return fmt.Errorf("insertPicture: %w", mapError("picture", err))
 		}
 
-		_, err = tx.Exec(insertInCollection, collectionID, picture.ID, collectionID)
+		_, err = tx.ExecContext(ctx, insertInCollection, collectionID, picture.ID, collectionID)
 		if err != nil {
-			return fmt.Errorf("insertInColleciton: %s", err)
+			return fmt.Errorf("insertInColleciton: %w", mapError("picture", err))
 		}
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return err
+		return mapError("picture", err)
 	}
 
 	return nil
 }
 
 // UpdateCollectionOrdering updates the ordering of pictures under the given collection ID.
-func (pr *PictureRepository) UpdateCollectionOrdering(collectionID string, fromIndex, toIndex int) error {
+func (pr *PictureRepository) UpdateCollectionOrdering(ctx context.Context, collectionID string, fromIndex, toIndex int) error {
 	return nil
 }
 
 // DeleteCollection deletes all pictures under the given collection ID.
-func (pr *PictureRepository) DeleteCollection(collectionID string) error {
+func (pr *PictureRepository) DeleteCollection(ctx context.Context, collectionID string) error {
 	db := pr.db
 
 	deletePicturesInCollection, _, _ := psql.
@@ -139,26 +140,26 @@ func (pr *PictureRepository) DeleteCollection(collectionID string) error {
 		Where("ID = ?").
 		ToSql()
 
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return mapError("picture", err)
 	}
 
 	{
-		_, err = tx.Exec(deletePicturesInCollection, collectionID)
+		_, err = tx.ExecContext(ctx, deletePicturesInCollection, collectionID)
 		if err != nil {
-			return fmt.Errorf("deletePicturesInCollection: %s", err)
+			return fmt.Errorf("deletePicturesInCollection: %w", mapError("picture", err))
 		}
 
-		_, err := tx.Exec(deleteCollection, collectionID)
+		_, err := tx.ExecContext(ctx, deleteCollection, collectionID)
 		if err != nil {
-			return fmt.Errorf("deleteCollection: %s", err)
+			return fmt.Errorf("deleteCollection: %w", mapError("picture", err))
 		}
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return err
+		return mapError("picture", err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/jmoiron/sqlx"
@@ -34,12 +35,12 @@ func TestStoreSucceeds(t *testing.T) {
 	// first picture
 
 	picture.ID = "picture-0"
-	err := pictureRepository.Store("coll", picture)
+	err := pictureRepository.Store(context.Background(), "coll", picture)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	coll, err := pictureRepository.FetchByCollectionID("coll")
+	coll, err := pictureRepository.FetchByCollectionID(context.Background(), "coll")
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -49,12 +50,12 @@ func TestStoreSucceeds(t *testing.T) {
 	// second picture
 
 	picture.ID = "picture-1"
-	err = pictureRepository.Store("coll", picture)
+	err = pictureRepository.Store(context.Background(), "coll", picture)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	coll, err = pictureRepository.FetchByCollectionID("coll")
+	coll, err = pictureRepository.FetchByCollectionID(context.Background(), "coll")
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -64,12 +65,12 @@ func TestStoreSucceeds(t *testing.T) {
 	// third picture
 
 	picture.ID = "picture-2"
-	err = pictureRepository.Store("coll", picture)
+	err = pictureRepository.Store(context.Background(), "coll", picture)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	coll, err = pictureRepository.FetchByCollectionID("coll")
+	coll, err = pictureRepository.FetchByCollectionID(context.Background(), "coll")
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -0,0 +1,40 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/reports"
+)
+
+// ReportRepository implements the ReportRepository interface for postgres.
+type ReportRepository struct {
+	db *sqlx.DB
+}
+
+// NewReportRepository creates a new ReportRepository instance using the given
+// database instance.
+func NewReportRepository(db *sqlx.DB) *ReportRepository {
+	return &ReportRepository{db}
+}
+
+// Run runs the given report, with the bounds of the range as parameters if
+// it's ranged.
+func (rr *ReportRepository) Run(ctx context.Context, report *reports.Report, dateRange reports.Range) (interface{}, error) {
+	db := rr.db
+
+	args := []interface{}{}
+	if report.Ranged {
+		args = append(args, dateRange.Start, dateRange.End)
+	}
+
+	rows := report.NewRows()
+	err := db.SelectContext(ctx, rows, report.Query(), args...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", report.Name, mapError("report", err))
+	}
+
+	return rows, nil
+}
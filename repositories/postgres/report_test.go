@@ -0,0 +1,90 @@
+package postgres_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/reports"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestReports(db *sqlx.DB) []string {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE users CASCADE")
+	tx.MustExec("TRUNCATE TABLE rides CASCADE")
+	tx.MustExec("TRUNCATE TABLE tickets CASCADE")
+
+	rides := []string{generator.MustInsertRide(tx), generator.MustInsertRide(tx)}
+	customer := generator.MustInsertCustomer(tx, "customer0@email.com", "customer0")
+
+	// rides[0] is ridden twice in January 2020, rides[1] once in February
+	january := time.Date(2020, 1, 15, 12, 0, 0, 0, time.UTC)
+	february := time.Date(2020, 2, 15, 12, 0, 0, 0, time.UTC)
+	ticket := generator.MustInsertTicket(tx, customer, january)
+	generator.MustInsertTicketScan(tx, ticket, rides[0], january)
+	generator.MustInsertTicketScan(tx, ticket, rides[0], january.Add(time.Hour))
+	generator.MustInsertTicketScan(tx, ticket, rides[1], february)
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+
+	return rides
+}
+
+// Tests
+// --------------------------------
+
+func TestReportRunSucceeds(t *testing.T) {
+	reportRepository, db, teardown := testutil.MakeReportRepositoryFixture()
+	defer teardown()
+
+	setupTestReports(db)
+
+	for _, report := range reports.All() {
+		rows, err := reportRepository.Run(context.Background(), report, reports.Range{})
+		assert.Nil(t, err, report.Name)
+		assert.NotNil(t, rows, report.Name)
+	}
+}
+
+func TestReportRunWithRangeSucceeds(t *testing.T) {
+	reportRepository, db, teardown := testutil.MakeReportRepositoryFixture()
+	defer teardown()
+
+	rides := setupTestReports(db)
+	report, _ := reports.Get("frequently_ridden_rides_monthly")
+
+	rows, err := reportRepository.Run(context.Background(), report, reports.Range{})
+	if assert.Nil(t, err) {
+		topRides := *rows.(*[]*reports.MonthlyTopRide)
+		if assert.Len(t, topRides, 2) {
+			assert.Equal(t, rides[1], topRides[0].RideID)
+			assert.Equal(t, 2, topRides[0].Month.Month)
+			assert.Equal(t, "February", topRides[0].MonthName)
+			assert.Equal(t, rides[0], topRides[1].RideID)
+			assert.Equal(t, 2, topRides[1].TimesRidden)
+		}
+	}
+
+	january := sql.NullTime{Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Valid: true}
+	rows, err = reportRepository.Run(context.Background(), report, reports.Range{Start: january, End: january})
+	if assert.Nil(t, err) {
+		topRides := *rows.(*[]*reports.MonthlyTopRide)
+		if assert.Len(t, topRides, 1) {
+			assert.Equal(t, rides[0], topRides[0].RideID)
+			assert.Equal(t, 2020, topRides[0].Year)
+		}
+	}
+}
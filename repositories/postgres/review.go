@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -23,16 +24,16 @@ func NewReviewRepository(db *sqlx.DB) *ReviewRepository {
 }
 
 // GetByID fetches a review from the database using the given ID
-func (rr *ReviewRepository) GetByID(ID string) (*models.Review, error) {
+func (rr *ReviewRepository) GetByID(ctx context.Context, ID string) (*models.Review, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
 	query, _ := selectReviews.Where(sq.Eq{"reviews.ID": ID}).MustSql()
 
 	review := models.Review{}
-	err := udb.Get(&review, query, ID)
+	err := udb.GetContext(ctx, &review, query, ID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("review", err)
 	}
 
 	return &review, nil
@@ -40,55 +41,55 @@ func (rr *ReviewRepository) GetByID(ID string) (*models.Review, error) {
 }
 
 // Fetch fetches all reviews from the database
-func (rr *ReviewRepository) Fetch() ([]*models.Review, error) {
+func (rr *ReviewRepository) Fetch(ctx context.Context) ([]*models.Review, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
 	query, _ := selectReviews.MustSql()
 
 	reviews := []*models.Review{}
-	err := udb.Select(&reviews, query)
+	err := udb.SelectContext(ctx, &reviews, query)
 	if err != nil {
-		return nil, err
+		return nil, mapError("review", err)
 	}
 
 	return reviews, nil
 }
 
 // FetchForRideSortedByRating fetches all reviews from the database for the given ride.
-func (rr *ReviewRepository) FetchForRideSortedByRating(rideID string) ([]*models.Review, error) {
+func (rr *ReviewRepository) FetchForRideSortedByRating(ctx context.Context, rideID string) ([]*models.Review, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
 	query, _ := selectReviews.Where("ride_ID = ?").OrderBy("rating DESC").MustSql()
 
 	reviews := []*models.Review{}
-	err := udb.Select(&reviews, query, rideID)
+	err := udb.SelectContext(ctx, &reviews, query, rideID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("review", err)
 	}
 
 	return reviews, nil
 }
 
 // FetchForRideSortedByDate fetches all reviews from the database for the given ride.
-func (rr *ReviewRepository) FetchForRideSortedByDate(rideID string) ([]*models.Review, error) {
+func (rr *ReviewRepository) FetchForRideSortedByDate(ctx context.Context, rideID string) ([]*models.Review, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
 	query, _ := selectReviews.Where("ride_ID = ?").OrderBy("posted_on DESC").MustSql()
 
 	reviews := []*models.Review{}
-	err := udb.Select(&reviews, query, rideID)
+	err := udb.SelectContext(ctx, &reviews, query, rideID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("review", err)
 	}
 
 	return reviews, nil
 }
 
 // Store creates an entry for the given review model in the database
-func (rr *ReviewRepository) Store(review *models.Review) error {
+func (rr *ReviewRepository) Store(ctx context.Context, review *models.Review) error {
 	db := rr.db
 
 	insertReview, _, _ := psql.
@@ -97,16 +98,16 @@ func (rr *ReviewRepository) Store(review *models.Review) error {
 		Values("?", "?", "?", "?", "?", "?", "?").
 		ToSql()
 
-	_, err := db.Exec(insertReview, review.ID, review.RideID, review.UserID, review.Rating, review.Title, review.Content, review.PostedOn)
+	_, err := db.ExecContext(ctx, insertReview, review.ID, review.RideID, review.UserID, review.Rating, review.Title, review.Content, review.PostedOn)
 	if err != nil {
-		return fmt.Errorf("insertReview: %s", err)
+		return fmt.Errorf("insertReview: %w", mapError("review", err))
 	}
 
 	return nil
 }
 
 // Update updates an existing entry in the database for the given review model
-func (rr *ReviewRepository) Update(review *models.Review) error {
+func (rr *ReviewRepository) Update(ctx context.Context, review *models.Review) error {
 	db := rr.db
 
 	updateReview, _, _ := psql.
@@ -120,23 +121,23 @@ func (rr *ReviewRepository) Update(review *models.Review) error {
 		Where("id = ?").
 		ToSql()
 
-	_, err := db.Exec(updateReview, review.RideID, review.UserID, review.Rating, review.Title, review.Content, review.PostedOn, review.ID)
+	_, err := db.ExecContext(ctx, updateReview, review.RideID, review.UserID, review.Rating, review.Title, review.Content, review.PostedOn, review.ID)
 	if err != nil {
-		return fmt.Errorf("updateReview: %s", err)
+		return fmt.Errorf("updateReview: %w", mapError("review", err))
 	}
 
 	return nil
 }
 
 // Delete deletes an existing entry in the database for the given review ID
-func (rr *ReviewRepository) Delete(ID string) error {
+func (rr *ReviewRepository) Delete(ctx context.Context, ID string) error {
 	db := rr.db
 
 	deleteReview, _, _ := psql.Delete("reviews").Where("id = ?").ToSql()
 
-	_, err := db.Exec(deleteReview, ID)
+	_, err := db.ExecContext(ctx, deleteReview, ID)
 	if err != nil {
-		return fmt.Errorf("deleteReview: %s", err)
+		return fmt.Errorf("deleteReview: %w", mapError("review", err))
 	}
 
 	return nil
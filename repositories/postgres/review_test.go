@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
 
 	"testing"
@@ -60,7 +61,7 @@ func TestReviewGetByIDSucceeds(t *testing.T) {
 	_, _, reviewIDs := setupTestReviews(db)
 
 	for _, reviewID := range reviewIDs {
-		review, err := reviewRepository.GetByID(reviewID)
+		review, err := reviewRepository.GetByID(context.Background(), reviewID)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -78,7 +79,7 @@ func TestReviewGetByIDNoMatchFails(t *testing.T) {
 	reviewRepository, _, teardown := testutil.MakeReviewRepositoryFixture()
 	defer teardown()
 
-	review, err := reviewRepository.GetByID("some-unknown-ID")
+	review, err := reviewRepository.GetByID(context.Background(), "some-unknown-ID")
 	assert.Nil(t, review)
 	assert.NotNil(t, err)
 }
@@ -89,7 +90,7 @@ func TestReviewFetchSucceeds(t *testing.T) {
 
 	_, _, reviewIDs := setupTestReviews(db)
 
-	reviews, err := reviewRepository.Fetch()
+	reviews, err := reviewRepository.Fetch(context.Background())
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -108,12 +109,12 @@ func TestReviewStoreSucceeds(t *testing.T) {
 	expectedReview := models.NewReview("review--ID", 1, "review--ID--title", "review--ID--content", time.Now().UTC())
 	expectedReview.RideID = rideID
 	expectedReview.UserID = userID
-	err := reviewRepository.Store(expectedReview)
+	err := reviewRepository.Store(context.Background(), expectedReview)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	review, err := reviewRepository.GetByID(expectedReview.ID)
+	review, err := reviewRepository.GetByID(context.Background(), expectedReview.ID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -135,7 +136,7 @@ func TestReviewUpdateSucceeds(t *testing.T) {
 	_, _, reviewIDs := setupTestReviews(db)
 	reviewID := reviewIDs[0]
 
-	review, err := reviewRepository.GetByID(reviewID)
+	review, err := reviewRepository.GetByID(context.Background(), reviewID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -143,12 +144,12 @@ func TestReviewUpdateSucceeds(t *testing.T) {
 	expectedReview := models.NewReview(review.ID, 1, "new title", "new content", time.Now().UTC())
 	expectedReview.RideID = review.RideID
 	expectedReview.UserID = review.UserID
-	err = reviewRepository.Update(expectedReview)
+	err = reviewRepository.Update(context.Background(), expectedReview)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	updatedReview, err := reviewRepository.GetByID(reviewID)
+	updatedReview, err := reviewRepository.GetByID(context.Background(), reviewID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -169,12 +170,12 @@ func TestReviewDeleteSucceeds(t *testing.T) {
 	_, _, reviewIds := setupTestReviews(db)
 	reviewID := reviewIds[0]
 
-	err := reviewRepository.Delete(reviewID)
+	err := reviewRepository.Delete(context.Background(), reviewID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	review, err := reviewRepository.GetByID(reviewID)
+	review, err := reviewRepository.GetByID(context.Background(), reviewID)
 	assert.Nil(t, review)
 	assert.NotNil(t, err)
 }
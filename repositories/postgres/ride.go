@@ -1,6 +1,7 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	sq "github.com/Masterminds/squirrel"
@@ -23,39 +24,39 @@ func NewRideRepository(db *sqlx.DB) *RideRepository {
 }
 
 // GetByID fetches a ride from the database using the given ID.
-func (rr *RideRepository) GetByID(ID string) (*models.Ride, error) {
+func (rr *RideRepository) GetByID(ctx context.Context, ID string) (*models.Ride, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
 	query, _ := selectRides.Where(sq.Eq{"rides.ID": ID}).MustSql()
 
 	ride := models.Ride{}
-	err := udb.Get(&ride, query, ID)
+	err := udb.GetContext(ctx, &ride, query, ID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("ride", err)
 	}
 
 	return &ride, nil
 }
 
 // Fetch fetches all rides from the database.
-func (rr *RideRepository) Fetch() ([]*models.Ride, error) {
+func (rr *RideRepository) Fetch(ctx context.Context) ([]*models.Ride, error) {
 	db := rr.db
 	udb := db.Unsafe()
 
 	query, _ := selectRides.MustSql()
 
 	rides := []*models.Ride{}
-	err := udb.Select(&rides, query)
+	err := udb.SelectContext(ctx, &rides, query)
 	if err != nil {
-		return nil, err
+		return nil, mapError("ride", err)
 	}
 
 	return rides, err
 }
 
 // Store creates an entry for the given ride model in the database.
-func (rr *RideRepository) Store(ride *models.Ride) error {
+func (rr *RideRepository) Store(ctx context.Context, ride *models.Ride) error {
 	db := rr.db
 
 	insertRide, _, _ := psql.
@@ -64,16 +65,16 @@ func (rr *RideRepository) Store(ride *models.Ride) error {
 		Values("?", "?", "?", "?", "?", "?", "?").
 		ToSql()
 
-	_, err := db.Exec(insertRide, ride.ID, ride.Name, ride.Description, ride.MinAge, ride.MinHeight, ride.Longitude, ride.Latitude)
+	_, err := db.ExecContext(ctx, insertRide, ride.ID, ride.Name, ride.Description, ride.MinAge, ride.MinHeight, ride.Longitude, ride.Latitude)
 	if err != nil {
-		return fmt.Errorf("inserRide: %s", err)
+		return fmt.Errorf("inserRide: %w", mapError("ride", err))
 	}
 
 	return nil
 }
 
 // Update updates an existing entry in the database for the given ride model.
-func (rr *RideRepository) Update(ride *models.Ride) error {
+func (rr *RideRepository) Update(ctx context.Context, ride *models.Ride) error {
 	db := rr.db
 
 	updateRide, _, _ := psql.
@@ -87,23 +88,23 @@ func (rr *RideRepository) Update(ride *models.Ride) error {
 		Where("id = ?").
 		ToSql()
 
-	_, err := db.Exec(updateRide, ride.Name, ride.Description, ride.MinAge, ride.MinHeight, ride.Longitude, ride.Latitude, ride.ID)
+	_, err := db.ExecContext(ctx, updateRide, ride.Name, ride.Description, ride.MinAge, ride.MinHeight, ride.Longitude, ride.Latitude, ride.ID)
 	if err != nil {
-		return fmt.Errorf("updateRide: %s", err)
+		return fmt.Errorf("updateRide: %w", mapError("ride", err))
 	}
 
 	return nil
 }
 
 // Delete deletes an existing entry in the database for the given ride ID.
-func (rr *RideRepository) Delete(ID string) error {
+func (rr *RideRepository) Delete(ctx context.Context, ID string) error {
 	db := rr.db
 
 	deleteRide, _, _ := psql.Delete("rides").Where("id = ?").ToSql()
 
-	_, err := db.Exec(deleteRide, ID)
+	_, err := db.ExecContext(ctx, deleteRide, ID)
 	if err != nil {
-		return fmt.Errorf("deleteRide: %s", err)
+		return fmt.Errorf("deleteRide: %w", mapError("ride", err))
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
 	//"database/sql"
 
@@ -45,7 +46,7 @@ func TestRideGetByIDSucceeds(t *testing.T) {
 	tests := setupTestRides(db)
 
 	for _, rideID := range tests {
-		ride, err := rideRepository.GetByID(rideID)
+		ride, err := rideRepository.GetByID(context.Background(), rideID)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -64,7 +65,7 @@ func TestRideGetByIDNoMatchFails(t *testing.T) {
 	rideRepository, _, teardown := testutil.MakeRideRepositoryFixture()
 	defer teardown()
 
-	ride, err := rideRepository.GetByID("some-unknown-ID")
+	ride, err := rideRepository.GetByID(context.Background(), "some-unknown-ID")
 	assert.Nil(t, ride)
 	assert.NotNil(t, err)
 }
@@ -75,7 +76,7 @@ func TestRideFetchSucceeds(t *testing.T) {
 
 	setupTestRides(db)
 
-	rides, err := rideRepository.Fetch()
+	rides, err := rideRepository.Fetch(context.Background())
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -88,12 +89,12 @@ func TestRideStoreSucceeds(t *testing.T) {
 	defer teardown()
 
 	expectedRide := models.NewRide("ride--ID", "ride--ID--name", "ride--ID--description", 1, 2, 3, 4)
-	err := rideRepository.Store(expectedRide)
+	err := rideRepository.Store(context.Background(), expectedRide)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	ride, err := rideRepository.GetByID(expectedRide.ID)
+	ride, err := rideRepository.GetByID(context.Background(), expectedRide.ID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -116,18 +117,18 @@ func TestRideUpdateSucceeds(t *testing.T) {
 	tests := setupTestRides(db)
 	rideID := tests[0]
 
-	ride, err := rideRepository.GetByID(rideID)
+	ride, err := rideRepository.GetByID(context.Background(), rideID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
 	expectedRide := models.NewRide(ride.ID, "new name", "new description", 4, 4, 4, 4)
-	err = rideRepository.Update(expectedRide)
+	err = rideRepository.Update(context.Background(), expectedRide)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	updatedRide, err := rideRepository.GetByID(rideID)
+	updatedRide, err := rideRepository.GetByID(context.Background(), rideID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -148,12 +149,12 @@ func TestRideDeleteSucceeds(t *testing.T) {
 	tests := setupTestRides(db)
 	rideID := tests[0]
 
-	err := rideRepository.Delete(rideID)
+	err := rideRepository.Delete(context.Background(), rideID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	ride, err := rideRepository.GetByID(rideID)
+	ride, err := rideRepository.GetByID(context.Background(), rideID)
 	assert.Nil(t, ride)
 	assert.NotNil(t, err)
 }
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+var selectRoles = psql.Select("roles.*").From("roles").OrderBy("roles.role ASC")
+
+// RoleRepository implements the RoleRepository interface for postgres.
+type RoleRepository struct {
+	db *sqlx.DB
+}
+
+// NewRoleRepository creates a new RoleRepository instance using the given
+// database instance.
+func NewRoleRepository(db *sqlx.DB) *RoleRepository {
+	return &RoleRepository{db}
+}
+
+// GetByID fetches a role using the given ID.
+func (rr *RoleRepository) GetByID(ctx context.Context, ID string) (*models.Role, error) {
+	db := rr.db
+	udb := db.Unsafe()
+
+	query, _ := selectRoles.Where("roles.ID = ?").MustSql()
+
+	role := models.Role{}
+	err := udb.GetContext(ctx, &role, query, ID)
+	if err != nil {
+		return nil, mapError("role", err)
+	}
+
+	return &role, nil
+}
+
+// Fetch fetches all roles.
+func (rr *RoleRepository) Fetch(ctx context.Context) ([]*models.Role, error) {
+	db := rr.db
+	udb := db.Unsafe()
+
+	query, _ := selectRoles.MustSql()
+
+	roles := []*models.Role{}
+	err := udb.SelectContext(ctx, &roles, query)
+	if err != nil {
+		return nil, mapError("role", err)
+	}
+
+	return roles, nil
+}
+
+// UpdateRequiresTwoFactor sets whether employees with the given role must use
+// two-factor authentication.
+func (rr *RoleRepository) UpdateRequiresTwoFactor(ctx context.Context, ID string, requiresTwoFactor bool) error {
+	db := rr.db
+
+	updateRole, _, _ := psql.
+		Update("roles").
+		Set("requires_two_factor", "?").
+		Where("ID = ?").
+		ToSql()
+
+	result, err := db.ExecContext(ctx, updateRole, requiresTwoFactor, ID)
+	if err != nil {
+		return fmt.Errorf("updateRole: %w", mapError("role", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("updateRole: %w", mapError("role", err))
+	}
+
+	if affected != 1 {
+		return usecases.Errorf(usecases.KindNotFound, "updateRole: role does not exist")
+	}
+
+	return nil
+}
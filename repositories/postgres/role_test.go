@@ -0,0 +1,83 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestRoles(db *sqlx.DB) []string {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE roles CASCADE")
+
+	role0 := generator.MustInsertRole(tx, "Supervisor")
+	role1 := generator.MustInsertRole(tx, "Worker")
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+
+	return []string{role0, role1}
+}
+
+// Tests
+// --------------------------------
+
+func TestRoleFetchSucceeds(t *testing.T) {
+	roleRepository, db, teardown := testutil.MakeRoleRepositoryFixture()
+	defer teardown()
+
+	roleIDs := setupTestRoles(db)
+
+	roles, err := roleRepository.Fetch(context.Background())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	if assert.Len(t, roles, len(roleIDs)) {
+		for idx, role := range roles {
+			assert.Equal(t, roleIDs[idx], role.ID)
+			assert.False(t, role.RequiresTwoFactor)
+		}
+	}
+}
+
+func TestRoleUpdateRequiresTwoFactorSucceeds(t *testing.T) {
+	roleRepository, db, teardown := testutil.MakeRoleRepositoryFixture()
+	defer teardown()
+
+	roleIDs := setupTestRoles(db)
+
+	err := roleRepository.UpdateRequiresTwoFactor(context.Background(), roleIDs[0], true)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	role, err := roleRepository.GetByID(context.Background(), roleIDs[0])
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "Supervisor", role.Role)
+	assert.True(t, role.RequiresTwoFactor)
+}
+
+func TestRoleUpdateRequiresTwoFactorFails(t *testing.T) {
+	roleRepository, db, teardown := testutil.MakeRoleRepositoryFixture()
+	defer teardown()
+
+	setupTestRoles(db)
+
+	err := roleRepository.UpdateRequiresTwoFactor(context.Background(), "does-not-exist", true)
+	assert.NotNil(t, err)
+}
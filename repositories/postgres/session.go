@@ -0,0 +1,118 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+var selectSessions = psql.Select("sessions.*").From("sessions").OrderBy("sessions.issued_on DESC")
+
+// SessionRepository implements the SessionRepository interface for postgres.
+type SessionRepository struct {
+	db *sqlx.DB
+}
+
+// NewSessionRepository creates a new SessionRepository instance using the given
+// database instance.
+func NewSessionRepository(db *sqlx.DB) *SessionRepository {
+	return &SessionRepository{db}
+}
+
+// GetByID fetches a session using the given ID.
+func (sr *SessionRepository) GetByID(ctx context.Context, ID string) (*models.Session, error) {
+	db := sr.db
+	udb := db.Unsafe()
+
+	query, _ := selectSessions.Where("sessions.ID = ?").MustSql()
+
+	session := models.Session{}
+	err := udb.GetContext(ctx, &session, query, ID)
+	if err != nil {
+		return nil, mapError("session", err)
+	}
+
+	return &session, nil
+}
+
+// FetchActiveForUser fetches the sessions for the given user that are neither
+// expired nor revoked at the given time.
+func (sr *SessionRepository) FetchActiveForUser(ctx context.Context, userID string, now time.Time) ([]*models.Session, error) {
+	db := sr.db
+	udb := db.Unsafe()
+
+	query, _ := selectSessions.
+		Where("sessions.user_ID = ?").
+		Where("sessions.revoked_on IS NULL").
+		Where("sessions.expires_on > ?").
+		MustSql()
+
+	sessions := []*models.Session{}
+	err := udb.SelectContext(ctx, &sessions, query, userID, now)
+	if err != nil {
+		return nil, mapError("session", err)
+	}
+
+	return sessions, nil
+}
+
+// Store creates a new session.
+func (sr *SessionRepository) Store(ctx context.Context, session *models.Session) error {
+	db := sr.db
+
+	insertSession, _, _ := psql.
+		Insert("sessions").
+		Columns("ID", "user_ID", "user_agent", "address", "issued_on", "expires_on", "revoked_on").
+		Values("?", "?", "?", "?", "?", "?", "?").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, insertSession, session.ID, session.UserID, session.UserAgent, session.Address, session.IssuedOn, session.ExpiresOn, session.RevokedOn)
+	if err != nil {
+		return fmt.Errorf("insertSession: %w", mapError("session", err))
+	}
+
+	return nil
+}
+
+// Revoke revokes the session with the given ID. Sessions that were already
+// revoked keep their original revocation time.
+func (sr *SessionRepository) Revoke(ctx context.Context, ID string, revokedOn time.Time) error {
+	db := sr.db
+
+	revokeSession, _, _ := psql.
+		Update("sessions").
+		Set("revoked_on", "?").
+		Where("ID = ?").
+		Where("revoked_on IS NULL").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, revokeSession, revokedOn, ID)
+	if err != nil {
+		return fmt.Errorf("revokeSession: %w", mapError("session", err))
+	}
+
+	return nil
+}
+
+// RevokeForUser revokes all the sessions for the given user.
+func (sr *SessionRepository) RevokeForUser(ctx context.Context, userID string, revokedOn time.Time) error {
+	db := sr.db
+
+	revokeSessions, _, _ := psql.
+		Update("sessions").
+		Set("revoked_on", "?").
+		Where("user_ID = ?").
+		Where("revoked_on IS NULL").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, revokeSessions, revokedOn, userID)
+	if err != nil {
+		return fmt.Errorf("revokeSessions: %w", mapError("session", err))
+	}
+
+	return nil
+}
@@ -0,0 +1,152 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestSessions(db *sqlx.DB) ([]string, []string) {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE users CASCADE")
+
+	customer0 := generator.MustInsertCustomer(tx, "customer0", "customer0@email.com")
+	customer1 := generator.MustInsertCustomer(tx, "customer1", "customer1@email.com")
+
+	// sessions per customer = customer index + 1, the first one always expired
+	now := time.Now().UTC()
+	session0 := generator.MustInsertSession(tx, customer0, now.Add(-time.Hour*2), time.Hour)
+	session1 := generator.MustInsertSession(tx, customer1, now.Add(-time.Hour*2), time.Hour)
+	session2 := generator.MustInsertSession(tx, customer1, now, time.Hour)
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+
+	return []string{customer0, customer1}, []string{session0, session1, session2}
+}
+
+// Tests
+// --------------------------------
+
+func TestSessionGetByIDSucceeds(t *testing.T) {
+	sessionRepository, db, teardown := testutil.MakeSessionRepositoryFixture()
+	defer teardown()
+
+	_, sessionIDs := setupTestSessions(db)
+
+	for _, sessionID := range sessionIDs {
+		session, err := sessionRepository.GetByID(context.Background(), sessionID)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, sessionID, session.ID)
+		assert.NotEmpty(t, session.UserID)
+		assert.True(t, session.IssuedOn.Before(session.ExpiresOn))
+		assert.False(t, session.RevokedOn.Valid)
+	}
+}
+
+func TestSessionFetchActiveForUserSucceeds(t *testing.T) {
+	sessionRepository, db, teardown := testutil.MakeSessionRepositoryFixture()
+	defer teardown()
+
+	userIDs, _ := setupTestSessions(db)
+
+	for idx, userID := range userIDs {
+		sessions, err := sessionRepository.FetchActiveForUser(context.Background(), userID, time.Now().UTC())
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Len(t, sessions, idx)
+	}
+}
+
+func TestSessionStoreSucceeds(t *testing.T) {
+	sessionRepository, db, teardown := testutil.MakeSessionRepositoryFixture()
+	defer teardown()
+
+	userIDs, _ := setupTestSessions(db)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	expectedSession := &models.Session{
+		ID:        "some-session-id",
+		UserID:    userIDs[0],
+		IssuedOn:  now,
+		ExpiresOn: now.Add(time.Hour),
+	}
+
+	err := sessionRepository.Store(context.Background(), expectedSession)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	session, err := sessionRepository.GetByID(context.Background(), expectedSession.ID)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, expectedSession, session)
+}
+
+func TestSessionRevokeSucceeds(t *testing.T) {
+	sessionRepository, db, teardown := testutil.MakeSessionRepositoryFixture()
+	defer teardown()
+
+	userIDs, sessionIDs := setupTestSessions(db)
+	userID := userIDs[1]
+	sessionID := sessionIDs[2]
+
+	err := sessionRepository.Revoke(context.Background(), sessionID, time.Now().UTC())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	session, err := sessionRepository.GetByID(context.Background(), sessionID)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.True(t, session.RevokedOn.Valid)
+
+	sessions, err := sessionRepository.FetchActiveForUser(context.Background(), userID, time.Now().UTC())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Len(t, sessions, 0)
+}
+
+func TestSessionRevokeForUserSucceeds(t *testing.T) {
+	sessionRepository, db, teardown := testutil.MakeSessionRepositoryFixture()
+	defer teardown()
+
+	userIDs, _ := setupTestSessions(db)
+	userID := userIDs[1]
+
+	err := sessionRepository.RevokeForUser(context.Background(), userID, time.Now().UTC())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	sessions, err := sessionRepository.FetchActiveForUser(context.Background(), userID, time.Now().UTC())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Len(t, sessions, 0)
+}
@@ -1,6 +1,9 @@
 package postgres
 
 import (
+	"context"
+	"fmt"
+
 	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 
@@ -11,22 +14,49 @@ var selectTickets = psql.
 	Select(
 		"tickets.*",
 		"(DATE_TRUNC('day', tickets.purchased_on) = DATE_TRUNC('day', NOW())) AS is_valid",
-		"users.email",
-		"user_details.first_name",
-		"user_details.last_name",
+		`tickets.user_id AS "user.id"`,
+		`user_details.first_name AS "user.first_name"`,
+		`user_details.last_name AS "user.last_name"`,
 	).
 	From("tickets").
-	Join("users ON users.id = tickets.user_id").
-	LeftJoin("user_details ON user_details.user_id = tickets.user_id").
-	OrderBy("tickets.purchased_on DESC")
+	LeftJoin("user_details ON user_details.user_id = tickets.user_id")
 
 var selectTicketScans = psql.
-	Select("scans.*", "users.email", "user_details.first_name", "user_details.last_name").
+	Select(
+		"scans.*",
+		"tickets.user_id",
+		`tickets.user_id AS "user.id"`,
+		`user_details.first_name AS "user.first_name"`,
+		`user_details.last_name AS "user.last_name"`,
+	).
 	From("tickets_on_rides AS scans").
 	Join("tickets ON tickets.id = scans.ticket_id").
-	Join("users ON users.id = tickets.user_id").
-	LeftJoin("user_details ON user_details.user_id = tickets.user_id").
-	OrderBy("scans.scan_datetime DESC")
+	LeftJoin("user_details ON user_details.user_id = tickets.user_id")
+
+var ticketSorting = &sorting{
+	fields: map[string]sortField{
+		"purchasedOn": {"tickets.purchased_on", "timestamp", func(row interface{}) string {
+			return timeValue(row.(*models.Ticket).PurchasedOn)
+		}},
+		"purchasePrice": {"tickets.purchase_price", "numeric", func(row interface{}) string {
+			return floatValue(row.(*models.Ticket).PurchasePrice)
+		}},
+	},
+	defaultSort: "-purchasedOn",
+	id:          "tickets.id",
+	idOf:        func(row interface{}) string { return row.(*models.Ticket).ID },
+}
+
+var ticketScanSorting = &sorting{
+	fields: map[string]sortField{
+		"scanOn": {"scans.scan_datetime", "timestamp", func(row interface{}) string {
+			return timeValue(row.(*models.TicketScan).ScanOn)
+		}},
+	},
+	defaultSort: "-scanOn",
+	id:          "scans.id",
+	idOf:        func(row interface{}) string { return row.(*models.TicketScan).ID },
+}
 
 // TicketRepository implements the TicketRepository interface for postgres.
 type TicketRepository struct {
@@ -39,103 +69,117 @@ func NewTicketRepository(db *sqlx.DB) *TicketRepository {
 }
 
 // GetByID fetches a ticket using the given ID.
-func (tr *TicketRepository) GetByID(ID string) (*models.Ticket, error) {
+func (tr *TicketRepository) GetByID(ctx context.Context, ID string) (*models.Ticket, error) {
 	db := tr.db
 	udb := db.Unsafe()
 
 	query, _ := selectTickets.Where(sq.Eq{"tickets.id": "$1"}).MustSql()
 
 	ticket := models.Ticket{}
-	err := udb.Get(&ticket, query, ID)
+	err := udb.GetContext(ctx, &ticket, query, ID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("ticket", err)
 	}
 
 	return &ticket, nil
 }
 
-// Fetch fetches all tickets.
-func (tr *TicketRepository) Fetch() ([]*models.Ticket, error) {
+// Fetch fetches a page of the tickets matching the given filter.
+func (tr *TicketRepository) Fetch(ctx context.Context, filter *models.TicketFilter, page *models.PageRequest) ([]*models.Ticket, string, error) {
 	db := tr.db
 	udb := db.Unsafe()
 
-	query, _ := selectTickets.MustSql()
+	builder := selectTickets
+	if len(filter.UserID) > 0 {
+		builder = builder.Where(sq.Eq{"tickets.user_id": filter.UserID})
+	}
 
-	tickets := []*models.Ticket{}
-	err := udb.Select(&tickets, query)
-	if err != nil {
-		return nil, err
+	if filter.Since.Valid {
+		builder = builder.Where(sq.GtOrEq{"tickets.purchased_on": filter.Since.Time})
 	}
 
-	return tickets, nil
-}
+	if filter.Until.Valid {
+		builder = builder.Where(sq.Lt{"tickets.purchased_on": filter.Until.Time})
+	}
 
-// FetchForUser fetches all tickets for the given user.
-func (tr *TicketRepository) FetchForUser(userID string) ([]*models.Ticket, error) {
-	db := tr.db
-	udb := db.Unsafe()
+	builder, err := ticketSorting.paginate(builder, page)
+	if err != nil {
+		return nil, "", err
+	}
 
-	query, _ := selectTickets.Where(sq.Eq{"tickets.user_id": "$1"}).MustSql()
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("selectTickets: %w", mapError("ticket", err))
+	}
 
 	tickets := []*models.Ticket{}
-	err := udb.Select(&tickets, query, userID)
+	err = udb.SelectContext(ctx, &tickets, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", mapError("ticket", err)
+	}
+
+	count, more := ticketSorting.next(page, len(tickets))
+	tickets = tickets[:count]
+	if !more {
+		return tickets, "", nil
 	}
 
-	return tickets, nil
+	return tickets, ticketSorting.cursor(page, tickets[count-1]), nil
 }
 
-// FetchScans fetches all ticket scans.
-func (tr *TicketRepository) FetchScans() ([]*models.TicketScan, error) {
+// FetchScans fetches a page of the ticket scans matching the given filter.
+func (tr *TicketRepository) FetchScans(ctx context.Context, filter *models.TicketScanFilter, page *models.PageRequest) ([]*models.TicketScan, string, error) {
 	db := tr.db
 	udb := db.Unsafe()
 
-	query, _ := selectTicketScans.MustSql()
+	builder := selectTicketScans
+	if len(filter.RideID) > 0 {
+		builder = builder.Where(sq.Eq{"scans.ride_id": filter.RideID})
+	}
 
-	scans := []*models.TicketScan{}
-	err := udb.Select(&scans, query)
-	if err != nil {
-		return nil, err
+	if len(filter.UserID) > 0 {
+		builder = builder.Where(sq.Eq{"tickets.user_id": filter.UserID})
 	}
 
-	return scans, nil
-}
+	if len(filter.TicketID) > 0 {
+		builder = builder.Where(sq.Eq{"scans.ticket_id": filter.TicketID})
+	}
 
-// FetchScansForRide fetches all ticket scans for the given ride.
-func (tr *TicketRepository) FetchScansForRide(rideID string) ([]*models.TicketScan, error) {
-	db := tr.db
-	udb := db.Unsafe()
+	if filter.Since.Valid {
+		builder = builder.Where(sq.GtOrEq{"scans.scan_datetime": filter.Since.Time})
+	}
 
-	query, _ := selectTicketScans.Where(sq.Eq{"scans.ride_id": "$1"}).MustSql()
+	if filter.Until.Valid {
+		builder = builder.Where(sq.Lt{"scans.scan_datetime": filter.Until.Time})
+	}
 
-	scans := []*models.TicketScan{}
-	err := udb.Select(&scans, query, rideID)
+	builder, err := ticketScanSorting.paginate(builder, page)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return scans, nil
-}
-
-// FetchScansForUser fetches all ticket scans for the given user.
-func (tr *TicketRepository) FetchScansForUser(userID string) ([]*models.TicketScan, error) {
-	db := tr.db
-	udb := db.Unsafe()
-
-	query, _ := selectTicketScans.Where(sq.Eq{"tickets.user_id": "$1"}).MustSql()
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("selectTicketScans: %w", mapError("ticket scan", err))
+	}
 
 	scans := []*models.TicketScan{}
-	err := udb.Select(&scans, query, userID)
+	err = udb.SelectContext(ctx, &scans, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", mapError("ticket scan", err)
+	}
+
+	count, more := ticketScanSorting.next(page, len(scans))
+	scans = scans[:count]
+	if !more {
+		return scans, "", nil
 	}
 
-	return scans, nil
+	return scans, ticketScanSorting.cursor(page, scans[count-1]), nil
 }
 
 // Store creates a new ticket.
-func (tr *TicketRepository) Store(ticket *models.Ticket) error {
+func (tr *TicketRepository) Store(ctx context.Context, ticket *models.Ticket) error {
 	db := tr.db
 
 	query, _, _ := psql.
@@ -144,16 +188,16 @@ func (tr *TicketRepository) Store(ticket *models.Ticket) error {
 		Values("$1", "$2", "$3", "$4", "$5", "$6").
 		ToSql()
 
-	_, err := db.Exec(query, ticket.ID, ticket.UserID, ticket.IsKid, ticket.PurchasePrice, ticket.PurchasedOn, ticket.PurchaseReference)
+	_, err := db.ExecContext(ctx, query, ticket.ID, ticket.UserID, ticket.IsKid, ticket.PurchasePrice, ticket.PurchasedOn, ticket.PurchaseReference)
 	if err != nil {
-		return err
+		return mapError("ticket", err)
 	}
 
 	return nil
 }
 
 // Update updates an existing ticket.
-func (tr *TicketRepository) Update(ticket *models.Ticket) error {
+func (tr *TicketRepository) Update(ctx context.Context, ticket *models.Ticket) error {
 	db := tr.db
 
 	query, _, _ := psql.
@@ -166,16 +210,16 @@ func (tr *TicketRepository) Update(ticket *models.Ticket) error {
 		Where(sq.Eq{"id": "$6"}).
 		ToSql()
 
-	_, err := db.Exec(query, ticket.UserID, ticket.IsKid, ticket.PurchasePrice, ticket.PurchasedOn, ticket.PurchaseReference, ticket.ID)
+	_, err := db.ExecContext(ctx, query, ticket.UserID, ticket.IsKid, ticket.PurchasePrice, ticket.PurchasedOn, ticket.PurchaseReference, ticket.ID)
 	if err != nil {
-		return err
+		return mapError("ticket", err)
 	}
 
 	return nil
 }
 
 // Delete deletes an existing ticket.
-func (tr *TicketRepository) Delete(ticketID string) error {
+func (tr *TicketRepository) Delete(ctx context.Context, ticketID string) error {
 	db := tr.db
 
 	query, _, _ := psql.
@@ -183,34 +227,34 @@ func (tr *TicketRepository) Delete(ticketID string) error {
 		Where(sq.Eq{"id": "$1"}).
 		ToSql()
 
-	_, err := db.Exec(query, ticketID)
+	_, err := db.ExecContext(ctx, query, ticketID)
 	if err != nil {
-		return err
+		return mapError("ticket", err)
 	}
 
 	return nil
 }
 
 // StoreScan creates a new ticket scan.
-func (tr *TicketRepository) StoreScan(ticketScan *models.TicketScan) error {
+func (tr *TicketRepository) StoreScan(ctx context.Context, ticketScan *models.TicketScan) error {
 	db := tr.db
 
 	query, _, _ := psql.
 		Insert("tickets_on_rides").
-		Columns("id", "ride_id", "ticket_id", "scan_datetime").
-		Values("$1", "$2", "$3", "$4").
+		Columns("id", "ride_id", "ticket_id", "scan_datetime", "device_id").
+		Values("$1", "$2", "$3", "$4", "$5").
 		ToSql()
 
-	_, err := db.Exec(query, ticketScan.ID, ticketScan.RideID, ticketScan.TicketID, ticketScan.ScanOn)
+	_, err := db.ExecContext(ctx, query, ticketScan.ID, ticketScan.RideID, ticketScan.TicketID, ticketScan.ScanOn, ticketScan.DeviceID)
 	if err != nil {
-		return err
+		return mapError("ticket", err)
 	}
 
 	return nil
 }
 
 // UpdateScan updates an existing ticket scan.
-func (tr *TicketRepository) UpdateScan(ticketScan *models.TicketScan) error {
+func (tr *TicketRepository) UpdateScan(ctx context.Context, ticketScan *models.TicketScan) error {
 	db := tr.db
 
 	query, _, _ := psql.
@@ -221,16 +265,16 @@ func (tr *TicketRepository) UpdateScan(ticketScan *models.TicketScan) error {
 		Where(sq.Eq{"id": "$4"}).
 		ToSql()
 
-	_, err := db.Exec(query, ticketScan.RideID, ticketScan.TicketID, ticketScan.ScanOn, ticketScan.ID)
+	_, err := db.ExecContext(ctx, query, ticketScan.RideID, ticketScan.TicketID, ticketScan.ScanOn, ticketScan.ID)
 	if err != nil {
-		return err
+		return mapError("ticket", err)
 	}
 
 	return nil
 }
 
 // DeleteScan deletes an existing ticket scan.
-func (tr *TicketRepository) DeleteScan(ticketScanID string) error {
+func (tr *TicketRepository) DeleteScan(ctx context.Context, ticketScanID string) error {
 	db := tr.db
 
 	query, _, _ := psql.
@@ -238,9 +282,9 @@ func (tr *TicketRepository) DeleteScan(ticketScanID string) error {
 		Where(sq.Eq{"id": "$1"}).
 		ToSql()
 
-	_, err := db.Exec(query, ticketScanID)
+	_, err := db.ExecContext(ctx, query, ticketScanID)
 	if err != nil {
-		return err
+		return mapError("ticket", err)
 	}
 
 	return nil
@@ -1,6 +1,7 @@
 package postgres_test
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -13,6 +14,8 @@ import (
 
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
 func setupTestTickets(db *sqlx.DB) ([]string, []string, []string, []string) {
@@ -61,7 +64,7 @@ func TestTicketGetByIDSucceeds(t *testing.T) {
 
 	for _, ticketID := range tickets {
 
-		ticket, err := ticketRepository.GetByID(ticketID)
+		ticket, err := ticketRepository.GetByID(context.Background(), ticketID)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -86,7 +89,7 @@ func TestTicketFetchSucceeds(t *testing.T) {
 
 	_, ticketIDs, _, _ := setupTestTickets(db)
 
-	tickets, err := ticketRepository.Fetch()
+	tickets, _, err := ticketRepository.Fetch(context.Background(), &models.TicketFilter{}, nil)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -94,6 +97,54 @@ func TestTicketFetchSucceeds(t *testing.T) {
 	assert.Len(t, tickets, len(ticketIDs))
 }
 
+func TestTicketFetchPagesSucceeds(t *testing.T) {
+	ticketRepository, db, teardown := testutil.MakeTicketRepositoryFixture()
+	defer teardown()
+
+	_, ticketIDs, _, _ := setupTestTickets(db)
+
+	for _, sort := range []string{"", "purchasedOn", "-purchasePrice"} {
+		page := &models.PageRequest{Limit: 2, Sort: sort}
+		fetched := []string{}
+		for {
+			tickets, next, err := ticketRepository.Fetch(context.Background(), &models.TicketFilter{}, page)
+			if !assert.Nil(t, err) {
+				t.FailNow()
+			}
+
+			assert.LessOrEqual(t, len(tickets), page.Limit)
+			for _, ticket := range tickets {
+				fetched = append(fetched, ticket.ID)
+			}
+
+			if len(next) <= 0 {
+				break
+			}
+			page.Cursor = next
+		}
+
+		assert.ElementsMatch(t, ticketIDs, fetched, sort)
+	}
+}
+
+func TestTicketFetchInvalidPageFails(t *testing.T) {
+	ticketRepository, db, teardown := testutil.MakeTicketRepositoryFixture()
+	defer teardown()
+
+	setupTestTickets(db)
+
+	_, _, err := ticketRepository.Fetch(context.Background(), &models.TicketFilter{}, &models.PageRequest{Limit: 2, Sort: "user.id"})
+	assert.True(t, usecases.IsKind(err, usecases.KindValidation))
+
+	_, next, err := ticketRepository.Fetch(context.Background(), &models.TicketFilter{}, &models.PageRequest{Limit: 1})
+	if !assert.Nil(t, err) || !assert.NotEmpty(t, next) {
+		t.FailNow()
+	}
+
+	_, _, err = ticketRepository.Fetch(context.Background(), &models.TicketFilter{}, &models.PageRequest{Limit: 1, Sort: "purchasePrice", Cursor: next})
+	assert.True(t, usecases.IsKind(err, usecases.KindValidation))
+}
+
 func TestTicketFetchForUserSucceeds(t *testing.T) {
 	ticketRepository, db, teardown := testutil.MakeTicketRepositoryFixture()
 	defer teardown()
@@ -101,7 +152,7 @@ func TestTicketFetchForUserSucceeds(t *testing.T) {
 	userIDs, _, _, _ := setupTestTickets(db)
 
 	for idx, userID := range userIDs {
-		tickets, err := ticketRepository.FetchForUser(userID)
+		tickets, _, err := ticketRepository.Fetch(context.Background(), &models.TicketFilter{UserID: userID}, nil)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -116,7 +167,7 @@ func TestTicketFetchScansSucceeds(t *testing.T) {
 
 	_, _, _, scanIDs := setupTestTickets(db)
 
-	scans, err := ticketRepository.Fetch()
+	scans, _, err := ticketRepository.FetchScans(context.Background(), &models.TicketScanFilter{}, nil)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -131,7 +182,7 @@ func TestTicketFetchScansForRideSucceeds(t *testing.T) {
 	_, _, rideIDs, _ := setupTestTickets(db)
 
 	for idx, rideID := range rideIDs {
-		scans, err := ticketRepository.FetchScansForRide(rideID)
+		scans, _, err := ticketRepository.FetchScans(context.Background(), &models.TicketScanFilter{RideID: rideID}, nil)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -147,7 +198,7 @@ func TestTicketFetchScansForUserSucceeds(t *testing.T) {
 	userIDs, _, _, _ := setupTestTickets(db)
 
 	for idx, userID := range userIDs {
-		scans, err := ticketRepository.FetchScansForUser(userID)
+		scans, _, err := ticketRepository.FetchScans(context.Background(), &models.TicketScanFilter{UserID: userID}, nil)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -162,7 +213,6 @@ func TestTicketStoreSucceeds(t *testing.T) {
 
 	userIDs, _, _, _ := setupTestTickets(db)
 	userID := userIDs[0]
-	email := "customer0@email.com"
 
 	expectedTicket := &models.Ticket{
 		ID:                "some-ticket-id",
@@ -172,15 +222,15 @@ func TestTicketStoreSucceeds(t *testing.T) {
 		PurchasedOn:       time.Now().UTC(),
 		PurchaseReference: "some-purchase-reference-id",
 		IsValid:           true,
-		Email:             email,
+		User:              models.UserPublic{ID: userID},
 	}
 
-	err := ticketRepository.Store(expectedTicket)
+	err := ticketRepository.Store(context.Background(), expectedTicket)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	ticket, err := ticketRepository.GetByID(expectedTicket.ID)
+	ticket, err := ticketRepository.GetByID(context.Background(), expectedTicket.ID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -195,7 +245,7 @@ func TestTicketUpdateSucceeds(t *testing.T) {
 	_, ticketIDs, _, _ := setupTestTickets(db)
 	ticketID := ticketIDs[0]
 
-	expectedTicket, err := ticketRepository.GetByID(ticketID)
+	expectedTicket, err := ticketRepository.GetByID(context.Background(), ticketID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -205,12 +255,12 @@ func TestTicketUpdateSucceeds(t *testing.T) {
 	expectedTicket.PurchasedOn = time.Now().UTC()
 	expectedTicket.PurchaseReference = "some-purchase-reference-id"
 
-	err = ticketRepository.Update(expectedTicket)
+	err = ticketRepository.Update(context.Background(), expectedTicket)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	ticket, err := ticketRepository.GetByID(expectedTicket.ID)
+	ticket, err := ticketRepository.GetByID(context.Background(), expectedTicket.ID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -233,12 +283,12 @@ func TestTicketStoreScanSucceeds(t *testing.T) {
 		ScanOn:   time.Now().UTC(),
 	}
 
-	err := ticketRepository.StoreScan(expectedScan)
+	err := ticketRepository.StoreScan(context.Background(), expectedScan)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	scans, err := ticketRepository.FetchScansForRide(rideID)
+	scans, _, err := ticketRepository.FetchScans(context.Background(), &models.TicketScanFilter{RideID: rideID}, nil)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+var selectTwoFactors = psql.Select("two_factors.*").From("two_factors")
+
+var selectRecoveryCodes = psql.Select("recovery_codes.*").From("recovery_codes").OrderBy("recovery_codes.ID ASC")
+
+// TwoFactorRepository implements the TwoFactorRepository interface for
+// postgres.
+type TwoFactorRepository struct {
+	db *sqlx.DB
+}
+
+// NewTwoFactorRepository creates a new TwoFactorRepository instance using the
+// given database instance.
+func NewTwoFactorRepository(db *sqlx.DB) *TwoFactorRepository {
+	return &TwoFactorRepository{db}
+}
+
+// GetByUserID fetches the two-factor authentication of the given user.
+func (tr *TwoFactorRepository) GetByUserID(ctx context.Context, userID string) (*models.TwoFactor, error) {
+	db := tr.db
+	udb := db.Unsafe()
+
+	query, _ := selectTwoFactors.Where("two_factors.user_ID = ?").MustSql()
+
+	twoFactor := models.TwoFactor{}
+	err := udb.GetContext(ctx, &twoFactor, query, userID)
+	if err != nil {
+		return nil, mapError("two-factor authentication", err)
+	}
+
+	return &twoFactor, nil
+}
+
+// Store creates the two-factor authentication for an user, replacing any
+// existing one.
+func (tr *TwoFactorRepository) Store(ctx context.Context, twoFactor *models.TwoFactor) error {
+	db := tr.db
+
+	insertTwoFactor, _, _ := psql.
+		Insert("two_factors").
+		Columns("user_ID", "secret", "created_on", "enabled_on", "last_step").
+		Values("?", "?", "?", "?", "?").
+		Suffix("ON CONFLICT (user_ID) DO UPDATE SET secret = EXCLUDED.secret, created_on = EXCLUDED.created_on, enabled_on = EXCLUDED.enabled_on, last_step = EXCLUDED.last_step").
+		ToSql()
+
+	_, err := db.ExecContext(ctx, insertTwoFactor, twoFactor.UserID, twoFactor.Secret, twoFactor.CreatedOn, twoFactor.EnabledOn, twoFactor.LastStep)
+	if err != nil {
+		return fmt.Errorf("insertTwoFactor: %w", mapError("two-factor authentication", err))
+	}
+
+	return nil
+}
+
+// Enable marks the two-factor authentication of the given user as enabled.
+func (tr *TwoFactorRepository) Enable(ctx context.Context, userID string, enabledOn time.Time) error {
+	db := tr.db
+
+	enableTwoFactor, _, _ := psql.
+		Update("two_factors").
+		Set("enabled_on", "?").
+		Where("user_ID = ?").
+		ToSql()
+
+	result, err := db.ExecContext(ctx, enableTwoFactor, enabledOn, userID)
+	if err != nil {
+		return fmt.Errorf("enableTwoFactor: %w", mapError("two-factor authentication", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("enableTwoFactor: %w", mapError("two-factor authentication", err))
+	}
+
+	if affected != 1 {
+		return usecases.Errorf(usecases.KindNotFound, "enableTwoFactor: two-factor authentication does not exist")
+	}
+
+	return nil
+}
+
+// UpdateLastStep sets the last time step used by the given user. Steps only
+// move forward, so that a code can only be used once, any other caller using
+// the same or an older step gets an error.
+func (tr *TwoFactorRepository) UpdateLastStep(ctx context.Context, userID string, step int64) error {
+	db := tr.db
+
+	updateLastStep, _, _ := psql.
+		Update("two_factors").
+		Set("last_step", "?").
+		Where("user_ID = ?").
+		Where("last_step < ?").
+		ToSql()
+
+	result, err := db.ExecContext(ctx, updateLastStep, step, userID, step)
+	if err != nil {
+		return fmt.Errorf("updateLastStep: %w", mapError("two-factor authentication", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("updateLastStep: %w", mapError("two-factor authentication", err))
+	}
+
+	if affected != 1 {
+		return usecases.Errorf(usecases.KindNotFound, "updateLastStep: two-factor authentication does not exist or code was already used")
+	}
+
+	return nil
+}
+
+// Delete deletes the two-factor authentication and the recovery codes of the
+// given user.
+func (tr *TwoFactorRepository) Delete(ctx context.Context, userID string) error {
+	db := tr.db
+
+	deleteRecoveryCodes, _, _ := psql.
+		Delete("recovery_codes").
+		Where("user_ID = ?").
+		ToSql()
+
+	deleteTwoFactor, _, _ := psql.
+		Delete("two_factors").
+		Where("user_ID = ?").
+		ToSql()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return mapError("two-factor authentication", err)
+	}
+	defer tx.Rollback()
+
+	{
+		_, err = tx.ExecContext(ctx, deleteRecoveryCodes, userID)
+		if err != nil {
+			return fmt.Errorf("deleteRecoveryCodes: %w", mapError("two-factor authentication", err))
+		}
+
+		_, err = tx.ExecContext(ctx, deleteTwoFactor, userID)
+		if err != nil {
+			return fmt.Errorf("deleteTwoFactor: %w", mapError("two-factor authentication", err))
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return mapError("two-factor authentication", err)
+	}
+
+	return nil
+}
+
+// FetchRecoveryCodes fetches all recovery codes of the given user, including
+// the used ones.
+func (tr *TwoFactorRepository) FetchRecoveryCodes(ctx context.Context, userID string) ([]*models.RecoveryCode, error) {
+	db := tr.db
+	udb := db.Unsafe()
+
+	query, _ := selectRecoveryCodes.Where("recovery_codes.user_ID = ?").MustSql()
+
+	codes := []*models.RecoveryCode{}
+	err := udb.SelectContext(ctx, &codes, query, userID)
+	if err != nil {
+		return nil, mapError("two-factor authentication", err)
+	}
+
+	return codes, nil
+}
+
+// StoreRecoveryCodes replaces the recovery codes of the given user.
+func (tr *TwoFactorRepository) StoreRecoveryCodes(ctx context.Context, userID string, codes []*models.RecoveryCode) error {
+	db := tr.db
+
+	deleteRecoveryCodes, _, _ := psql.
+		Delete("recovery_codes").
+		Where("user_ID = ?").
+		ToSql()
+
+	insertRecoveryCode, _, _ := psql.
+		Insert("recovery_codes").
+		Columns("ID", "user_ID", "code_hash", "used_on").
+		Values("?", "?", "?", "?").
+		ToSql()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return mapError("two-factor authentication", err)
+	}
+	defer tx.Rollback()
+
+	{
+		_, err = tx.ExecContext(ctx, deleteRecoveryCodes, userID)
+		if err != nil {
+			return fmt.Errorf("deleteRecoveryCodes: %w", mapError("two-factor authentication", err))
+		}
+
+		for _, code := range codes {
+			_, err = tx.ExecContext(ctx, insertRecoveryCode, code.ID, userID, code.CodeHash, code.UsedOn)
+			if err != nil {
+				return fmt.Errorf("insertRecoveryCode: %w", mapError("two-factor authentication", err))
+			}
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return mapError("two-factor authentication", err)
+	}
+
+	return nil
+}
+
+// UseRecoveryCode marks the recovery code with the given hash as used. Only
+// one caller can use a code, any other gets an error.
+func (tr *TwoFactorRepository) UseRecoveryCode(ctx context.Context, userID, codeHash string, usedOn time.Time) error {
+	db := tr.db
+
+	useRecoveryCode, _, _ := psql.
+		Update("recovery_codes").
+		Set("used_on", "?").
+		Where("user_ID = ?").
+		Where("code_hash = ?").
+		Where("used_on IS NULL").
+		ToSql()
+
+	result, err := db.ExecContext(ctx, useRecoveryCode, usedOn, userID, codeHash)
+	if err != nil {
+		return fmt.Errorf("useRecoveryCode: %w", mapError("two-factor authentication", err))
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("useRecoveryCode: %w", mapError("two-factor authentication", err))
+	}
+
+	if affected != 1 {
+		return usecases.Errorf(usecases.KindNotFound, "useRecoveryCode: recovery code does not exist or was already used")
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package postgres_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// Fixtures
+// --------------------------------
+
+func setupTestTwoFactors(db *sqlx.DB) []string {
+
+	tx := db.MustBegin()
+	tx.MustExec("TRUNCATE TABLE users CASCADE")
+
+	// customer0 is pending, customer1 is enabled, customer2 has none
+	customer0 := generator.MustInsertCustomer(tx, "customer0", "customer0@email.com")
+	customer1 := generator.MustInsertCustomer(tx, "customer1", "customer1@email.com")
+	customer2 := generator.MustInsertCustomer(tx, "customer2", "customer2@email.com")
+
+	generator.MustInsertTwoFactor(tx, customer0, "secret0", false)
+	generator.MustInsertTwoFactor(tx, customer1, "secret1", true)
+	generator.MustInsertRecoveryCode(tx, customer1, "hash0")
+	generator.MustInsertRecoveryCode(tx, customer1, "hash1")
+
+	err := tx.Commit()
+	if err != nil {
+		panic(err)
+	}
+
+	return []string{customer0, customer1, customer2}
+}
+
+// Tests
+// --------------------------------
+
+func TestTwoFactorGetByUserIDSucceeds(t *testing.T) {
+	twoFactorRepository, db, teardown := testutil.MakeTwoFactorRepositoryFixture()
+	defer teardown()
+
+	userIDs := setupTestTwoFactors(db)
+
+	tests := []struct {
+		userID  string
+		secret  string
+		enabled bool
+	}{
+		{userIDs[0], "secret0", false},
+		{userIDs[1], "secret1", true},
+	}
+
+	for _, tt := range tests {
+		twoFactor, err := twoFactorRepository.GetByUserID(context.Background(), tt.userID)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, tt.userID, twoFactor.UserID)
+		assert.Equal(t, tt.secret, twoFactor.Secret)
+		assert.Equal(t, tt.enabled, twoFactor.IsEnabled())
+	}
+
+	_, err := twoFactorRepository.GetByUserID(context.Background(), userIDs[2])
+	assert.NotNil(t, err)
+}
+
+func TestTwoFactorStoreAndEnableSucceeds(t *testing.T) {
+	twoFactorRepository, db, teardown := testutil.MakeTwoFactorRepositoryFixture()
+	defer teardown()
+
+	userIDs := setupTestTwoFactors(db)
+
+	// replaces the pending one, and creates a new one
+	for _, userID := range []string{userIDs[0], userIDs[2]} {
+		twoFactor := &models.TwoFactor{
+			UserID:    userID,
+			Secret:    "new-secret",
+			CreatedOn: time.Now().UTC().Truncate(time.Second),
+		}
+
+		err := twoFactorRepository.Store(context.Background(), twoFactor)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		err = twoFactorRepository.Enable(context.Background(), userID, time.Now().UTC())
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		dbTwoFactor, err := twoFactorRepository.GetByUserID(context.Background(), userID)
+		if !assert.Nil(t, err) {
+			t.FailNow()
+		}
+
+		assert.Equal(t, "new-secret", dbTwoFactor.Secret)
+		assert.True(t, dbTwoFactor.IsEnabled())
+	}
+}
+
+func TestTwoFactorUpdateLastStepSucceeds(t *testing.T) {
+	twoFactorRepository, db, teardown := testutil.MakeTwoFactorRepositoryFixture()
+	defer teardown()
+
+	userIDs := setupTestTwoFactors(db)
+
+	err := twoFactorRepository.UpdateLastStep(context.Background(), userIDs[1], 10)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	// the same or an older step can't be used again
+	assert.NotNil(t, twoFactorRepository.UpdateLastStep(context.Background(), userIDs[1], 10))
+	assert.NotNil(t, twoFactorRepository.UpdateLastStep(context.Background(), userIDs[1], 9))
+	assert.Nil(t, twoFactorRepository.UpdateLastStep(context.Background(), userIDs[1], 11))
+}
+
+func TestTwoFactorRecoveryCodesSucceeds(t *testing.T) {
+	twoFactorRepository, db, teardown := testutil.MakeTwoFactorRepositoryFixture()
+	defer teardown()
+
+	userIDs := setupTestTwoFactors(db)
+
+	codes, err := twoFactorRepository.FetchRecoveryCodes(context.Background(), userIDs[1])
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Len(t, codes, 2)
+
+	// codes are single use
+	err = twoFactorRepository.UseRecoveryCode(context.Background(), userIDs[1], "hash0", time.Now().UTC())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.NotNil(t, twoFactorRepository.UseRecoveryCode(context.Background(), userIDs[1], "hash0", time.Now().UTC()))
+	assert.NotNil(t, twoFactorRepository.UseRecoveryCode(context.Background(), userIDs[0], "hash1", time.Now().UTC()))
+
+	// storing replaces all codes
+	err = twoFactorRepository.StoreRecoveryCodes(context.Background(), userIDs[1], []*models.RecoveryCode{
+		{ID: "code0", CodeHash: "hash2"},
+	})
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	codes, err = twoFactorRepository.FetchRecoveryCodes(context.Background(), userIDs[1])
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	if assert.Len(t, codes, 1) {
+		assert.Equal(t, "code0", codes[0].ID)
+		assert.Equal(t, userIDs[1], codes[0].UserID)
+		assert.False(t, codes[0].UsedOn.Valid)
+	}
+}
+
+func TestTwoFactorDeleteSucceeds(t *testing.T) {
+	twoFactorRepository, db, teardown := testutil.MakeTwoFactorRepositoryFixture()
+	defer teardown()
+
+	userIDs := setupTestTwoFactors(db)
+
+	err := twoFactorRepository.Delete(context.Background(), userIDs[1])
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	_, err = twoFactorRepository.GetByUserID(context.Background(), userIDs[1])
+	assert.NotNil(t, err)
+
+	codes, err := twoFactorRepository.FetchRecoveryCodes(context.Background(), userIDs[1])
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Len(t, codes, 0)
+}
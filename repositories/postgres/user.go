@@ -1,9 +1,12 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
+	sq "github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
 
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
@@ -18,12 +21,29 @@ var selectUsers = psql.
 		"(employees.ID IS NOT NULL) AS is_employee",
 		"roles.role",
 		"COALESCE(roles.hourly_rate, 0.0) as hourly_rate",
+		"(two_factors.enabled_on IS NOT NULL) AS two_factor_enabled",
+		"COALESCE(roles.requires_two_factor, false) AS requires_two_factor",
 	).
 	From("users").
 	LeftJoin("user_details ON user_details.user_ID = users.ID").
 	LeftJoin("genders ON genders.ID = user_details.gender_ID").
 	LeftJoin("employees ON employees.user_ID = users.ID").
-	LeftJoin("roles ON roles.ID = employees.role_ID")
+	LeftJoin("roles ON roles.ID = employees.role_ID").
+	LeftJoin("two_factors ON two_factors.user_ID = users.ID")
+
+var userSorting = &sorting{
+	fields: map[string]sortField{
+		"registeredOn": {"users.registered_on", "timestamp", func(row interface{}) string {
+			return timeValue(row.(*models.User).RegisteredOn)
+		}},
+		"email": {"users.email", "text", func(row interface{}) string {
+			return row.(*models.User).Email
+		}},
+	},
+	defaultSort: "-registeredOn",
+	id:          "users.id",
+	idOf:        func(row interface{}) string { return row.(*models.User).ID },
+}
 
 // UserRepository implements the UserRepository interface for postgres.
 type UserRepository struct {
@@ -37,107 +57,98 @@ func NewUserRepository(db *sqlx.DB) *UserRepository {
 }
 
 // GetByID fetches an user from the postgres `users` and `user_details` tables.
-func (ur *UserRepository) GetByID(ID string) (*models.User, error) {
+func (ur *UserRepository) GetByID(ctx context.Context, ID string) (*models.User, error) {
 	db := ur.db
 	udb := db.Unsafe()
 
 	query, _ := selectUsers.Where("users.ID = $1").Limit(1).MustSql()
 
 	user := models.User{}
-	err := udb.Get(&user, query, ID)
+	err := udb.GetContext(ctx, &user, query, ID)
 	if err != nil {
-		return nil, err
+		return nil, mapError("user", err)
 	}
 
 	return &user, err
 }
 
 // GetByEmail is similar to GetByID but uses the email for finding the user.
-func (ur *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	db := ur.db
 	udb := db.Unsafe()
 
 	query, _ := selectUsers.Where("users.email = $1").Limit(1).MustSql()
 
 	user := models.User{}
-	err := udb.Get(&user, query, email)
+	err := udb.GetContext(ctx, &user, query, email)
 	if err != nil {
-		return nil, err
+		return nil, mapError("user", err)
 	}
 
 	return &user, err
 }
 
 // GetByUsername is similar to GetByID but uses the username for finding the user.
-func (ur *UserRepository) GetByUsername(username string) (*models.User, error) {
+func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
 	db := ur.db
 	udb := db.Unsafe()
 
 	query, _ := selectUsers.Where("users.username = $1").Limit(1).MustSql()
 
 	user := models.User{}
-	err := udb.Get(&user, query, username)
+	err := udb.GetContext(ctx, &user, query, username)
 	if err != nil {
-		return nil, err
+		return nil, mapError("user", err)
 	}
 
 	return &user, err
 }
 
-// Fetch fetches all users from the postgres `users` and `user_details` tables.
-func (ur *UserRepository) Fetch() ([]*models.User, error) {
+// Fetch fetches a page of the users matching the given filter.
+func (ur *UserRepository) Fetch(ctx context.Context, filter *models.UserFilter, page *models.PageRequest) ([]*models.User, string, error) {
 	db := ur.db
 	udb := db.Unsafe()
 
-	query, _ := selectUsers.MustSql()
+	builder := selectUsers
+	if filter.IsEmployee.Valid && filter.IsEmployee.Bool {
+		builder = builder.Where(sq.NotEq{"employees.ID": nil})
+	}
 
-	users := []*models.User{}
-	err := udb.Select(&users, query)
-	if err != nil {
-		return nil, err
+	if filter.IsEmployee.Valid && !filter.IsEmployee.Bool {
+		builder = builder.Where(sq.Eq{"employees.ID": nil})
 	}
 
-	return users, err
-}
+	if len(filter.Role) > 0 {
+		builder = builder.Where(sq.Eq{"roles.role": filter.Role})
+	}
 
-// FetchCustomers is like Fetch, but fetches only the customers.
-func (ur *UserRepository) FetchCustomers() ([]*models.User, error) {
-	users, err := ur.Fetch()
+	builder, err := userSorting.paginate(builder, page)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	customers := make([]*models.User, 0, len(users))
-	for _, user := range users {
-		if user.IsEmployee {
-			continue
-		}
-		customers = append(customers, user)
+	query, args, err := builder.ToSql()
+	if err != nil {
+		return nil, "", fmt.Errorf("selectUsers: %w", mapError("user", err))
 	}
 
-	return customers, nil
-}
-
-// FetchEmployees is like Fetch, but fetches only the employees.
-func (ur *UserRepository) FetchEmployees() ([]*models.User, error) {
-	users, err := ur.Fetch()
+	users := []*models.User{}
+	err = udb.SelectContext(ctx, &users, query, args...)
 	if err != nil {
-		return nil, err
+		return nil, "", mapError("user", err)
 	}
 
-	employees := make([]*models.User, 0, len(users))
-	for _, user := range users {
-		if !user.IsEmployee {
-			continue
-		}
-		employees = append(employees, user)
+	count, more := userSorting.next(page, len(users))
+	users = users[:count]
+	if !more {
+		return users, "", nil
 	}
 
-	return employees, nil
+	return users, userSorting.cursor(page, users[count-1]), nil
 }
 
 // Store creates a new user in the database.
-func (ur *UserRepository) Store(user *models.User) error {
+func (ur *UserRepository) Store(ctx context.Context, user *models.User) error {
 
 	db := ur.db
 
@@ -178,56 +189,56 @@ func (ur *UserRepository) Store(user *models.User) error {
 		ToSql()
 
 	var genderID sql.NullString
-	_ = db.Get(&genderID, selectGenderID, user.Gender.String)
+	_ = db.GetContext(ctx, &genderID, selectGenderID, user.Gender.String)
 
 	// begin the transaction
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return mapError("user", err)
 	}
 
 	// ANONYMOUS BLOCK FOR TRANSACTION
 	{
-		_, err = tx.Exec(insertUser, user.ID, user.Email, user.Email, user.PasswordSalt, user.PasswordHash, user.RegisteredOn)
+		_, err = tx.ExecContext(ctx, insertUser, user.ID, user.Email, user.Email, user.PasswordSalt, user.PasswordHash, user.RegisteredOn)
 		if err != nil {
-			return fmt.Errorf("insertUser: %s", err)
+			return fmt.Errorf("insertUser: %w", mapError("user", err))
 		}
 
-		_, err = tx.Exec(insertDetail, user.ID, genderID, user.FirstName, user.LastName, user.DateOfBirth, user.Phone, user.Address)
+		_, err = tx.ExecContext(ctx, insertDetail, user.ID, genderID, user.FirstName, user.LastName, user.DateOfBirth, user.Phone, user.Address)
 		if err != nil {
-			return fmt.Errorf("insertDetails: %s", err)
+			return fmt.Errorf("insertDetails: %w", mapError("user", err))
 		}
 
 		if user.IsEmployee {
 			var roleID string
-			err = db.Get(&roleID, selectRoleID, user.Role.String)
+			err = db.GetContext(ctx, &roleID, selectRoleID, user.Role.String)
 			if err != nil {
-				return fmt.Errorf("selectRoleID: %s", err)
+				return fmt.Errorf("selectRoleID: %w", mapError("user", err))
 			}
 
-			_, err = tx.Exec(insertEmployee, user.ID, user.ID, roleID)
+			_, err = tx.ExecContext(ctx, insertEmployee, user.ID, user.ID, roleID)
 			if err != nil {
-				return fmt.Errorf("insertEmployee: %s", err)
+				return fmt.Errorf("insertEmployee: %w", mapError("user", err))
 			}
 		}
 
-		_, err := tx.Exec(insertCustomer, user.ID)
+		_, err := tx.ExecContext(ctx, insertCustomer, user.ID)
 		if err != nil {
-			return fmt.Errorf("insertCustomer: %s", err)
+			return fmt.Errorf("insertCustomer: %w", mapError("user", err))
 		}
 	}
 
 	// commit the transaction
 	err = tx.Commit()
 	if err != nil {
-		return err
+		return mapError("user", err)
 	}
 
 	return nil
 }
 
 // Update updates an existing user in the database.
-func (ur *UserRepository) Update(user *models.User) error {
+func (ur *UserRepository) Update(ctx context.Context, user *models.User) error {
 
 	updateUser, _, _ := psql.Update("users").
 		Set("email", "?").
@@ -262,35 +273,35 @@ func (ur *UserRepository) Update(user *models.User) error {
 		MustSql()
 
 	var genderID sql.NullString
-	_ = ur.db.Get(&genderID, selectGenderID, user.Gender.String)
+	_ = ur.db.GetContext(ctx, &genderID, selectGenderID, user.Gender.String)
 
 	//Start transaction
-	tx, err := ur.db.Begin()
+	tx, err := ur.db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return mapError("user", err)
 	}
 
 	{
-		_, err = tx.Exec(updateUser, user.Email, user.ID)
+		_, err = tx.ExecContext(ctx, updateUser, user.Email, user.ID)
 		if err != nil {
-			return fmt.Errorf("updateUser: %s", err)
+			return fmt.Errorf("updateUser: %w", mapError("user", err))
 		}
 
-		_, err = tx.Exec(updateDetails, genderID, user.FirstName, user.LastName, user.DateOfBirth, user.Phone, user.Address, user.ID)
+		_, err = tx.ExecContext(ctx, updateDetails, genderID, user.FirstName, user.LastName, user.DateOfBirth, user.Phone, user.Address, user.ID)
 		if err != nil {
-			return fmt.Errorf("updateDetails: %s", err)
+			return fmt.Errorf("updateDetails: %w", mapError("user", err))
 		}
 
 		if user.IsEmployee {
 			var roleID string
-			err = ur.db.Get(&roleID, selectRoleID, user.Role.String)
+			err = ur.db.GetContext(ctx, &roleID, selectRoleID, user.Role.String)
 			if err != nil {
-				return fmt.Errorf("selectRoleID: %s", err)
+				return fmt.Errorf("selectRoleID: %w", mapError("user", err))
 			}
 
-			_, err = tx.Exec(updateEmployee, roleID, user.ID)
+			_, err = tx.ExecContext(ctx, updateEmployee, roleID, user.ID)
 			if err != nil {
-				return fmt.Errorf("updateRole: %s", err)
+				return fmt.Errorf("updateRole: %w", mapError("user", err))
 			}
 		}
 
@@ -298,14 +309,14 @@ func (ur *UserRepository) Update(user *models.User) error {
 
 	err = tx.Commit()
 	if err != nil {
-		return err
+		return mapError("user", err)
 	}
 
 	return nil
 }
 
 // Delete deletes an existing user from the database.
-func (ur *UserRepository) Delete(ID string) error {
+func (ur *UserRepository) Delete(ctx context.Context, ID string) error {
 
 	// TODO account for deleting employees from maintanence
 	db := ur.db
@@ -330,51 +341,122 @@ func (ur *UserRepository) Delete(ID string) error {
 		Where("user_id = ?").
 		ToSql()
 
-	user, err := ur.GetByID(ID)
+	deleteSessions, _, _ := psql.
+		Delete("sessions").
+		Where("user_id = ?").
+		ToSql()
+
+	deletePasswordResets, _, _ := psql.
+		Delete("password_resets").
+		Where("user_id = ?").
+		ToSql()
+
+	deleteTwoFactors, _, _ := psql.
+		Delete("two_factors").
+		Where("user_id = ?").
+		ToSql()
+
+	deleteRecoveryCodes, _, _ := psql.
+		Delete("recovery_codes").
+		Where("user_id = ?").
+		ToSql()
+
+	user, err := ur.GetByID(ctx, ID)
 	if err != nil {
-		return fmt.Errorf("user does not exist to be deleted: %s", err)
+		return fmt.Errorf("user does not exist to be deleted: %w", mapError("user", err))
 	}
 
-	tx, err := db.Begin()
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return mapError("user", err)
 	}
 
 	{
 
-		_, err = tx.Exec(deleteDetails, ID)
+		_, err = tx.ExecContext(ctx, deleteDetails, ID)
 		if err != nil {
-			return fmt.Errorf("deleteDetails: %s", err)
+			return fmt.Errorf("deleteDetails: %w", mapError("user", err))
 		}
 		//this is where removing from employee maintanence would happen
 		if user.IsEmployee {
-			_, err = tx.Exec(deleteEmployee, ID)
+			_, err = tx.ExecContext(ctx, deleteEmployee, ID)
 			if err != nil {
-				return fmt.Errorf("deleteEmployee: %s", err)
+				return fmt.Errorf("deleteEmployee: %w", mapError("user", err))
 			}
 		} else {
-			_, err = tx.Exec(deleteCustomer, ID)
+			_, err = tx.ExecContext(ctx, deleteCustomer, ID)
 			if err != nil {
-				return fmt.Errorf("deleteCustomer: %s", err)
+				return fmt.Errorf("deleteCustomer: %w", mapError("user", err))
 			}
 		}
 
-		_, err = tx.Exec(deleteUser, ID)
+		_, err = tx.ExecContext(ctx, deleteSessions, ID)
 		if err != nil {
-			return fmt.Errorf("deleteUser: %s", err)
+			return fmt.Errorf("deleteSessions: %w", mapError("user", err))
+		}
+
+		_, err = tx.ExecContext(ctx, deletePasswordResets, ID)
+		if err != nil {
+			return fmt.Errorf("deletePasswordResets: %w", mapError("user", err))
+		}
+
+		_, err = tx.ExecContext(ctx, deleteTwoFactors, ID)
+		if err != nil {
+			return fmt.Errorf("deleteTwoFactors: %w", mapError("user", err))
+		}
+
+		_, err = tx.ExecContext(ctx, deleteRecoveryCodes, ID)
+		if err != nil {
+			return fmt.Errorf("deleteRecoveryCodes: %w", mapError("user", err))
+		}
+
+		_, err = tx.ExecContext(ctx, deleteUser, ID)
+		if err != nil {
+			return fmt.Errorf("deleteUser: %w", mapError("user", err))
 		}
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return err
+		return mapError("user", err)
 	}
 
 	return nil
 }
 
 // UpdatePassword updates the password salt and hash for the given user ID.
-func (ur *UserRepository) UpdatePassword(ID, passwordSalt, passwordHash string) error {
+func (ur *UserRepository) UpdatePassword(ctx context.Context, ID, passwordSalt, passwordHash string) error {
+	db := ur.db
+
+	updatePass, _, _ := psql.Update("users").
+		Set("password_salt", "?").
+		Set("password_hash", "?").
+		Where("id = ?").
+		ToSql()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return mapError("user", err)
+	}
+
+	{
+		_, err = tx.ExecContext(ctx, updatePass, passwordSalt, passwordHash, ID)
+		if err != nil {
+			return fmt.Errorf("updatePass: %w", mapError("user", err))
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return mapError("user", err)
+	}
+
+	return nil
+}
+
+// ChangePassword updates the password of the user with the given ID and
+// revokes all of their sessions, in one transaction.
+func (ur *UserRepository) ChangePassword(ctx context.Context, ID, passwordSalt, passwordHash string, revokedOn time.Time) error {
 	db := ur.db
 
 	updatePass, _, _ := psql.Update("users").
@@ -383,37 +465,50 @@ func (ur *UserRepository) UpdatePassword(ID, passwordSalt, passwordHash string)
 		Where("id = ?").
 		ToSql()
 
-	tx, err := db.Begin()
+	revokeSessions, _, _ := psql.
+		Update("sessions").
+		Set("revoked_on", "?").
+		Where("user_ID = ?").
+		Where("revoked_on IS NULL").
+		ToSql()
+
+	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
-		return err
+		return mapError("user", err)
 	}
+	defer tx.Rollback()
 
 	{
-		_, err = tx.Exec(updatePass, passwordSalt, passwordHash, ID)
+		_, err = tx.ExecContext(ctx, updatePass, passwordSalt, passwordHash, ID)
+		if err != nil {
+			return fmt.Errorf("updatePass: %w", mapError("user", err))
+		}
+
+		_, err = tx.ExecContext(ctx, revokeSessions, revokedOn, ID)
 		if err != nil {
-			return fmt.Errorf("updatePass: %s", err)
+			return fmt.Errorf("revokeSessions: %w", mapError("session", err))
 		}
 	}
 
 	err = tx.Commit()
 	if err != nil {
-		return err
+		return mapError("user", err)
 	}
 
 	return nil
 }
 
 // AvailableGenders returns are the valid values for gender.
-func (ur *UserRepository) AvailableGenders() ([]string, error) {
+func (ur *UserRepository) AvailableGenders(ctx context.Context) ([]string, error) {
 	db := ur.db
 	udb := db.Unsafe()
 
 	query, _ := psql.Select("DISTINCT gender").From("genders").OrderBy("gender ASC").MustSql()
 
 	rows := []string{}
-	err := udb.Select(&rows, query)
+	err := udb.SelectContext(ctx, &rows, query)
 	if err != nil {
-		return nil, err
+		return nil, mapError("user", err)
 	}
 
 	return rows, err
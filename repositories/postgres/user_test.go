@@ -1,8 +1,10 @@
 package postgres_test
 
 import (
+	"context"
 	"database/sql"
 	"testing"
+	"time"
 
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/generator"
 
@@ -58,7 +60,7 @@ func TestUserGetByIDSucceeds(t *testing.T) {
 	customerIDs, employeeIDs := setupTestUsers(db)
 
 	for _, customerID := range customerIDs {
-		customer, err := userRepository.GetByID(customerID)
+		customer, err := userRepository.GetByID(context.Background(), customerID)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -67,7 +69,7 @@ func TestUserGetByIDSucceeds(t *testing.T) {
 	}
 
 	for _, employeeID := range employeeIDs {
-		employee, err := userRepository.GetByID(employeeID)
+		employee, err := userRepository.GetByID(context.Background(), employeeID)
 		if !assert.Nil(t, err) {
 			t.FailNow()
 		}
@@ -80,7 +82,7 @@ func TestUserGetByIDNoMatchFails(t *testing.T) {
 	userRepository, _, teardown := testutil.MakeUserRepositoryFixture()
 	defer teardown()
 
-	user, err := userRepository.GetByID("some-unknown-ID")
+	user, err := userRepository.GetByID(context.Background(), "some-unknown-ID")
 	assert.Nil(t, user)
 	assert.NotNil(t, err)
 }
@@ -91,7 +93,7 @@ func TestUserFetchSucceeds(t *testing.T) {
 
 	setupTestUsers(db)
 
-	users, err := userRepository.Fetch()
+	users, _, err := userRepository.Fetch(context.Background(), &models.UserFilter{}, nil)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -105,7 +107,7 @@ func TestUserFetchCustomersSucceeds(t *testing.T) {
 
 	setupTestUsers(db)
 
-	customers, err := userRepository.FetchCustomers()
+	customers, _, err := userRepository.Fetch(context.Background(), &models.UserFilter{IsEmployee: sql.NullBool{Bool: false, Valid: true}}, nil)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -119,7 +121,7 @@ func TestUserFetchEmployeesSucceeds(t *testing.T) {
 
 	setupTestUsers(db)
 
-	employees, err := userRepository.FetchEmployees()
+	employees, _, err := userRepository.Fetch(context.Background(), &models.UserFilter{IsEmployee: sql.NullBool{Bool: true, Valid: true}}, nil)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -134,12 +136,12 @@ func TestUserStoreCustomerSucceeds(t *testing.T) {
 	setupTestUsers(db)
 
 	customer := models.NewCustomer("customer--A", "customer--A--email", "customer--A--password_salt", "customer--A--password_hash")
-	err := userRepository.Store(customer)
+	err := userRepository.Store(context.Background(), customer)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	user, err := userRepository.GetByID(customer.ID)
+	user, err := userRepository.GetByID(context.Background(), customer.ID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -156,12 +158,12 @@ func TestUserStoreEmployeeSucceeds(t *testing.T) {
 
 	role := "Worker"
 	employee := models.NewEmployee("customer--A", "customer--A--email", "customer--A--password_salt", "customer--A--password_hash", role, 0)
-	err := userRepository.Store(employee)
+	err := userRepository.Store(context.Background(), employee)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	user, err := userRepository.GetByID(employee.ID)
+	user, err := userRepository.GetByID(context.Background(), employee.ID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -179,7 +181,7 @@ func TestUserUpdateCustomerSucceeds(t *testing.T) {
 	customerIDs, _ := setupTestUsers(db)
 	userID := customerIDs[0]
 
-	user, err := userRepository.GetByID(userID)
+	user, err := userRepository.GetByID(context.Background(), userID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -193,12 +195,12 @@ func TestUserUpdateCustomerSucceeds(t *testing.T) {
 	expectedUser.Phone = models.FromSQLNullString(sql.NullString{String: "expected--phone", Valid: true})
 	expectedUser.Address = models.FromSQLNullString(sql.NullString{String: "expected--address", Valid: true})
 
-	err = userRepository.Update(expectedUser)
+	err = userRepository.Update(context.Background(), expectedUser)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	updatedUser, err := userRepository.GetByID(user.ID)
+	updatedUser, err := userRepository.GetByID(context.Background(), user.ID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -214,12 +216,12 @@ func TestDeleteUserSucceeds(t *testing.T) {
 	userID := customerIDs[0]
 
 	// delete a user, check if he isn't there
-	err := userRepository.Delete(userID)
+	err := userRepository.Delete(context.Background(), userID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	user, err := userRepository.GetByID(userID)
+	user, err := userRepository.GetByID(context.Background(), userID)
 	assert.Nil(t, user)
 	assert.NotNil(t, err)
 }
@@ -231,12 +233,12 @@ func TestUserUpdatePasswordSucceeds(t *testing.T) {
 	customerIDs, _ := setupTestUsers(db)
 	userID := customerIDs[0]
 
-	err := userRepository.UpdatePassword(userID, "abc", "new password")
+	err := userRepository.UpdatePassword(context.Background(), userID, "abc", "new password")
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
 
-	updatedUser, err := userRepository.GetByID(userID)
+	updatedUser, err := userRepository.GetByID(context.Background(), userID)
 	if !assert.Nil(t, err) {
 		t.FailNow()
 	}
@@ -246,13 +248,38 @@ func TestUserUpdatePasswordSucceeds(t *testing.T) {
 
 }
 
+func TestUserChangePasswordRevokesSessions(t *testing.T) {
+	userRepository, db, teardown := testutil.MakeUserRepositoryFixture()
+	defer teardown()
+
+	customerIDs, _ := setupTestUsers(db)
+	userID := customerIDs[0]
+	sessionID := generator.MustInsertSession(db, userID, time.Now().UTC(), time.Hour)
+
+	err := userRepository.ChangePassword(context.Background(), userID, "", "new password", time.Now().UTC())
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	updatedUser, err := userRepository.GetByID(context.Background(), userID)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+
+	assert.Equal(t, "new password", updatedUser.PasswordHash)
+
+	var revoked bool
+	db.Get(&revoked, "SELECT revoked_on IS NOT NULL FROM sessions WHERE ID = $1", sessionID)
+	assert.True(t, revoked)
+}
+
 func TestUserGetAllGendersSucceeds(t *testing.T) {
 	userRepository, db, teardown := testutil.MakeUserRepositoryFixture()
 	defer teardown()
 
 	setupTestUsers(db)
 
-	genders, err := userRepository.AvailableGenders()
+	genders, err := userRepository.AvailableGenders(context.Background())
 
 	if !assert.Nil(t, err) {
 		t.FailNow()
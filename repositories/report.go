@@ -0,0 +1,14 @@
+package repositories
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/reports"
+)
+
+// ReportRepository defines the interface for running reports.
+type ReportRepository interface {
+	// Run runs the given report and returns its rows, as returned by
+	// report.NewRows.
+	Run(ctx context.Context, report *reports.Report, dateRange reports.Range) (interface{}, error)
+}
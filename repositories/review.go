@@ -1,18 +1,20 @@
 package repositories
 
 import (
+	"context"
+
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 )
 
 // ReviewRepository defines the interface for working with reviews.
 type ReviewRepository interface {
-	GetByID(ID string) (*models.Review, error)
+	GetByID(ctx context.Context, ID string) (*models.Review, error)
 
-	Fetch() ([]*models.Review, error)
-	FetchForRideSortedByRating(rideID string) ([]*models.Review, error)
-	FetchForRideSortedByDate(rideID string) ([]*models.Review, error)
+	Fetch(ctx context.Context) ([]*models.Review, error)
+	FetchForRideSortedByRating(ctx context.Context, rideID string) ([]*models.Review, error)
+	FetchForRideSortedByDate(ctx context.Context, rideID string) ([]*models.Review, error)
 
-	Store(*models.Review) error
-	Update(*models.Review) error
-	Delete(ID string) error
+	Store(ctx context.Context, review *models.Review) error
+	Update(ctx context.Context, review *models.Review) error
+	Delete(ctx context.Context, ID string) error
 }
@@ -1,14 +1,16 @@
 package repositories
 
 import (
+	"context"
+
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 )
 
 // RideRepository defines the interface for working with rides.
 type RideRepository interface {
-	GetByID(ID string) (*models.Ride, error)
-	Fetch() ([]*models.Ride, error)
-	Store(*models.Ride) error
-	Update(*models.Ride) error
-	Delete(ID string) error
+	GetByID(ctx context.Context, ID string) (*models.Ride, error)
+	Fetch(ctx context.Context) ([]*models.Ride, error)
+	Store(ctx context.Context, ride *models.Ride) error
+	Update(ctx context.Context, ride *models.Ride) error
+	Delete(ctx context.Context, ID string) error
 }
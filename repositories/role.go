@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// RoleRepository defines the interface for working with employee roles.
+type RoleRepository interface {
+	GetByID(ctx context.Context, ID string) (*models.Role, error)
+	Fetch(ctx context.Context) ([]*models.Role, error)
+
+	UpdateRequiresTwoFactor(ctx context.Context, ID string, requiresTwoFactor bool) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// SessionRepository defines the interface for working with log in sessions.
+type SessionRepository interface {
+	GetByID(ctx context.Context, ID string) (*models.Session, error)
+	FetchActiveForUser(ctx context.Context, userID string, now time.Time) ([]*models.Session, error)
+
+	Store(ctx context.Context, session *models.Session) error
+	Revoke(ctx context.Context, ID string, revokedOn time.Time) error
+	RevokeForUser(ctx context.Context, userID string, revokedOn time.Time) error
+}
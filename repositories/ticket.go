@@ -1,26 +1,25 @@
 package repositories
 
 import (
+	"context"
+
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 )
 
 // TicketRepository defines the interface for interacting with tickets and
-// ticket scans.
+// ticket scans. Lists are fetched a page at a time (every row if the page is
+// nil), along with the cursor of the next page, empty on the last one.
 type TicketRepository interface {
-	GetByID(ID string) (*models.Ticket, error)
-
-	Fetch() ([]*models.Ticket, error)
-	FetchForUser(userID string) ([]*models.Ticket, error)
+	GetByID(ctx context.Context, ID string) (*models.Ticket, error)
 
-	FetchScans() ([]*models.TicketScan, error)
-	FetchScansForRide(rideID string) ([]*models.TicketScan, error)
-	FetchScansForUser(rideID string) ([]*models.TicketScan, error)
+	Fetch(ctx context.Context, filter *models.TicketFilter, page *models.PageRequest) ([]*models.Ticket, string, error)
+	FetchScans(ctx context.Context, filter *models.TicketScanFilter, page *models.PageRequest) ([]*models.TicketScan, string, error)
 
-	Store(ticket *models.Ticket) error
-	Update(ticket *models.Ticket) error
-	Delete(ticketID string) error
+	Store(ctx context.Context, ticket *models.Ticket) error
+	Update(ctx context.Context, ticket *models.Ticket) error
+	Delete(ctx context.Context, ticketID string) error
 
-	StoreScan(ticketScan *models.TicketScan) error
-	UpdateScan(ticketScan *models.TicketScan) error
-	DeleteScan(ticketScanID string) error
+	StoreScan(ctx context.Context, ticketScan *models.TicketScan) error
+	UpdateScan(ctx context.Context, ticketScan *models.TicketScan) error
+	DeleteScan(ctx context.Context, ticketScanID string) error
 }
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"context"
+
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// TwoFactorRepository defines the interface for working with two-factor
+// authentication and recovery codes.
+type TwoFactorRepository interface {
+	GetByUserID(ctx context.Context, userID string) (*models.TwoFactor, error)
+
+	Store(ctx context.Context, twoFactor *models.TwoFactor) error
+	Enable(ctx context.Context, userID string, enabledOn time.Time) error
+	UpdateLastStep(ctx context.Context, userID string, step int64) error
+	Delete(ctx context.Context, userID string) error
+
+	FetchRecoveryCodes(ctx context.Context, userID string) ([]*models.RecoveryCode, error)
+	StoreRecoveryCodes(ctx context.Context, userID string, codes []*models.RecoveryCode) error
+	UseRecoveryCode(ctx context.Context, userID, codeHash string, usedOn time.Time) error
+}
@@ -1,23 +1,27 @@
 package repositories
 
 import (
+	"context"
+	"time"
+
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 )
 
-// UserRepository defines the interface for working with users.
+// UserRepository defines the interface for working with users. Users are
+// fetched a page at a time (every user if the page is nil), along with the
+// cursor of the next page, empty on the last one.
 type UserRepository interface {
-	GetByID(ID string) (*models.User, error)
-	GetByEmail(email string) (*models.User, error)
-	GetByUsername(username string) (*models.User, error)
+	GetByID(ctx context.Context, ID string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
 
-	Fetch() ([]*models.User, error)
-	FetchCustomers() ([]*models.User, error)
-	FetchEmployees() ([]*models.User, error)
+	Fetch(ctx context.Context, filter *models.UserFilter, page *models.PageRequest) ([]*models.User, string, error)
 
-	Store(*models.User) error
-	Update(*models.User) error
-	Delete(ID string) error
+	Store(ctx context.Context, user *models.User) error
+	Update(ctx context.Context, user *models.User) error
+	Delete(ctx context.Context, ID string) error
 
-	UpdatePassword(ID, passwordSalt, passwordHash string) error
-	AvailableGenders() ([]string, error)
+	UpdatePassword(ctx context.Context, ID, passwordSalt, passwordHash string) error
+	ChangePassword(ctx context.Context, ID, passwordSalt, passwordHash string, revokedOn time.Time) error
+	AvailableGenders(ctx context.Context) ([]string, error)
 }
@@ -0,0 +1,80 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// shutdownHook is a named function called when the server shuts down.
+type shutdownHook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// lifecycle keeps track of what has to be stopped when the server shuts down,
+// like the HTTP server, background workers and the database pool.
+type lifecycle struct {
+	mu    sync.Mutex
+	hooks []shutdownHook
+}
+
+// newLifecycle returns a new lifecycle instance.
+func newLifecycle() *lifecycle {
+	return &lifecycle{}
+}
+
+// OnShutdown registers a function to be called on shutdown. Functions are
+// called in reverse order of registration, so that things are stopped before
+// what they depend on (e.g. the HTTP server before the database pool).
+func (l *lifecycle) OnShutdown(name string, fn func(ctx context.Context) error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, shutdownHook{name, fn})
+}
+
+// Go runs the given background worker in a new goroutine. The context given
+// to the worker is canceled on shutdown, and shutdown waits for the worker to
+// return (or for the shutdown deadline).
+func (l *lifecycle) Go(name string, worker func(ctx context.Context)) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		worker(ctx)
+	}()
+
+	l.OnShutdown(name, func(shutdownCtx context.Context) error {
+		cancel()
+
+		select {
+		case <-done:
+			return nil
+		case <-shutdownCtx.Done():
+			return shutdownCtx.Err()
+		}
+	})
+}
+
+// Shutdown calls every registered function, even if some fail, and returns
+// the first error. The given context holds the shutdown deadline.
+func (l *lifecycle) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := l.hooks
+	l.hooks = nil
+	l.mu.Unlock()
+
+	var firstErr error
+	for idx := len(hooks) - 1; idx >= 0; idx-- {
+		hook := hooks[idx]
+
+		err := hook.fn(ctx)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("shutdown %s: %s", hook.name, err)
+		}
+	}
+
+	return firstErr
+}
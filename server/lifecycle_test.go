@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLifecycleShutdownSucceeds(t *testing.T) {
+	lc := newLifecycle()
+
+	called := []string{}
+	for _, name := range []string{"database", "worker", "http"} {
+		name := name
+		lc.OnShutdown(name, func(ctx context.Context) error {
+			called = append(called, name)
+			return nil
+		})
+	}
+
+	err := lc.Shutdown(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"http", "worker", "database"}, called)
+
+	// hooks are only called once
+	called = []string{}
+	assert.Nil(t, lc.Shutdown(context.Background()))
+	assert.Empty(t, called)
+}
+
+func TestLifecycleShutdownFails(t *testing.T) {
+	lc := newLifecycle()
+
+	called := 0
+	lc.OnShutdown("database", func(ctx context.Context) error {
+		called++
+		return nil
+	})
+	lc.OnShutdown("http", func(ctx context.Context) error {
+		called++
+		return fmt.Errorf("failed")
+	})
+
+	// every hook is called even if some fail
+	err := lc.Shutdown(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, called)
+}
+
+func TestLifecycleGoSucceeds(t *testing.T) {
+	lc := newLifecycle()
+
+	stopped := false
+	lc.Go("worker", func(ctx context.Context) {
+		<-ctx.Done()
+		stopped = true
+	})
+
+	err := lc.Shutdown(context.Background())
+	assert.Nil(t, err)
+	assert.True(t, stopped)
+}
+
+func TestLifecycleGoFails(t *testing.T) {
+	lc := newLifecycle()
+
+	release := make(chan struct{})
+	defer close(release)
+
+	// the worker ignores cancellation, so the deadline is hit
+	lc.Go("worker", func(ctx context.Context) {
+		<-release
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+
+	err := lc.Shutdown(ctx)
+	assert.NotNil(t, err)
+}
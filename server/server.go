@@ -1,9 +1,13 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"net"
 	"net/http"
-	"strings"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/jmoiron/sqlx"
@@ -11,10 +15,17 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 
-	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/testutil"
+	"github.com/labstack/gommon/log"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/config"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/database"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/throttle"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/metrics"
 
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/handlers"
 	middlew "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/middleware"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/notify"
 	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories/postgres"
 	usecases "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases/impl"
 )
@@ -35,28 +46,202 @@ func notImplemented(c echo.Context) error {
 	return c.JSONPretty(http.StatusInternalServerError, errResponse, Indent)
 }
 
-// Start starts and HTTP server.
-func Start(address string, testing, dokku bool) error {
+// logLevels maps the configured log level to the echo log level, used for
+// messages logged by echo itself.
+var logLevels = map[string]log.Lvl{
+	"debug": log.DEBUG,
+	"info":  log.INFO,
+	"warn":  log.WARN,
+	"error": log.ERROR,
+}
 
-	e := echo.New()
+// publicPaths are the paths that can be accessed without a key, in any API
+// version.
+var publicPaths = map[string]bool{
+	"/docs":              true,
+	"/docs/openapi.json": true,
+	"/healthz":           true,
+	"/metrics":           true,
+	"/readyz":            true,
+	"/login":             true,
+	"/login/two-factor":  true,
+	"/register":          true,
+	"/password/forgot":   true,
+	"/password/reset":    true,
+}
+
+// apiVersions returns the versions of the API served, oldest first. The
+// configuration must be valid.
+func apiVersions(cfg *config.APIConfig) []middlew.Version {
+	deprecation, _ := config.ParseDate(cfg.V1Deprecation)
+	sunset, _ := config.ParseDate(cfg.V1Sunset)
+
+	return []middlew.Version{
+		{Number: 1, Deprecation: deprecation, Sunset: sunset, Successor: 2},
+		{Number: 2},
+	}
+}
+
+// Start starts an HTTP server using the given configuration, and blocks until
+// it's stopped by SIGINT or SIGTERM. On shutdown, in-flight requests are given
+// until the configured deadline to finish before the database pool is closed.
+func Start(cfg *config.Config, testing bool) error {
+
+	level, err := logging.ParseLevel(cfg.Log.Level)
+	if err != nil {
+		return err
+	}
+
+	logger := logging.New(os.Stderr, level)
+	logging.SetDefault(logger)
+
+	lc := newLifecycle()
 
 	// database
 
-	var db *sqlx.DB
-	var err error
+	db, err := database.Open(&cfg.Database)
+	if err != nil {
+		return err
+	}
 
-	dbconfig := testutil.NewDatabaseConnectionConfig()
+	lc.OnShutdown("database", func(ctx context.Context) error {
+		return db.Close()
+	})
 
-	if dokku {
-		db, err = testutil.NewDatabaseConnectionDokku(dbconfig)
-	} else {
-		db, err = testutil.NewDatabaseConnection(dbconfig)
+	if cfg.Database.Migrate {
+		err = migrate(db, cfg.Database.Schema, logger)
+		if err != nil {
+			lc.Shutdown(context.Background())
+			return err
+		}
+	}
+
+	err = database.CheckSchema(db, cfg.Database.Schema)
+	if err != nil {
+		lc.Shutdown(context.Background())
+		return err
 	}
 
+	e, err := newEcho(cfg, testing, db, lc)
 	if err != nil {
+		lc.Shutdown(context.Background())
 		return err
 	}
 
+	lc.OnShutdown("http", e.Shutdown)
+
+	return serve(e, logger, fmt.Sprintf(":%d", cfg.Server.Port), lc, cfg.Server.ShutdownTimeout)
+}
+
+// migrate applies every pending migration to the given schema. Other instances
+// starting at the same time wait for it, as migrating takes a lock.
+func migrate(db *sqlx.DB, schema string, logger *logging.Logger) error {
+	migrator, err := database.NewEmbeddedMigrator(db, schema)
+	if err != nil {
+		return err
+	}
+
+	applied, err := migrator.Up(context.Background(), migrator.Latest())
+	for _, migration := range applied {
+		logger.Info("applied migration", logging.Fields{"version": migration.Version, "name": migration.Name})
+	}
+
+	return err
+}
+
+// serve starts the given echo instance and blocks until it fails, or until
+// SIGINT or SIGTERM is received. Either way, everything in the lifecycle is
+// shut down with the given deadline.
+func serve(e *echo.Echo, logger *logging.Logger, address string, lc *lifecycle, shutdownTimeout time.Duration) error {
+	errs := make(chan error, 1)
+	go func() {
+		errs <- e.Start(address)
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	var serveErr error
+	select {
+	case serveErr = <-errs:
+		logger.Error("server stopped", logging.Fields{"error": serveErr})
+	case sig := <-signals:
+		logger.Info("shutting down", logging.Fields{"signal": sig.String(), "deadline": shutdownTimeout.String()})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	err := lc.Shutdown(ctx)
+	if serveErr != nil && serveErr != http.ErrServerClosed {
+		return serveErr
+	}
+
+	return err
+}
+
+// pruneThrottles prunes the given throttles every interval until the context
+// is canceled, so that keys which stopped failing don't stay in memory.
+func pruneThrottles(ctx context.Context, interval time.Duration, throttles ...*throttle.Throttle) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, th := range throttles {
+				th.Prune()
+			}
+		}
+	}
+}
+
+// ipExtractor returns how client IPs are found, which log ins are throttled
+// and sessions recorded by. Without trusted proxies it's the address of the
+// connection, as any header can be forged; otherwise it's the address before
+// the last trusted proxy in X-Forwarded-For. The proxies are validated by the
+// configuration.
+func ipExtractor(trustedProxies []string) echo.IPExtractor {
+	if len(trustedProxies) <= 0 {
+		return echo.ExtractIPDirect()
+	}
+
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, proxy := range trustedProxies {
+		_, ipRange, _ := net.ParseCIDR(proxy)
+		options = append(options, echo.TrustIPRange(ipRange))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// newEcho returns a new echo instance with every route set up. Its background
+// workers are added to the given lifecycle.
+func newEcho(cfg *config.Config, testing bool, db *sqlx.DB, lc *lifecycle) (*echo.Echo, error) {
+
+	e := echo.New()
+	e.HideBanner = true
+	e.HTTPErrorHandler = handlers.ErrorHandler
+	e.IPExtractor = ipExtractor(cfg.Server.TrustedProxies)
+	e.Server.ReadTimeout = cfg.Server.ReadTimeout
+	e.Server.WriteTimeout = cfg.Server.WriteTimeout
+	e.Logger.SetLevel(logLevels[cfg.Log.Level])
+
+	err := metrics.Registry.Register(metrics.NewDBStatsCollector(db.DB))
+	if err != nil {
+		return nil, err
+	}
+
+	versions := apiVersions(&cfg.API)
+
 	// repos
 
 	userRepo := repos.NewUserRepository(db)
@@ -66,83 +251,131 @@ func Start(address string, testing, dokku bool) error {
 	maintenanceRepo := repos.NewMaintenanceRepository(db)
 	ticketRepo := repos.NewTicketRepository(db)
 	eventRepo := repos.NewEventRepository(db)
+	sessionRepo := repos.NewSessionRepository(db)
+	passwordResetRepo := repos.NewPasswordResetRepository(db)
+	loginAttemptRepo := repos.NewLoginAttemptRepository(db)
+	auditRepo := repos.NewAuditRepository(db)
+	deviceRepo := repos.NewDeviceRepository(db)
+	twoFactorRepo := repos.NewTwoFactorRepository(db)
+	roleRepo := repos.NewRoleRepository(db)
+	healthRepo := repos.NewHealthRepository(db)
+	reportRepo := repos.NewReportRepository(db)
+
+	// notifiers
+
+	notifier := notify.NewOutboxNotifier(cfg.Server.OutboxPath)
 
 	// usecases
 
-	timeout := time.Second * 2
-	userUsecase := usecases.NewUserUsecaseImpl(userRepo, timeout)
-	rideUsecase := usecases.NewRideUsecaseImpl(rideRepo, pictureRepo, reviewRepo, timeout)
-	reviewUsecase := usecases.NewReviewUsecaseImpl(reviewRepo, rideRepo, timeout)
-	maintenanceUsecase := usecases.NewMaintenanceUsecaseImpl(maintenanceRepo, timeout)
-	ticketUsecase := usecases.NewTicketUsecaseImpl(ticketRepo, rideRepo, userRepo)
-	eventUsecase := usecases.NewEventUsecaseImpl(eventRepo, timeout)
+	timeout := cfg.Server.UsecaseTimeout
+	auditUsecase := usecases.NewAuditUsecaseImpl(auditRepo, timeout)
+	userUsecase := usecases.NewUserUsecaseImpl(userRepo, auditUsecase, timeout)
+	rideUsecase := usecases.NewRideUsecaseImpl(rideRepo, pictureRepo, reviewRepo, auditUsecase, timeout)
+	reviewUsecase := usecases.NewReviewUsecaseImpl(reviewRepo, rideRepo, auditUsecase, timeout)
+	maintenanceUsecase := usecases.NewMaintenanceUsecaseImpl(maintenanceRepo, auditUsecase, timeout)
+	ticketUsecase := usecases.NewTicketUsecaseImpl(ticketRepo, rideRepo, userRepo, auditUsecase, timeout)
+	eventUsecase := usecases.NewEventUsecaseImpl(eventRepo, auditUsecase, timeout)
+	sessionUsecase := usecases.NewSessionUsecaseImpl(sessionRepo, userRepo, timeout)
+	loginAttemptUsecase := usecases.NewLoginAttemptUsecaseImpl(loginAttemptRepo, timeout)
+	passwordUsecase := usecases.NewPasswordUsecaseImpl(userRepo, passwordResetRepo, notifier, cfg.Auth.ResetDuration, timeout)
+	deviceUsecase := usecases.NewDeviceUsecaseImpl(deviceRepo, rideRepo, auditUsecase, timeout)
+	twoFactorUsecase := usecases.NewTwoFactorUsecaseImpl(twoFactorRepo, userRepo, auditUsecase, timeout)
+	roleUsecase := usecases.NewRoleUsecaseImpl(roleRepo, userRepo, auditUsecase, timeout)
+	healthUsecase := usecases.NewHealthUsecaseImpl(healthRepo, cfg.Database.Schema, timeout)
+	reportUsecase := usecases.NewReportUsecaseImpl(reportRepo, timeout)
 
 	// middleware
 
-	keyAuth := middlew.NewKeyAuth(userUsecase)
+	keyAuth := middlew.NewKeyAuth(userUsecase, sessionUsecase, []byte(cfg.Auth.SessionSecret), cfg.Auth.SessionDuration)
+	userUsecase.OnUserChange(keyAuth.InvalidateUser)
+	passwordUsecase.OnUserChange(keyAuth.InvalidateUser)
+	twoFactorUsecase.OnUserChange(keyAuth.InvalidateUser)
+	roleUsecase.OnUserChange(keyAuth.InvalidateUser)
+	deviceAuth := middlew.NewDeviceAuth(deviceUsecase, handlers.DevicePaths...)
+
+	accountThrottle := throttle.New(5, time.Second, time.Minute, time.Minute*15)
+	addressThrottle := throttle.New(20, time.Second, time.Minute, time.Minute*15)
+	resetAccountThrottle := throttle.New(5, time.Minute, time.Minute*15, time.Hour)
+	resetAddressThrottle := throttle.New(20, time.Second, time.Minute, time.Hour)
+	lc.Go("throttles", func(ctx context.Context) {
+		pruneThrottles(ctx, time.Minute, accountThrottle, addressThrottle, resetAccountThrottle, resetAddressThrottle)
+	})
+
 	keyAuthConfig := middleware.DefaultKeyAuthConfig
 	keyAuthConfig.Validator = keyAuth.Validator
 	keyAuthConfig.Skipper = func(c echo.Context) bool {
-		if strings.HasPrefix(c.Path(), "/login") {
-			return true
-		}
-		return false
+		return publicPaths[middlew.UnversionedPath(c.Path())] || middlew.HasDeviceKey(c)
 	}
 
 	corsConfig := middleware.DefaultCORSConfig
-	corsConfig.AllowOrigins = []string{"*"}
-	corsConfig.AllowHeaders = []string{"Origin", "X-Requested-With", "Content-Type", "Accept", "Authorization"}
+	corsConfig.AllowOrigins = cfg.CORS.AllowOrigins
+	corsConfig.AllowHeaders = cfg.CORS.AllowHeaders
 	corsConfig.AllowCredentials = true
 
+	e.Use(middlew.Metrics(e))
+	e.Use(middlew.RequestLogger(logging.Default()))
 	e.Use(middleware.CORSWithConfig(corsConfig))
+	e.Use(middlew.APIVersions(versions...))
 	if !testing {
 		e.Use(middleware.KeyAuthWithConfig(keyAuthConfig))
+		e.Use(deviceAuth.Middleware())
+		e.Use(middlew.RequireTwoFactorSetup(append(handlers.TwoFactorSetupPaths, "/logout")...))
+	} else {
+		e.Use(middlew.AllowAll())
 	}
-	e.Use(middleware.Logger())
 
 	// handlers
 
-	loginHandler := handlers.NewLoginHandler(keyAuth, userUsecase)
-	err = loginHandler.Bind(e)
-	if err != nil {
-		return err
+	docsHandler := handlers.NewDocsHandler()
+	root := e.Group("")
+	rootHandlers := []handlers.Handler{
+		handlers.NewHealthHandler(healthUsecase),
+		handlers.NewMetricsHandler(metrics.Registry, cfg.Server.MetricsToken),
+		docsHandler,
 	}
 
-	userHandler := handlers.NewUserHandler(userUsecase)
-	err = userHandler.Bind(e)
-	if err != nil {
-		return err
-	}
-
-	rideHandler := handlers.NewRideHandler(rideUsecase, maintenanceUsecase)
-	err = rideHandler.Bind(e)
-	if err != nil {
-		return err
-	}
-
-	reviewHandler := handlers.NewReviewHandler(reviewUsecase)
-	err = reviewHandler.Bind(e)
-	if err != nil {
-		return err
+	for _, handler := range rootHandlers {
+		err = handler.Bind(root)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	maintenanceHandler := handlers.NewMaintenanceHandler(maintenanceUsecase)
-	err = maintenanceHandler.Bind(e)
-	if err != nil {
-		return err
+	versionedHandlers := []handlers.Handler{
+		handlers.NewLoginHandler(keyAuth, userUsecase, loginAttemptUsecase, twoFactorUsecase, accountThrottle, addressThrottle),
+		handlers.NewSessionHandler(keyAuth, sessionUsecase),
+		handlers.NewPasswordHandler(keyAuth, passwordUsecase, resetAccountThrottle, resetAddressThrottle),
+		handlers.NewTwoFactorHandler(twoFactorUsecase),
+		handlers.NewRoleHandler(roleUsecase),
+		handlers.NewAuditHandler(auditUsecase),
+		handlers.NewDeviceHandler(deviceUsecase),
+		handlers.NewUserHandler(userUsecase),
+		handlers.NewRideHandler(rideUsecase, maintenanceUsecase),
+		handlers.NewReviewHandler(reviewUsecase),
+		handlers.NewMaintenanceHandler(maintenanceUsecase),
+		handlers.NewTicketHandler(ticketUsecase),
+		handlers.NewEventHandler(eventUsecase),
+		handlers.NewReportHandler(reportUsecase),
 	}
 
-	ticketHandler := handlers.NewTicketHandler(ticketUsecase)
-	err = ticketHandler.Bind(e)
-	if err != nil {
-		return err
+	// every version serves the same routes, handlers pick the shape of
+	// requests and responses with middleware.VersionFromContext
+	for _, version := range versions {
+		group := e.Group(version.Prefix())
+		for _, handler := range versionedHandlers {
+			err = handler.Bind(group)
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	eventHandler := handlers.NewEventHandler(eventUsecase)
-	err = eventHandler.Bind(e)
+	// the docs are generated from the routes, once they are all bound
+	document, err := handlers.Docs(e.Routes(), versions...)
 	if err != nil {
-		return err
+		logging.Default().Warn("the API docs are incomplete", logging.Fields{"error": err})
 	}
+	docsHandler.SetDocument(document)
 
-	return e.Start(address)
+	return e, nil
 }
@@ -0,0 +1,102 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/config"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/handlers"
+)
+
+var (
+	testEcho     *echo.Echo
+	testEchoErr  error
+	testEchoOnce sync.Once
+)
+
+// newTestEcho returns the echo instance of the server, with a database that
+// is never connected to. It's only created once, as the database metrics can
+// only be registered once.
+func newTestEcho(t *testing.T) *echo.Echo {
+	testEchoOnce.Do(func() {
+		var db *sqlx.DB
+		db, testEchoErr = sqlx.Open("pgx", "postgres://localhost/unused")
+		if testEchoErr == nil {
+			testEcho, testEchoErr = newEcho(&config.Config{}, true, db, newLifecycle())
+		}
+	})
+
+	if !assert.Nil(t, testEchoErr) {
+		t.FailNow()
+	}
+
+	return testEcho
+}
+
+func TestDocsCoverEveryRoute(t *testing.T) {
+	e := newTestEcho(t)
+	document, err := handlers.Docs(e.Routes(), apiVersions(&config.APIConfig{})...)
+	assert.Nil(t, err)
+
+	registered := map[string]bool{}
+	for _, route := range e.Routes() {
+		registered[route.Method+" "+route.Path] = true
+		assert.True(t, document.Has(route.Method, route.Path), "%s %s is missing from the docs", route.Method, route.Path)
+	}
+
+	for _, operation := range document.Operations() {
+		assert.True(t, registered[operation], "%s is documented but not registered", operation)
+	}
+}
+
+func TestDocsAreServed(t *testing.T) {
+	e := newTestEcho(t)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	document := map[string]interface{}{}
+	err := json.Unmarshal(rec.Body.Bytes(), &document)
+	if assert.Nil(t, err) {
+		assert.Equal(t, "3.0.3", document["openapi"])
+		assert.Contains(t, document["paths"], "/v1/tickets/{ticketID}")
+		assert.Contains(t, document["paths"], "/v2/tickets/{ticketID}")
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/docs/openapi.json")
+}
+
+func TestRoutesAreVersioned(t *testing.T) {
+	e := newTestEcho(t)
+
+	for _, path := range []string{"/rides", "/v3/rides"} {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, http.StatusNotFound, rec.Code, path)
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v2/healthz", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestClientIPIsOnlyForwardedByTrustedProxies(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set(echo.HeaderXForwardedFor, "203.0.113.7")
+
+	assert.Equal(t, "10.0.0.1", ipExtractor(nil)(req))
+	assert.Equal(t, "10.0.0.1", ipExtractor([]string{"192.168.0.0/16"})(req))
+	assert.Equal(t, "203.0.113.7", ipExtractor([]string{"10.0.0.0/8"})(req))
+}
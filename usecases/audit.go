@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// AuditUsecase is the usecase for recording and reviewing the audit log.
+type AuditUsecase interface {
+	Fetch(ctx context.Context, filter *models.AuditFilter) ([]*models.AuditEntry, error)
+
+	// Record records a mutating operation by the principal in the given
+	// context. Either before or after can be nil (e.g. on create or delete).
+	Record(ctx context.Context, action, entityType, entityID string, before, after interface{})
+}
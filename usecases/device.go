@@ -0,0 +1,18 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// DeviceUsecase is the usecase for interacting with devices and their keys.
+type DeviceUsecase interface {
+	GetByID(ctx context.Context, ID string) (*models.Device, error)
+	Fetch(ctx context.Context) ([]*models.Device, error)
+	Store(ctx context.Context, device *models.Device) (key string, err error)
+	Update(ctx context.Context, device *models.Device) error
+	Rotate(ctx context.Context, ID string) (device *models.Device, key string, err error)
+	Revoke(ctx context.Context, ID string) error
+	Authenticate(ctx context.Context, key string) (*models.Device, error)
+}
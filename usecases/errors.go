@@ -0,0 +1,60 @@
+package usecases
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorKind classifies the errors returned by usecases, so that transports can
+// report them consistently (e.g. as HTTP status codes).
+type ErrorKind string
+
+// Error kinds. Errors without a kind are internal errors.
+const (
+	KindNotFound    ErrorKind = "not_found"
+	KindConflict    ErrorKind = "conflict"
+	KindValidation  ErrorKind = "validation"
+	KindForbidden   ErrorKind = "forbidden"
+	KindUnavailable ErrorKind = "unavailable"
+)
+
+// Error is an error of a given kind. The message is meant for clients, and so
+// are the field errors of validation errors (see Validator).
+type Error struct {
+	Kind    ErrorKind
+	Message string
+	Err     error
+	Fields  []FieldError
+}
+
+// Errorf returns a new Error of the given kind, with the message formatted
+// like fmt.Errorf. An error given with the %w verb is wrapped.
+func Errorf(kind ErrorKind, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Kind: kind, Message: err.Error(), Err: errors.Unwrap(err)}
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the wrapped error, if any.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// KindOf returns the kind of the first Error in the chain of the given error,
+// or an empty kind if none.
+func KindOf(err error) ErrorKind {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Kind
+	}
+	return ""
+}
+
+// IsKind returns true if the given error is of the given kind.
+func IsKind(err error, kind ErrorKind) bool {
+	return KindOf(err) == kind
+}
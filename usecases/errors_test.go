@@ -0,0 +1,26 @@
+package usecases_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+func TestErrorKindSurvivesWrapping(t *testing.T) {
+	cause := fmt.Errorf("connection refused")
+	err := usecases.Errorf(usecases.KindUnavailable, "database unavailable: %w", cause)
+
+	assert.Equal(t, "database unavailable: connection refused", err.Error())
+	assert.True(t, errors.Is(err, cause))
+
+	wrapped := fmt.Errorf("error fetching rides: %w", err)
+	assert.Equal(t, usecases.KindUnavailable, usecases.KindOf(wrapped))
+	assert.True(t, usecases.IsKind(wrapped, usecases.KindUnavailable))
+
+	assert.Equal(t, usecases.ErrorKind(""), usecases.KindOf(cause))
+	assert.Equal(t, usecases.ErrorKind(""), usecases.KindOf(fmt.Errorf("error fetching rides: %s", err)))
+}
@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// HealthUsecase is the usecase for checking whether the service is alive and
+// ready to handle requests.
+type HealthUsecase interface {
+	Live(ctx context.Context) *models.HealthReport
+	Ready(ctx context.Context) *models.HealthReport
+}
@@ -0,0 +1,99 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// Entity types used in the audit log.
+const (
+	auditRide        = "ride"
+	auditReview      = "review"
+	auditMaintenance = "maintenance"
+	auditTicket      = "ticket"
+	auditTicketScan  = "ticket_scan"
+	auditEvent       = "event"
+	auditUser        = "user"
+	auditDevice      = "device"
+	auditTwoFactor   = "two_factor"
+	auditRole        = "role"
+)
+
+// AuditUsecaseImpl implements the AuditUsecase interface.
+type AuditUsecaseImpl struct {
+	auditRepo repos.AuditRepository
+	timeout   time.Duration
+}
+
+// NewAuditUsecaseImpl returns a new AuditUsecaseImpl instance.
+func NewAuditUsecaseImpl(auditRepo repos.AuditRepository, timeout time.Duration) *AuditUsecaseImpl {
+	return &AuditUsecaseImpl{
+		auditRepo,
+		timeout,
+	}
+}
+
+// Fetch fetches the audit entries matching the given filter.
+func (au *AuditUsecaseImpl) Fetch(ctx context.Context, filter *models.AuditFilter) ([]*models.AuditEntry, error) {
+	ctx, cancel := withTimeout(ctx, au.timeout)
+	defer cancel()
+
+	return au.auditRepo.Fetch(ctx, filter)
+}
+
+// Record records a mutating operation by the principal in the given context.
+// Errors are only logged, the operation already happened at this point. For
+// the same reason, the entry is recorded even if the given context was
+// cancelled meanwhile.
+func (au *AuditUsecaseImpl) Record(ctx context.Context, action, entityType, entityID string, before, after interface{}) {
+	ctx, cancel := withTimeout(detach(ctx), au.timeout)
+	defer cancel()
+
+	err := au.record(ctx, action, entityType, entityID, before, after)
+	if err != nil {
+		logging.FromContext(ctx).Error("error recording audit entry", logging.Fields{
+			"action":      action,
+			"entity_type": entityType,
+			"entity_id":   entityID,
+			"error":       err,
+		})
+	}
+}
+
+func (au *AuditUsecaseImpl) record(ctx context.Context, action, entityType, entityID string, before, after interface{}) error {
+	uuid, err := GenerateUUID()
+	if err != nil {
+		return err
+	}
+
+	beforeJSON, err := models.NewNullJSON(before)
+	if err != nil {
+		return err
+	}
+
+	afterJSON, err := models.NewNullJSON(after)
+	if err != nil {
+		return err
+	}
+
+	entry := &models.AuditEntry{
+		ID:         uuid,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     beforeJSON,
+		After:      afterJSON,
+		RecordedOn: time.Now().UTC(),
+	}
+
+	// NOTE: the actor is either an user or a device.
+	principal, _ := usecases.PrincipalFromContext(ctx)
+	entry.ActorID = models.NewNullString(principal.ID())
+
+	return au.auditRepo.Store(ctx, entry)
+}
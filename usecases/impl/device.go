@@ -0,0 +1,276 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+var (
+	errDeviceDoesNotExists = usecases.Errorf(usecases.KindNotFound, "device with the given ID does not exists")
+	errDeviceRevoked       = usecases.Errorf(usecases.KindConflict, "device was revoked")
+	errInvalidDeviceKey    = fmt.Errorf("device key is not valid")
+)
+
+// deviceSecretSize is the number of random bytes in a device secret.
+const deviceSecretSize = 32
+
+// deviceKeySeparator separates the device ID from the secret in device keys.
+const deviceKeySeparator = "."
+
+// deviceOperations are the operations devices can be scoped to.
+var deviceOperations = map[string]bool{
+	models.DeviceScanTickets: true,
+}
+
+// DeviceUsecaseImpl implements the DeviceUsecase interface.
+type DeviceUsecaseImpl struct {
+	deviceRepo   repos.DeviceRepository
+	rideRepo     repos.RideRepository
+	auditUsecase usecases.AuditUsecase
+	timeout      time.Duration
+}
+
+// NewDeviceUsecaseImpl returns a new DeviceUsecaseImpl instance. The timeout
+// parameter specifies a duration for each request before throwing and error.
+func NewDeviceUsecaseImpl(
+	deviceRepo repos.DeviceRepository,
+	rideRepo repos.RideRepository,
+	auditUsecase usecases.AuditUsecase,
+	timeout time.Duration) *DeviceUsecaseImpl {
+
+	return &DeviceUsecaseImpl{
+		deviceRepo,
+		rideRepo,
+		auditUsecase,
+		timeout,
+	}
+}
+
+// GetByID fetches device from the repositories using the given ID.
+func (du *DeviceUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.Device, error) {
+	ctx, cancel := withTimeout(ctx, du.timeout)
+	defer cancel()
+
+	device, err := du.deviceRepo.GetByID(ctx, ID)
+	if err != nil {
+		return nil, fmt.Errorf("error fetching device: %w", err)
+	}
+
+	return device, nil
+}
+
+// Fetch fetches all devices from the repositories.
+func (du *DeviceUsecaseImpl) Fetch(ctx context.Context) ([]*models.Device, error) {
+	ctx, cancel := withTimeout(ctx, du.timeout)
+	defer cancel()
+
+	devices, err := du.deviceRepo.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+}
+
+// Store creates a new device in the repository and returns its key. The key
+// is only returned here and when rotating, only its hash is stored.
+func (du *DeviceUsecaseImpl) Store(ctx context.Context, device *models.Device) (string, error) {
+	ctx, cancel := withTimeout(ctx, du.timeout)
+	defer cancel()
+
+	uuid, err := GenerateUUID()
+	if err != nil {
+		return "", err
+	}
+
+	device.ID = uuid
+	device.CreatedOn = time.Now().UTC()
+	device.RotatedOn = models.NullTime{}
+	device.RevokedOn = models.NullTime{}
+	cleanDevice(device)
+
+	err = du.validateDevice(ctx, device)
+	if err != nil {
+		return "", err
+	}
+
+	secret, err := crypto.NewToken(deviceSecretSize)
+	if err != nil {
+		return "", err
+	}
+
+	device.SecretHash = crypto.HashToken(secret)
+
+	err = du.deviceRepo.Store(ctx, device)
+	if err != nil {
+		return "", err
+	}
+
+	du.auditUsecase.Record(ctx, models.AuditCreate, auditDevice, device.ID, nil, device)
+	return device.ID + deviceKeySeparator + secret, nil
+}
+
+// Update updates the name and scopes of an existing device in the repository.
+func (du *DeviceUsecaseImpl) Update(ctx context.Context, device *models.Device) error {
+	ctx, cancel := withTimeout(ctx, du.timeout)
+	defer cancel()
+
+	before, err := du.deviceRepo.GetByID(ctx, device.ID)
+	if err != nil {
+		return orNotFound(err, errDeviceDoesNotExists)
+	}
+
+	if !before.IsActive() {
+		return errDeviceRevoked
+	}
+
+	cleanDevice(device)
+	err = du.validateDevice(ctx, device)
+	if err != nil {
+		return err
+	}
+
+	err = du.deviceRepo.Update(ctx, device)
+	if err != nil {
+		return err
+	}
+
+	after, err := du.deviceRepo.GetByID(ctx, device.ID)
+	if err != nil {
+		return err
+	}
+
+	*device = *after
+	du.auditUsecase.Record(ctx, models.AuditUpdate, auditDevice, device.ID, before, device)
+	return nil
+}
+
+// Rotate replaces the secret of the given device and returns the updated
+// device and its new key. The old key stops working immediately.
+func (du *DeviceUsecaseImpl) Rotate(ctx context.Context, ID string) (*models.Device, string, error) {
+	ctx, cancel := withTimeout(ctx, du.timeout)
+	defer cancel()
+
+	before, err := du.deviceRepo.GetByID(ctx, ID)
+	if err != nil {
+		return nil, "", errDeviceDoesNotExists
+	}
+
+	if !before.IsActive() {
+		return nil, "", errDeviceRevoked
+	}
+
+	secret, err := crypto.NewToken(deviceSecretSize)
+	if err != nil {
+		return nil, "", err
+	}
+
+	err = du.deviceRepo.UpdateSecret(ctx, ID, crypto.HashToken(secret), time.Now().UTC())
+	if err != nil {
+		return nil, "", err
+	}
+
+	device, err := du.deviceRepo.GetByID(ctx, ID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	du.auditUsecase.Record(ctx, models.AuditUpdate, auditDevice, device.ID, before, device)
+	return device, device.ID + deviceKeySeparator + secret, nil
+}
+
+// Revoke revokes the given device. Revoked devices can't authenticate and
+// can't be updated or rotated.
+func (du *DeviceUsecaseImpl) Revoke(ctx context.Context, ID string) error {
+	ctx, cancel := withTimeout(ctx, du.timeout)
+	defer cancel()
+
+	before, err := du.deviceRepo.GetByID(ctx, ID)
+	if err != nil {
+		return orNotFound(err, errDeviceDoesNotExists)
+	}
+
+	if !before.IsActive() {
+		return errDeviceRevoked
+	}
+
+	err = du.deviceRepo.Revoke(ctx, ID, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	du.auditUsecase.Record(ctx, models.AuditRevoke, auditDevice, ID, before, nil)
+	return nil
+}
+
+// Authenticate returns the device for the given key. An error is returned if
+// the key is not valid or the device was revoked.
+func (du *DeviceUsecaseImpl) Authenticate(ctx context.Context, key string) (*models.Device, error) {
+	ctx, cancel := withTimeout(ctx, du.timeout)
+	defer cancel()
+
+	parts := strings.SplitN(strings.TrimSpace(key), deviceKeySeparator, 2)
+	if len(parts) != 2 {
+		return nil, errInvalidDeviceKey
+	}
+
+	device, err := du.deviceRepo.GetByID(ctx, parts[0])
+	if err != nil {
+		return nil, errInvalidDeviceKey
+	}
+
+	if !crypto.CompareHashAndToken(device.SecretHash, parts[1]) {
+		return nil, errInvalidDeviceKey
+	}
+
+	if !device.IsActive() {
+		return nil, errDeviceRevoked
+	}
+
+	return device, nil
+}
+
+func cleanDevice(device *models.Device) {
+	device.ID = strings.TrimSpace(device.ID)
+	device.Name = strings.TrimSpace(device.Name)
+	for _, scope := range device.Scopes {
+		scope.Operation = strings.TrimSpace(scope.Operation)
+		scope.RideID.String = strings.TrimSpace(scope.RideID.String)
+	}
+}
+
+func (du *DeviceUsecaseImpl) validateDevice(ctx context.Context, device *models.Device) error {
+	v := usecases.Validator{}
+	v.Required("/id", device.ID)
+	if v.Required("/name", device.Name) {
+		v.MaxLength("/name", device.Name, 64)
+	}
+
+	for i, scope := range device.Scopes {
+		pointer := fmt.Sprintf("/scopes/%d", i)
+		if !v.Check(scope != nil, pointer, usecases.CodeRequired, "scopes must be non-null") {
+			continue
+		}
+
+		v.Check(deviceOperations[scope.Operation], pointer+"/operation", usecases.CodeInvalid, "unknown operation '%s'", scope.Operation)
+
+		if !scope.RideID.Valid {
+			continue
+		}
+
+		_, err := du.rideRepo.GetByID(ctx, scope.RideID.String)
+		if err != nil && !usecases.IsKind(err, usecases.KindNotFound) {
+			return err
+		}
+		v.Check(err == nil, pointer+"/rideId", usecases.CodeNotFound, "ride '%s' does not exists", scope.RideID.String)
+	}
+
+	return v.Err()
+}
@@ -10,66 +10,70 @@ import (
 
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
 var (
-	errEventExists        = fmt.Errorf("event with the given ID already exists")
-	errEventDoesNotExists = fmt.Errorf("event with he given ID does not exists")
+	errEventExists        = usecases.Errorf(usecases.KindConflict, "event with the given ID already exists")
+	errEventDoesNotExists = usecases.Errorf(usecases.KindNotFound, "event with he given ID does not exists")
 )
 
 // EventUsecaseImpl implements the EventUsecase interface.
 type EventUsecaseImpl struct {
-	eventRepo repos.EventRepository
-	timeout   time.Duration
+	eventRepo    repos.EventRepository
+	auditUsecase usecases.AuditUsecase
+	timeout      time.Duration
 }
 
 // NewEventUsecaseImpl returns a new EventUsecaseImpl instance. The timeout
 // parameter specifies a duration for each request before throwing and error.
 func NewEventUsecaseImpl(
 	eventRepo repos.EventRepository,
+	auditUsecase usecases.AuditUsecase,
 	timeout time.Duration) *EventUsecaseImpl {
 
 	return &EventUsecaseImpl{
 		eventRepo,
+		auditUsecase,
 		timeout,
 	}
 }
 
 // GetByID fetches event from the repositories using the given ID.
 func (eu *EventUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.Event, error) {
+	ctx, cancel := withTimeout(ctx, eu.timeout)
+	defer cancel()
 
-	event, err := eu.eventRepo.GetByID(ID)
+	event, err := eu.eventRepo.GetByID(ctx, ID)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching event: %s", err)
+		return nil, fmt.Errorf("error fetching event: %w", err)
 	}
 
 	return event, nil
 }
 
-// Fetch fetches all Events from the repositories.
-func (eu *EventUsecaseImpl) Fetch(ctx context.Context) ([]*models.Event, error) {
-	allEvent, err := eu.eventRepo.Fetch()
-	if err != nil {
-		return nil, err
-	}
-
-	return allEvent, nil
-}
+// Fetch fetches a page of the events matching the given filter.
+func (eu *EventUsecaseImpl) Fetch(ctx context.Context, filter *models.EventFilter, page *models.PageRequest) ([]*models.Event, string, error) {
+	ctx, cancel := withTimeout(ctx, eu.timeout)
+	defer cancel()
 
-// FetchSince is like fetch, but fetches all events since a specific time.
-func (eu *EventUsecaseImpl) FetchSince(ctx context.Context, day time.Time) ([]*models.Event, error) {
-	event, err := eu.eventRepo.FetchSince(day)
+	v := &usecases.Validator{}
+	validateList(v, page, filter.Since, filter.Until)
+	err := v.Err()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return event, err
+	return eu.eventRepo.Fetch(ctx, filter, page)
 }
 
 // Store creates a new event in the repository if a event with the same ID
 // doesn't exists already.
 func (eu *EventUsecaseImpl) Store(ctx context.Context, event *models.Event) error {
-	_, err := eu.eventRepo.GetByID(event.ID)
+	ctx, cancel := withTimeout(ctx, eu.timeout)
+	defer cancel()
+
+	_, err := eu.eventRepo.GetByID(ctx, event.ID)
 	if err == nil {
 		return errEventExists
 	}
@@ -88,19 +92,23 @@ func (eu *EventUsecaseImpl) Store(ctx context.Context, event *models.Event) erro
 		return err
 	}
 
-	err = eu.eventRepo.Store(event)
+	err = eu.eventRepo.Store(ctx, event)
 	if err != nil {
 		return err
 	}
 
+	eu.auditUsecase.Record(ctx, models.AuditCreate, auditEvent, event.ID, nil, event)
 	return nil
 }
 
 // Update updates a specific Event job in the repositories.
 func (eu *EventUsecaseImpl) Update(ctx context.Context, event *models.Event) error {
-	_, err := eu.eventRepo.GetByID(event.ID)
+	ctx, cancel := withTimeout(ctx, eu.timeout)
+	defer cancel()
+
+	before, err := eu.eventRepo.GetByID(ctx, event.ID)
 	if err != nil {
-		return errEventDoesNotExists
+		return orNotFound(err, errEventDoesNotExists)
 	}
 
 	cleanEvent(event)
@@ -110,32 +118,40 @@ func (eu *EventUsecaseImpl) Update(ctx context.Context, event *models.Event) err
 	}
 
 	event.PostedOn = time.Time{}
-	err = eu.eventRepo.Update(event)
+	err = eu.eventRepo.Update(ctx, event)
 	if err != nil {
 		return err
 	}
 
+	eu.auditUsecase.Record(ctx, models.AuditUpdate, auditEvent, event.ID, before, event)
 	return nil
 }
 
 // Delete deletes a specific event from the repositories.
 func (eu *EventUsecaseImpl) Delete(ctx context.Context, ID string) error {
-	_, err := eu.eventRepo.GetByID(ID)
+	ctx, cancel := withTimeout(ctx, eu.timeout)
+	defer cancel()
+
+	before, err := eu.eventRepo.GetByID(ctx, ID)
 	if err != nil {
-		return errEventDoesNotExists
+		return orNotFound(err, errEventDoesNotExists)
 	}
 
-	err = eu.eventRepo.Delete(ID)
+	err = eu.eventRepo.Delete(ctx, ID)
 	if err != nil {
 		return err
 	}
 
+	eu.auditUsecase.Record(ctx, models.AuditDelete, auditEvent, ID, before, nil)
 	return nil
 }
 
 // AvailableEventTypes returns the available event types.
 func (eu *EventUsecaseImpl) AvailableEventTypes(ctx context.Context) ([]*models.EventType, error) {
-	etypes, err := eu.eventRepo.AvailableEventTypes()
+	ctx, cancel := withTimeout(ctx, eu.timeout)
+	defer cancel()
+
+	etypes, err := eu.eventRepo.AvailableEventTypes(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -158,22 +174,17 @@ func cleanEvent(event *models.Event) {
 }
 
 func validateEvent(event *models.Event) error {
-
-	if len(event.ID) <= 0 {
-		return fmt.Errorf("validateEvent: ID must be non-empty")
-	}
-
-	if len(event.EventType) <= 0 {
-		return fmt.Errorf("validateEvent: event type must be non-empty")
+	v := usecases.Validator{}
+	v.Required("/id", event.ID)
+	if v.Required("/eventType", event.EventType) {
+		v.MaxLength("/eventType", event.EventType, 32)
 	}
-
-	if len(event.Title) <= 0 {
-		return fmt.Errorf("validateEvent: title must be non-empty")
+	if v.Required("/title", event.Title) {
+		v.MaxLength("/title", event.Title, 32)
 	}
-
-	if event.EmployeeID.Valid && len(event.EmployeeID.String) <= 0 {
-		return fmt.Errorf("validateEvent: employee ID must be non-empty")
+	v.MaxLength("/description", event.Description, 512)
+	if event.EmployeeID.Valid {
+		v.Required("/employeeId", event.EmployeeID.String)
 	}
-
-	return nil
+	return v.Err()
 }
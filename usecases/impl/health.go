@@ -0,0 +1,101 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+)
+
+// requiredRoles are the employee roles that must exist for the service to
+// work (see models.Role*).
+var requiredRoles = []string{models.RoleSupervisor}
+
+// HealthUsecaseImpl implements the HealthUsecase interface.
+type HealthUsecaseImpl struct {
+	healthRepo repos.HealthRepository
+	schema     string
+	timeout    time.Duration
+}
+
+// NewHealthUsecaseImpl returns a new HealthUsecaseImpl instance. The schema
+// parameter is the database schema that must exist. The timeout parameter
+// specifies a duration for the whole readiness check.
+func NewHealthUsecaseImpl(
+	healthRepo repos.HealthRepository,
+	schema string,
+	timeout time.Duration) *HealthUsecaseImpl {
+
+	return &HealthUsecaseImpl{
+		healthRepo,
+		schema,
+		timeout,
+	}
+}
+
+// Live returns a report without checks. If the process can answer, it's alive.
+func (hu *HealthUsecaseImpl) Live(ctx context.Context) *models.HealthReport {
+	return &models.HealthReport{Status: models.HealthOK}
+}
+
+// Ready checks that the database is reachable, that the schema exists and that
+// the reference rows the service relies on exist. Checks after a failed ping
+// are still run, so every failure is reported.
+func (hu *HealthUsecaseImpl) Ready(ctx context.Context) *models.HealthReport {
+	ctx, cancel := context.WithTimeout(ctx, hu.timeout)
+	defer cancel()
+
+	report := &models.HealthReport{Status: models.HealthOK}
+	check := func(name string, fn func() error) {
+		start := time.Now()
+		err := fn()
+		result := &models.HealthCheck{
+			Name:     name,
+			Status:   models.HealthOK,
+			Duration: time.Since(start).String(),
+		}
+		if err != nil {
+			result.Status = models.HealthFail
+			result.Error = err.Error()
+			report.Status = models.HealthFail
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	check("database", func() error {
+		return hu.healthRepo.Ping(ctx)
+	})
+
+	check("schema", func() error {
+		return requireExists(ctx, hu.healthRepo.SchemaExists, "schema", hu.schema)
+	})
+
+	check("event_types", func() error {
+		return requireExists(ctx, hu.healthRepo.EventTypeExists, "event type", models.EventTypeSystem)
+	})
+
+	check("roles", func() error {
+		for _, role := range requiredRoles {
+			err := requireExists(ctx, hu.healthRepo.RoleExists, "role", role)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	return report
+}
+
+func requireExists(ctx context.Context, fn func(context.Context, string) (bool, error), kind string, name string) error {
+	ok, err := fn(ctx, name)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%s %q does not exist", kind, name)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package impl
+
+import (
+	"sync"
+)
+
+// userHooks holds the functions to call whenever an user changes, e.g. to
+// drop cached keys for the user.
+type userHooks struct {
+	mu    sync.RWMutex
+	hooks []func(userID string)
+}
+
+// OnUserChange registers a function to be called with the ID of an user
+// whenever its details, role or password change, or when it's deleted.
+func (uh *userHooks) OnUserChange(hook func(userID string)) {
+	uh.mu.Lock()
+	defer uh.mu.Unlock()
+
+	uh.hooks = append(uh.hooks, hook)
+}
+
+func (uh *userHooks) userChanged(userID string) {
+	uh.mu.RLock()
+	defer uh.mu.RUnlock()
+
+	for _, hook := range uh.hooks {
+		hook(userID)
+	}
+}
@@ -1,9 +1,19 @@
 package impl
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
+// maxNumeric is the largest value of a numeric(10, 2) column, used for prices
+// and costs.
+const maxNumeric = 99999999.99
+
 // GenerateUUID generates a new UUID (V4) string.
 func GenerateUUID() (string, error) {
 	uuid, err := uuid.NewRandom()
@@ -13,3 +23,60 @@ func GenerateUUID() (string, error) {
 
 	return uuid.String(), nil
 }
+
+// truncate returns the first n characters of the given string, cutting on a
+// rune boundary so that the result is still valid UTF-8.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
+
+// withTimeout returns a copy of the given context that is cancelled after the
+// given timeout, which is the deadline for a single usecase operation and the
+// queries it runs. A timeout of zero means no deadline besides the one of the
+// given context.
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// detachedContext is a context that keeps the values of its parent, like the
+// principal and the logger, but is never cancelled with it.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// detach returns a copy of the given context that is not cancelled when the
+// given one is, for work that must finish once started.
+func detach(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
+
+// orNotFound returns notFound if the given repository error is a not found
+// error, or the error itself otherwise, so that database failures aren't
+// reported as missing entities.
+func orNotFound(err, notFound error) error {
+	if usecases.IsKind(err, usecases.KindNotFound) {
+		return notFound
+	}
+	return err
+}
+
+// validateList validates the page of a list and the range of times it is
+// filtered on, its pointers are the names of the query parameters. The sort
+// and cursor are validated by the repositories, which know the sort fields.
+func validateList(v *usecases.Validator, page *models.PageRequest, since, until models.NullTime) {
+	v.Range("/limit", float64(page.Limit), 1, models.MaxPageLimit)
+	if since.Valid && until.Valid {
+		v.Check(until.Time.After(since.Time), "/until", usecases.CodeOutOfRange, "until must be after since")
+	}
+}
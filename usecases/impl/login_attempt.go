@@ -0,0 +1,87 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/metrics"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// LoginAttemptUsecaseImpl implements the LoginAttemptUsecase interface.
+type LoginAttemptUsecaseImpl struct {
+	loginAttemptRepo repos.LoginAttemptRepository
+	timeout          time.Duration
+}
+
+// NewLoginAttemptUsecaseImpl returns a new LoginAttemptUsecaseImpl instance.
+func NewLoginAttemptUsecaseImpl(loginAttemptRepo repos.LoginAttemptRepository, timeout time.Duration) *LoginAttemptUsecaseImpl {
+	return &LoginAttemptUsecaseImpl{
+		loginAttemptRepo,
+		timeout,
+	}
+}
+
+// FetchSince fetches all log in attempts since the given time.
+func (lu *LoginAttemptUsecaseImpl) FetchSince(ctx context.Context, since time.Time) ([]*models.LoginAttempt, error) {
+	ctx, cancel := withTimeout(ctx, lu.timeout)
+	defer cancel()
+
+	return lu.loginAttemptRepo.FetchSince(ctx, since)
+}
+
+// FetchForEmailSince fetches all log in attempts for the given email since the
+// given time.
+func (lu *LoginAttemptUsecaseImpl) FetchForEmailSince(ctx context.Context, email string, since time.Time) ([]*models.LoginAttempt, error) {
+	ctx, cancel := withTimeout(ctx, lu.timeout)
+	defer cancel()
+
+	return lu.loginAttemptRepo.FetchForEmailSince(ctx, strings.TrimSpace(email), since)
+}
+
+// Store records a new log in attempt. The attempt ID and time are generated.
+// Failed attempts are counted by reason, even if storing them fails.
+func (lu *LoginAttemptUsecaseImpl) Store(ctx context.Context, attempt *models.LoginAttempt) error {
+	ctx, cancel := withTimeout(ctx, lu.timeout)
+	defer cancel()
+
+	uuid, err := GenerateUUID()
+	if err != nil {
+		return err
+	}
+
+	attempt.ID = uuid
+	attempt.AttemptedOn = time.Now().UTC()
+	cleanLoginAttempt(attempt)
+	err = validateLoginAttempt(attempt)
+	if err != nil {
+		return err
+	}
+
+	if !attempt.Success {
+		metrics.LoginFailures.WithLabelValues(attempt.Reason.String).Inc()
+	}
+
+	return lu.loginAttemptRepo.Store(ctx, attempt)
+}
+
+func cleanLoginAttempt(attempt *models.LoginAttempt) {
+	attempt.Email = strings.TrimSpace(attempt.Email)
+	attempt.UserAgent.String = strings.TrimSpace(attempt.UserAgent.String)
+	attempt.Address.String = strings.TrimSpace(attempt.Address.String)
+
+	// NOTE: emails and user agents are client provided and can be of any
+	// length, but we still want to record the attempt.
+	attempt.Email = truncate(attempt.Email, 64)
+	attempt.UserAgent.String = truncate(attempt.UserAgent.String, 256)
+}
+
+func validateLoginAttempt(attempt *models.LoginAttempt) error {
+	v := usecases.Validator{}
+	v.Required("/id", attempt.ID)
+	v.Required("/email", attempt.Email)
+	return v.Err()
+}
@@ -3,36 +3,42 @@ package impl
 import (
 	"context"
 	"database/sql"
-	"fmt"
 	"strings"
 	"time"
 
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/metrics"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
 var (
-	errMaintenanceExists        = fmt.Errorf("maintenance job with the given ID already exists")
-	errMaintenanceDoesNotExists = fmt.Errorf("maintenance job with he given ID does not exists")
+	errMaintenanceExists        = usecases.Errorf(usecases.KindConflict, "maintenance job with the given ID already exists")
+	errMaintenanceDoesNotExists = usecases.Errorf(usecases.KindNotFound, "maintenance job with he given ID does not exists")
 )
 
 // MaintenanceUsecaseImpl implements the MaintenanceUsecase interface.
 type MaintenanceUsecaseImpl struct {
 	maintenanceRepo repos.MaintenanceRepository
+	auditUsecase    usecases.AuditUsecase
 	timeout         time.Duration
 }
 
 // NewMaintenanceUsecaseImpl returns a new MaintenanceUsecaseImpl instance.
-func NewMaintenanceUsecaseImpl(maintenanceRepo repos.MaintenanceRepository, timeout time.Duration) *MaintenanceUsecaseImpl {
+func NewMaintenanceUsecaseImpl(maintenanceRepo repos.MaintenanceRepository, auditUsecase usecases.AuditUsecase, timeout time.Duration) *MaintenanceUsecaseImpl {
 	return &MaintenanceUsecaseImpl{
 		maintenanceRepo,
+		auditUsecase,
 		timeout,
 	}
 }
 
 // GetByID fetches a specific maintenance job from the repositories.
 func (mu *MaintenanceUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.Maintenance, error) {
-	maintenance, err := mu.maintenanceRepo.GetByID(ID)
+	ctx, cancel := withTimeout(ctx, mu.timeout)
+	defer cancel()
+
+	maintenance, err := mu.maintenanceRepo.GetByID(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
@@ -40,29 +46,31 @@ func (mu *MaintenanceUsecaseImpl) GetByID(ctx context.Context, ID string) (*mode
 	return maintenance, nil
 }
 
-// Fetch fetches all maintenance jobs from the repositories.
-func (mu *MaintenanceUsecaseImpl) Fetch(ctx context.Context) ([]*models.Maintenance, error) {
-	allMaintenance, err := mu.maintenanceRepo.Fetch()
-	if err != nil {
-		return nil, err
-	}
+// Fetch fetches a page of the maintenance jobs matching the given filter.
+func (mu *MaintenanceUsecaseImpl) Fetch(ctx context.Context, filter *models.MaintenanceFilter, page *models.PageRequest) ([]*models.Maintenance, string, error) {
+	ctx, cancel := withTimeout(ctx, mu.timeout)
+	defer cancel()
 
-	return allMaintenance, err
-}
+	v := &usecases.Validator{}
+	validateList(v, page, filter.Since, filter.Until)
+	if len(filter.Status) > 0 {
+		v.Check(filter.Status == models.MaintenanceOpen || filter.Status == models.MaintenanceClosed, "/status", usecases.CodeInvalid, "status must be one of %s, %s", models.MaintenanceOpen, models.MaintenanceClosed)
+	}
 
-// FetchForRide is like fetch, but fetches all maintenance jobs for a specific ride.
-func (mu *MaintenanceUsecaseImpl) FetchForRide(ctx context.Context, rideID string) ([]*models.Maintenance, error) {
-	maintenance, err := mu.maintenanceRepo.FetchForRide(rideID)
+	err := v.Err()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return maintenance, err
+	return mu.maintenanceRepo.Fetch(ctx, filter, page)
 }
 
 // Begin creates a new maintenance job in the repositories.
 func (mu *MaintenanceUsecaseImpl) Begin(ctx context.Context, maintenance *models.Maintenance) error {
-	_, err := mu.maintenanceRepo.GetByID(maintenance.ID)
+	ctx, cancel := withTimeout(ctx, mu.timeout)
+	defer cancel()
+
+	_, err := mu.maintenanceRepo.GetByID(ctx, maintenance.ID)
 	if err == nil {
 		return errMaintenanceExists
 	}
@@ -81,19 +89,24 @@ func (mu *MaintenanceUsecaseImpl) Begin(ctx context.Context, maintenance *models
 		return err
 	}
 
-	err = mu.maintenanceRepo.Store(maintenance)
+	err = mu.maintenanceRepo.Store(ctx, maintenance)
 	if err != nil {
 		return err
 	}
 
+	mu.auditUsecase.Record(ctx, models.AuditCreate, auditMaintenance, maintenance.ID, nil, maintenance)
+	metrics.MaintenanceOpened.Inc()
 	return nil
 }
 
 // Update updates a specific maintenance job in the repositories.
 func (mu *MaintenanceUsecaseImpl) Update(ctx context.Context, maintenance *models.Maintenance) error {
-	_, err := mu.maintenanceRepo.GetByID(maintenance.ID)
+	ctx, cancel := withTimeout(ctx, mu.timeout)
+	defer cancel()
+
+	before, err := mu.maintenanceRepo.GetByID(ctx, maintenance.ID)
 	if err != nil {
-		return errMaintenanceDoesNotExists
+		return orNotFound(err, errMaintenanceDoesNotExists)
 	}
 
 	cleanMaintenance(maintenance)
@@ -103,21 +116,26 @@ func (mu *MaintenanceUsecaseImpl) Update(ctx context.Context, maintenance *model
 	}
 
 	maintenance.End = models.NullTime{}
-	err = mu.maintenanceRepo.Update(maintenance)
+	err = mu.maintenanceRepo.Update(ctx, maintenance)
 	if err != nil {
 		return err
 	}
 
+	mu.auditUsecase.Record(ctx, models.AuditUpdate, auditMaintenance, maintenance.ID, before, maintenance)
 	return nil
 }
 
 // Close closes an existing maintenance job in the repositories.
 func (mu *MaintenanceUsecaseImpl) Close(ctx context.Context, ID string) (*models.Maintenance, error) {
-	maintenance, err := mu.maintenanceRepo.GetByID(ID)
+	ctx, cancel := withTimeout(ctx, mu.timeout)
+	defer cancel()
+
+	maintenance, err := mu.maintenanceRepo.GetByID(ctx, ID)
 	if err != nil {
-		return nil, errMaintenanceDoesNotExists
+		return nil, orNotFound(err, errMaintenanceDoesNotExists)
 	}
 
+	before := *maintenance
 	maintenance.End = models.FromSQLNullTime(sql.NullTime{Time: time.Now().UTC(), Valid: true})
 	cleanMaintenance(maintenance)
 	err = validateMaintenance(maintenance)
@@ -125,26 +143,32 @@ func (mu *MaintenanceUsecaseImpl) Close(ctx context.Context, ID string) (*models
 		return nil, err
 	}
 
-	err = mu.maintenanceRepo.Update(maintenance)
+	err = mu.maintenanceRepo.Update(ctx, maintenance)
 	if err != nil {
 		return nil, err
 	}
 
+	mu.auditUsecase.Record(ctx, models.AuditClose, auditMaintenance, maintenance.ID, &before, maintenance)
+	metrics.MaintenanceClosed.Inc()
 	return maintenance, nil
 }
 
 // Delete deletes a specific maintenance job from the repositories.
 func (mu *MaintenanceUsecaseImpl) Delete(ctx context.Context, ID string) error {
-	_, err := mu.maintenanceRepo.GetByID(ID)
+	ctx, cancel := withTimeout(ctx, mu.timeout)
+	defer cancel()
+
+	before, err := mu.maintenanceRepo.GetByID(ctx, ID)
 	if err != nil {
-		return errMaintenanceDoesNotExists
+		return orNotFound(err, errMaintenanceDoesNotExists)
 	}
 
-	err = mu.maintenanceRepo.Delete(ID)
+	err = mu.maintenanceRepo.Delete(ctx, ID)
 	if err != nil {
 		return err
 	}
 
+	mu.auditUsecase.Record(ctx, models.AuditDelete, auditMaintenance, ID, before, nil)
 	return nil
 }
 
@@ -155,26 +179,15 @@ func cleanMaintenance(maintenance *models.Maintenance) {
 }
 
 func validateMaintenance(maintenance *models.Maintenance) error {
-
-	if len(maintenance.ID) <= 0 {
-		return fmt.Errorf("validateMaintenance: ID must be non-empty")
-	}
-
-	if len(maintenance.RideID) <= 0 {
-		return fmt.Errorf("validateMaintenance: RideID must be non-empty")
-	}
-
-	if len(maintenance.Description) <= 0 {
-		return fmt.Errorf("validateMaintenance: description must be non-empty")
+	v := usecases.Validator{}
+	v.Required("/id", maintenance.ID)
+	v.Required("/rideId", maintenance.RideID)
+	if v.Required("/description", maintenance.Description) {
+		v.MaxLength("/description", maintenance.Description, 512)
 	}
-
-	if maintenance.Cost < 0 {
-		return fmt.Errorf("validateMaintenance: cost must be non-negative")
-	}
-
-	if maintenance.End.Valid && maintenance.Start.After(maintenance.End.Time) {
-		return fmt.Errorf("validateMaintenance: start date must be before end date")
+	v.Range("/cost", maintenance.Cost, 0, maxNumeric)
+	if maintenance.End.Valid {
+		v.Check(!maintenance.Start.After(maintenance.End.Time), "/end", usecases.CodeOutOfRange, "end must be after start")
 	}
-
-	return nil
+	return v.Err()
 }
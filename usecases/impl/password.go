@@ -0,0 +1,190 @@
+package impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/notify"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+var (
+	errWrongPassword      = usecases.Errorf(usecases.KindValidation, "current password is not valid")
+	errInvalidResetToken  = usecases.Errorf(usecases.KindValidation, "reset token is not valid or has expired")
+	errResetTokenWasUsed  = usecases.Errorf(usecases.KindValidation, "reset token was already used")
+	errResetTokenRequired = usecases.Errorf(usecases.KindValidation, "reset token must be non-empty")
+)
+
+// resetTokenSize is the number of random bytes in a reset token.
+const resetTokenSize = 32
+
+// PasswordUsecaseImpl implements the PasswordUsecase interface.
+type PasswordUsecaseImpl struct {
+	userRepo      repos.UserRepository
+	resetRepo     repos.PasswordResetRepository
+	notifier      notify.Notifier
+	resetDuration time.Duration
+	timeout       time.Duration
+	*userHooks
+}
+
+// NewPasswordUsecaseImpl returns a new PasswordUsecaseImpl instance. Reset
+// tokens are valid for the given reset duration.
+func NewPasswordUsecaseImpl(userRepo repos.UserRepository, resetRepo repos.PasswordResetRepository, notifier notify.Notifier, resetDuration, timeout time.Duration) *PasswordUsecaseImpl {
+	return &PasswordUsecaseImpl{
+		userRepo,
+		resetRepo,
+		notifier,
+		resetDuration,
+		timeout,
+		&userHooks{},
+	}
+}
+
+// Change changes the password of the given user if the current password is
+// valid. All sessions of the user are revoked, the updated user is returned.
+func (pu *PasswordUsecaseImpl) Change(ctx context.Context, userID, currentPassword, newPassword string) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, pu.timeout)
+	defer cancel()
+
+	user, err := pu.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, orNotFound(err, errUserDoesNotExists)
+	}
+
+	if !crypto.CompareHashAndPassword(user.PasswordHash, cleanPassword(currentPassword)) {
+		return nil, errWrongPassword
+	}
+
+	return pu.updatePassword(ctx, user, newPassword)
+}
+
+// RequestReset creates a new reset token for the user with the given email
+// and sends it through the notifier. To avoid telling which emails are
+// registered, no error is returned for unknown emails.
+func (pu *PasswordUsecaseImpl) RequestReset(ctx context.Context, email string) error {
+	ctx, cancel := withTimeout(ctx, pu.timeout)
+	defer cancel()
+
+	user, err := pu.userRepo.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		return nil
+	}
+
+	uuid, err := GenerateUUID()
+	if err != nil {
+		return err
+	}
+
+	token, err := crypto.NewToken(resetTokenSize)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	reset := &models.PasswordReset{
+		ID:        uuid,
+		UserID:    user.ID,
+		TokenHash: crypto.HashToken(token),
+		IssuedOn:  now,
+		ExpiresOn: now.Add(pu.resetDuration),
+	}
+
+	err = pu.resetRepo.Store(ctx, reset)
+	if err != nil {
+		return err
+	}
+
+	message := &notify.Message{
+		To:      user.Email,
+		Subject: "Password reset",
+		Body: fmt.Sprintf(
+			"Use the following token to reset your password: %s\nThe token is valid until %s.",
+			token,
+			reset.ExpiresOn.Format(time.RFC1123),
+		),
+	}
+
+	return pu.notifier.Notify(ctx, message)
+}
+
+// Reset sets a new password for the user the given reset token was issued
+// to. Tokens can be used only once. All sessions of the user are revoked,
+// the updated user is returned.
+func (pu *PasswordUsecaseImpl) Reset(ctx context.Context, token, newPassword string) (*models.User, error) {
+	ctx, cancel := withTimeout(ctx, pu.timeout)
+	defer cancel()
+
+	token = strings.TrimSpace(token)
+	if len(token) <= 0 {
+		return nil, errResetTokenRequired
+	}
+
+	reset, err := pu.resetRepo.GetByTokenHash(ctx, crypto.HashToken(token))
+	if err != nil {
+		return nil, errInvalidResetToken
+	}
+
+	now := time.Now().UTC()
+	if !reset.IsUsable(now) {
+		return nil, errInvalidResetToken
+	}
+
+	user, err := pu.userRepo.GetByID(ctx, reset.UserID)
+	if err != nil {
+		return nil, orNotFound(err, errUserDoesNotExists)
+	}
+
+	// validate before using up the token, so that it can be retried
+	passwordHash, err := hashNewPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// the token is used up, and the password set, together or not at all
+	err = pu.resetRepo.Redeem(ctx, reset, now, "", passwordHash)
+	if err != nil {
+		return nil, orNotFound(err, errResetTokenWasUsed)
+	}
+
+	pu.userChanged(user.ID)
+
+	user.PasswordSalt = ""
+	user.PasswordHash = passwordHash
+	return user, nil
+}
+
+func (pu *PasswordUsecaseImpl) updatePassword(ctx context.Context, user *models.User, newPassword string) (*models.User, error) {
+	passwordHash, err := hashNewPassword(newPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	// the password is changed, and the sessions revoked, together or not at all
+	err = pu.userRepo.ChangePassword(ctx, user.ID, "", passwordHash, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	pu.userChanged(user.ID)
+
+	user.PasswordSalt = ""
+	user.PasswordHash = passwordHash
+	return user, nil
+}
+
+// hashNewPassword validates the given new password and returns its hash.
+func hashNewPassword(newPassword string) (string, error) {
+	newPassword = cleanPassword(newPassword)
+	err := validatePassword("/newPassword", newPassword)
+	if err != nil {
+		return "", err
+	}
+
+	return crypto.HashFromPassword(newPassword)
+}
@@ -0,0 +1,28 @@
+package impl
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// principalUser returns the user calling the usecase. If the caller is not an
+// user, usecases.ErrForbidden is returned.
+func principalUser(ctx context.Context) (*models.User, error) {
+	principal, _ := usecases.PrincipalFromContext(ctx)
+	if principal == nil || principal.User == nil {
+		return nil, usecases.ErrForbidden
+	}
+	return principal.User, nil
+}
+
+// canModify returns usecases.ErrForbidden unless the caller is the user with
+// the given ID or has one of the given roles.
+func canModify(ctx context.Context, ownerID string, roles ...string) error {
+	principal, _ := usecases.PrincipalFromContext(ctx)
+	if !principal.IsUser(ownerID) && !principal.HasRole(roles...) {
+		return usecases.ErrForbidden
+	}
+	return nil
+}
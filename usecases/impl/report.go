@@ -0,0 +1,88 @@
+package impl
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/reports"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+// maxReportYears is the longest range of a report, in years, so that a typo
+// doesn't scan every row of the tables.
+const maxReportYears = 10
+
+var errReportDoesNotExist = usecases.Errorf(usecases.KindNotFound, "report with the given name does not exist")
+
+// ReportUsecaseImpl implements the ReportUsecase interface.
+type ReportUsecaseImpl struct {
+	reportRepo repos.ReportRepository
+	timeout    time.Duration
+}
+
+// NewReportUsecaseImpl returns a new ReportUsecaseImpl instance.
+func NewReportUsecaseImpl(reportRepo repos.ReportRepository, timeout time.Duration) *ReportUsecaseImpl {
+	return &ReportUsecaseImpl{
+		reportRepo,
+		timeout,
+	}
+}
+
+// Fetch fetches every report.
+func (ru *ReportUsecaseImpl) Fetch(ctx context.Context) []*reports.Report {
+	return reports.All()
+}
+
+// Run runs the report with the given name over the given range, which must be
+// empty unless the report is ranged. A range with a single bound covers at
+// most maxReportYears from it, a range without bounds covers every month.
+func (ru *ReportUsecaseImpl) Run(ctx context.Context, name string, dateRange reports.Range) (interface{}, error) {
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	report, ok := reports.Get(name)
+	if !ok {
+		return nil, errReportDoesNotExist
+	}
+
+	err := validateReportRange(report, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return ru.reportRepo.Run(ctx, report, boundReportRange(dateRange))
+}
+
+// validateReportRange validates the range of a report, its pointers are the
+// names of the query parameters.
+func validateReportRange(report *reports.Report, dateRange reports.Range) error {
+	v := usecases.Validator{}
+	if !report.Ranged {
+		v.Check(!dateRange.Start.Valid, "/start", usecases.CodeInvalid, "%s doesn't take a range", report.Name)
+		v.Check(!dateRange.End.Valid, "/end", usecases.CodeInvalid, "%s doesn't take a range", report.Name)
+		return v.Err()
+	}
+
+	if dateRange.Start.Valid && dateRange.End.Valid {
+		start, end := dateRange.Start.Time, dateRange.End.Time
+		if v.Check(!end.Before(start), "/end", usecases.CodeOutOfRange, "end must not be before start") {
+			v.Check(!end.After(start.AddDate(maxReportYears, 0, 0)), "/end", usecases.CodeOutOfRange, "end must be at most %d years after start", maxReportYears)
+		}
+	}
+	return v.Err()
+}
+
+// boundReportRange returns the given range with its missing bound, if only
+// one is missing, set to maxReportYears from the other. A range without bounds
+// is left as is, covering every month on purpose.
+func boundReportRange(dateRange reports.Range) reports.Range {
+	switch {
+	case dateRange.Start.Valid && !dateRange.End.Valid:
+		dateRange.End = sql.NullTime{Time: dateRange.Start.Time.AddDate(maxReportYears, 0, 0), Valid: true}
+	case !dateRange.Start.Valid && dateRange.End.Valid:
+		dateRange.Start = sql.NullTime{Time: dateRange.End.Time.AddDate(-maxReportYears, 0, 0), Valid: true}
+	}
+	return dateRange
+}
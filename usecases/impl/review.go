@@ -2,55 +2,71 @@ package impl
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
-	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/mathutil"
-
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
 var (
-	errReviewExists        = fmt.Errorf("review with the given ID alredy exists")
-	errReviewDoesNotExists = fmt.Errorf("review with the given ID does not exists")
+	errReviewExists        = usecases.Errorf(usecases.KindConflict, "review with the given ID alredy exists")
+	errReviewDoesNotExists = usecases.Errorf(usecases.KindNotFound, "review with the given ID does not exists")
 )
 
 // ReviewUsecaseImpl implements the ReviewUsecase interface.
 type ReviewUsecaseImpl struct {
-	reviewRepo repos.ReviewRepository
-	rideRepo   repos.RideRepository
-	timeout    time.Duration
+	reviewRepo   repos.ReviewRepository
+	rideRepo     repos.RideRepository
+	auditUsecase usecases.AuditUsecase
+	timeout      time.Duration
 }
 
 // NewReviewUsecaseImpl returns a new ReviewUsecaseImpl instance.
-func NewReviewUsecaseImpl(reviewRepo repos.ReviewRepository, rideRepo repos.RideRepository, timeout time.Duration) *ReviewUsecaseImpl {
-	return &ReviewUsecaseImpl{reviewRepo, rideRepo, timeout}
+func NewReviewUsecaseImpl(reviewRepo repos.ReviewRepository, rideRepo repos.RideRepository, auditUsecase usecases.AuditUsecase, timeout time.Duration) *ReviewUsecaseImpl {
+	return &ReviewUsecaseImpl{reviewRepo, rideRepo, auditUsecase, timeout}
 }
 
 // GetByID returns a spcific review using the given ID.
 func (ru *ReviewUsecaseImpl) GetByID(ctx context.Context, reviewID string) (*models.Review, error) {
-	return ru.reviewRepo.GetByID(reviewID)
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	return ru.reviewRepo.GetByID(ctx, reviewID)
 }
 
 // Fetch fetches all the reviews from the repository.
 func (ru *ReviewUsecaseImpl) Fetch(ctx context.Context) ([]*models.Review, error) {
-	return ru.reviewRepo.Fetch()
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	return ru.reviewRepo.Fetch(ctx)
 }
 
 // FetchForRide fetches all reviews for the given ride.
 func (ru *ReviewUsecaseImpl) FetchForRide(ctx context.Context, rideID string) ([]*models.Review, error) {
-	_, err := ru.rideRepo.GetByID(rideID)
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	_, err := ru.rideRepo.GetByID(ctx, rideID)
 	if err != nil {
-		return nil, errRideDoesNotExists
+		return nil, orNotFound(err, errRideDoesNotExists)
 	}
-	return ru.reviewRepo.FetchForRideSortedByDate(rideID)
+	return ru.reviewRepo.FetchForRideSortedByDate(ctx, rideID)
 }
 
-// Store creates a new review.
+// Store creates a new review. The author is always the user in the context.
 func (ru *ReviewUsecaseImpl) Store(ctx context.Context, review *models.Review) error {
-	_, err := ru.reviewRepo.GetByID(review.ID)
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	author, err := principalUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = ru.reviewRepo.GetByID(ctx, review.ID)
 	if err == nil {
 		return errReviewExists
 	}
@@ -61,6 +77,7 @@ func (ru *ReviewUsecaseImpl) Store(ctx context.Context, review *models.Review) e
 	}
 
 	review.ID = ID
+	review.UserID = author.ID
 	review.PostedOn = time.Now().UTC()
 	cleanReview(review)
 	err = validateReview(review)
@@ -68,78 +85,88 @@ func (ru *ReviewUsecaseImpl) Store(ctx context.Context, review *models.Review) e
 		return err
 	}
 
-	err = ru.reviewRepo.Store(review)
+	err = ru.reviewRepo.Store(ctx, review)
 	if err != nil {
 		return err
 	}
 
+	ru.auditUsecase.Record(ctx, models.AuditCreate, auditReview, review.ID, nil, review)
 	return nil
 }
 
-// Update updates an existing review.
+// Update updates an existing review. Only the author or an employee can update
+// it, and neither the author nor the ride can be changed.
 func (ru *ReviewUsecaseImpl) Update(ctx context.Context, review *models.Review) error {
-	_, err := ru.reviewRepo.GetByID(review.ID)
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	before, err := ru.reviewRepo.GetByID(ctx, review.ID)
 	if err != nil {
-		return errReviewDoesNotExists
+		return orNotFound(err, errReviewDoesNotExists)
 	}
 
+	err = canModify(ctx, before.UserID, models.RoleEmployee)
+	if err != nil {
+		return err
+	}
+
+	review.UserID = before.UserID
+	review.RideID = before.RideID
 	cleanReview(review)
 	err = validateReview(review)
 	if err != nil {
 		return err
 	}
 
-	err = ru.reviewRepo.Update(review)
+	err = ru.reviewRepo.Update(ctx, review)
 	if err != nil {
 		return err
 	}
 
+	ru.auditUsecase.Record(ctx, models.AuditUpdate, auditReview, review.ID, before, review)
 	return nil
 }
 
-// Delete deletes a specific review.
+// Delete deletes a specific review. Only the author or an employee can delete
+// it.
 func (ru *ReviewUsecaseImpl) Delete(ctx context.Context, reviewID string) error {
-	_, err := ru.reviewRepo.GetByID(reviewID)
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	before, err := ru.reviewRepo.GetByID(ctx, reviewID)
 	if err != nil {
-		return errReviewDoesNotExists
+		return orNotFound(err, errReviewDoesNotExists)
 	}
-	return ru.reviewRepo.Delete(reviewID)
+
+	err = canModify(ctx, before.UserID, models.RoleEmployee)
+	if err != nil {
+		return err
+	}
+
+	err = ru.reviewRepo.Delete(ctx, reviewID)
+	if err != nil {
+		return err
+	}
+
+	ru.auditUsecase.Record(ctx, models.AuditDelete, auditReview, reviewID, before, nil)
+	return nil
 }
 
 func cleanReview(review *models.Review) {
 	review.ID = strings.TrimSpace(review.ID)
 	review.RideID = strings.TrimSpace(review.RideID)
 	review.UserID = strings.TrimSpace(review.UserID)
-	review.Rating = mathutil.ClampInt(review.Rating, 1, 5)
 	review.Title = strings.TrimSpace(review.Title)
 	review.Content = strings.TrimSpace(review.Content)
 }
 
 func validateReview(review *models.Review) error {
-
-	if len(review.ID) <= 0 {
-		return fmt.Errorf("validateReview: ID must be non-empty")
-	}
-
-	if len(review.RideID) <= 0 {
-		return fmt.Errorf("validateReview: RideID must be non-empty")
-	}
-
-	if len(review.UserID) <= 0 {
-		return fmt.Errorf("validateReview: UserID must be non-empty")
-	}
-
-	if !(review.Rating >= 1 && review.Rating <= 5) {
-		return fmt.Errorf("validateReview: Rating must be in the range [1, 5]")
-	}
-
-	if len(review.Title) <= 0 {
-		return fmt.Errorf("validateReview: Title must be non-empty")
-	}
-
-	if len(review.Content) <= 0 {
-		return fmt.Errorf("validateReview: Content must be non-empty")
-	}
-
-	return nil
+	v := usecases.Validator{}
+	v.Required("/id", review.ID)
+	v.Required("/rideId", review.RideID)
+	v.Required("/userId", review.UserID)
+	v.Range("/rating", float64(review.Rating), 1, 5)
+	v.Required("/title", review.Title)
+	v.Required("/content", review.Content)
+	return v.Err()
 }
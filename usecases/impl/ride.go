@@ -8,22 +8,24 @@ import (
 
 	"golang.org/x/sync/errgroup"
 
-	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/mathutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/logging"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
 var (
-	errRideExists        = fmt.Errorf("ride with the given ID already exists")
-	errRideDoesNotExists = fmt.Errorf("ride with he given ID does not exists")
+	errRideExists        = usecases.Errorf(usecases.KindConflict, "ride with the given ID already exists")
+	errRideDoesNotExists = usecases.Errorf(usecases.KindNotFound, "ride with he given ID does not exists")
 )
 
 // RideUsecaseImpl implements the RideUsecase interface.
 type RideUsecaseImpl struct {
-	rideRepo    repos.RideRepository
-	pictureRepo repos.PictureRepository
-	reviewRepo  repos.ReviewRepository
-	timeout     time.Duration
+	rideRepo     repos.RideRepository
+	pictureRepo  repos.PictureRepository
+	reviewRepo   repos.ReviewRepository
+	auditUsecase usecases.AuditUsecase
+	timeout      time.Duration
 }
 
 // NewRideUsecaseImpl returns a new RideUsecaseImpl instance. The timeout
@@ -32,32 +34,36 @@ func NewRideUsecaseImpl(
 	rideRepo repos.RideRepository,
 	pictureRepo repos.PictureRepository,
 	reviewRepo repos.ReviewRepository,
+	auditUsecase usecases.AuditUsecase,
 	timeout time.Duration) *RideUsecaseImpl {
 
 	return &RideUsecaseImpl{
 		rideRepo,
 		pictureRepo,
 		reviewRepo,
+		auditUsecase,
 		timeout,
 	}
 }
 
 // GetByID fetches ride from the repositories using the given ID.
 func (ru *RideUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.Ride, error) {
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
 
-	ride, err := ru.rideRepo.GetByID(ID)
+	ride, err := ru.rideRepo.GetByID(ctx, ID)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching ride: %s", err)
+		return nil, fmt.Errorf("error fetching ride: %w", err)
 	}
 
-	pictures, err := ru.pictureRepo.FetchByCollectionID(ride.ID)
+	pictures, err := ru.pictureRepo.FetchByCollectionID(ctx, ride.ID)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching ride pictures: %s", err)
+		return nil, fmt.Errorf("error fetching ride pictures: %w", err)
 	}
 
-	reviews, err := ru.reviewRepo.FetchForRideSortedByDate(ride.ID)
+	reviews, err := ru.reviewRepo.FetchForRideSortedByDate(ctx, ride.ID)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching ride reviews: %s", err)
+		return nil, fmt.Errorf("error fetching ride reviews: %w", err)
 	}
 
 	reviewsTotal := 0
@@ -77,15 +83,15 @@ func (ru *RideUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.Ride
 
 // Fetch fetches all rides from the repositories.
 func (ru *RideUsecaseImpl) Fetch(ctx context.Context) ([]*models.Ride, error) {
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
 
-	rides, err := ru.rideRepo.Fetch()
+	rides, err := ru.rideRepo.Fetch(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching rides: %s", err)
+		return nil, fmt.Errorf("error fetching rides: %w", err)
 	}
 
-	timeoutContext, cancel := context.WithTimeout(ctx, ru.timeout)
-	defer cancel()
-	eg, _ := errgroup.WithContext(timeoutContext)
+	eg, egCtx := errgroup.WithContext(ctx)
 
 	// the following section fetches reviews to calculate review averages
 
@@ -100,7 +106,7 @@ func (ru *RideUsecaseImpl) Fetch(ctx context.Context) ([]*models.Ride, error) {
 		rideID := ride.ID
 
 		eg.Go(func() error {
-			reviews, err := ru.reviewRepo.FetchForRideSortedByDate(rideID)
+			reviews, err := ru.reviewRepo.FetchForRideSortedByDate(egCtx, rideID)
 			if err != nil {
 				return err
 			}
@@ -121,13 +127,13 @@ func (ru *RideUsecaseImpl) Fetch(ctx context.Context) ([]*models.Ride, error) {
 
 	}
 
-	// close channels if error group returns from Wait
+	// close channels when the error group returns from Wait, even on error so
+	// that the loop below ends
 
 	go func() {
 		err := eg.Wait()
 		if err != nil {
-			// TODO: log error
-			return
+			logging.FromContext(ctx).Error("error fetching ride reviews", logging.Fields{"error": err})
 		}
 		close(chanReviewAverages)
 	}()
@@ -158,7 +164,10 @@ func (ru *RideUsecaseImpl) Fetch(ctx context.Context) ([]*models.Ride, error) {
 // Store creates a new ride in the repository if a ride with the same ID
 // doesn't exists already.
 func (ru *RideUsecaseImpl) Store(ctx context.Context, ride *models.Ride) error {
-	_, err := ru.rideRepo.GetByID(ride.ID)
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	_, err := ru.rideRepo.GetByID(ctx, ride.ID)
 	if err == nil {
 		return errRideExists
 	}
@@ -175,19 +184,23 @@ func (ru *RideUsecaseImpl) Store(ctx context.Context, ride *models.Ride) error {
 		return err
 	}
 
-	err = ru.rideRepo.Store(ride)
+	err = ru.rideRepo.Store(ctx, ride)
 	if err != nil {
 		return err
 	}
 
+	ru.auditUsecase.Record(ctx, models.AuditCreate, auditRide, ride.ID, nil, ride)
 	return nil
 }
 
 // Update updates an existing ride in the repository.
 func (ru *RideUsecaseImpl) Update(ctx context.Context, ride *models.Ride) error {
-	_, err := ru.rideRepo.GetByID(ride.ID)
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	before, err := ru.rideRepo.GetByID(ctx, ride.ID)
 	if err != nil {
-		return errRideDoesNotExists
+		return orNotFound(err, errRideDoesNotExists)
 	}
 
 	cleanRide(ride)
@@ -196,26 +209,31 @@ func (ru *RideUsecaseImpl) Update(ctx context.Context, ride *models.Ride) error
 		return err
 	}
 
-	err = ru.rideRepo.Update(ride)
+	err = ru.rideRepo.Update(ctx, ride)
 	if err != nil {
 		return err
 	}
 
+	ru.auditUsecase.Record(ctx, models.AuditUpdate, auditRide, ride.ID, before, ride)
 	return nil
 }
 
 // Delete deletes an existing ride from the repository.
 func (ru *RideUsecaseImpl) Delete(ctx context.Context, ID string) error {
-	_, err := ru.rideRepo.GetByID(ID)
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	before, err := ru.rideRepo.GetByID(ctx, ID)
 	if err != nil {
-		return errRideDoesNotExists
+		return orNotFound(err, errRideDoesNotExists)
 	}
 
-	err = ru.rideRepo.Delete(ID)
+	err = ru.rideRepo.Delete(ctx, ID)
 	if err != nil {
 		return err
 	}
 
+	ru.auditUsecase.Record(ctx, models.AuditDelete, auditRide, ID, before, nil)
 	return nil
 }
 
@@ -223,20 +241,18 @@ func cleanRide(ride *models.Ride) {
 	ride.ID = strings.TrimSpace(ride.ID)
 	ride.Name = strings.TrimSpace(ride.Name)
 	ride.Description = strings.TrimSpace(ride.Description)
-	ride.MinAge = mathutil.ClampInt(ride.MinAge, 0, 200)
-	ride.MinHeight = mathutil.ClampInt(ride.MinHeight, 0, 400)
-	ride.Longitude = mathutil.ClampFloat64(ride.Longitude, -180, 180)
-	ride.Latitude = mathutil.ClampFloat64(ride.Latitude, -90, 90)
 }
 
 func validateRide(ride *models.Ride) error {
-	if len(ride.ID) <= 0 {
-		return fmt.Errorf("validateRide: ID must be non-empty")
-	}
-
-	if len(ride.Name) <= 0 {
-		return fmt.Errorf("validateRide: name must be non-empty")
+	v := usecases.Validator{}
+	v.Required("/id", ride.ID)
+	if v.Required("/name", ride.Name) {
+		v.MaxLength("/name", ride.Name, 64)
 	}
-
-	return nil
+	v.MaxLength("/description", ride.Description, 512)
+	v.Range("/minAge", float64(ride.MinAge), 0, 200)
+	v.Range("/minHeight", float64(ride.MinHeight), 0, 400)
+	v.Range("/longitude", ride.Longitude, -180, 180)
+	v.Range("/latitude", ride.Latitude, -90, 90)
+	return v.Err()
 }
@@ -0,0 +1,83 @@
+package impl
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+var (
+	errRoleDoesNotExists = usecases.Errorf(usecases.KindNotFound, "role with the given ID does not exists")
+)
+
+// RoleUsecaseImpl implements the RoleUsecase interface.
+type RoleUsecaseImpl struct {
+	roleRepo     repos.RoleRepository
+	userRepo     repos.UserRepository
+	auditUsecase usecases.AuditUsecase
+	timeout      time.Duration
+	*userHooks
+}
+
+// NewRoleUsecaseImpl returns a new RoleUsecaseImpl instance.
+func NewRoleUsecaseImpl(
+	roleRepo repos.RoleRepository,
+	userRepo repos.UserRepository,
+	auditUsecase usecases.AuditUsecase,
+	timeout time.Duration) *RoleUsecaseImpl {
+
+	return &RoleUsecaseImpl{
+		roleRepo,
+		userRepo,
+		auditUsecase,
+		timeout,
+		&userHooks{},
+	}
+}
+
+// Fetch fetches all roles from the repositories.
+func (ru *RoleUsecaseImpl) Fetch(ctx context.Context) ([]*models.Role, error) {
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	return ru.roleRepo.Fetch(ctx)
+}
+
+// UpdateRequiresTwoFactor sets whether employees with the given role must use
+// two-factor authentication, and returns the updated role. Employees with the
+// role are notified as changed users.
+func (ru *RoleUsecaseImpl) UpdateRequiresTwoFactor(ctx context.Context, ID string, requiresTwoFactor bool) (*models.Role, error) {
+	ctx, cancel := withTimeout(ctx, ru.timeout)
+	defer cancel()
+
+	before, err := ru.roleRepo.GetByID(ctx, ID)
+	if err != nil {
+		return nil, orNotFound(err, errRoleDoesNotExists)
+	}
+
+	err = ru.roleRepo.UpdateRequiresTwoFactor(ctx, ID, requiresTwoFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := ru.roleRepo.GetByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	ru.auditUsecase.Record(ctx, models.AuditUpdate, auditRole, role.ID, before, role)
+
+	employees, _, err := ru.userRepo.Fetch(ctx, &models.UserFilter{Role: role.Role}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, employee := range employees {
+		ru.userChanged(employee.ID)
+	}
+
+	return role, nil
+}
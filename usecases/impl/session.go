@@ -0,0 +1,130 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+var (
+	errSessionDoesNotExists = usecases.Errorf(usecases.KindNotFound, "session with the given ID does not exists")
+)
+
+// SessionUsecaseImpl implements the SessionUsecase interface.
+type SessionUsecaseImpl struct {
+	sessionRepo repos.SessionRepository
+	userRepo    repos.UserRepository
+	timeout     time.Duration
+}
+
+// NewSessionUsecaseImpl returns a new SessionUsecaseImpl instance.
+func NewSessionUsecaseImpl(sessionRepo repos.SessionRepository, userRepo repos.UserRepository, timeout time.Duration) *SessionUsecaseImpl {
+	return &SessionUsecaseImpl{
+		sessionRepo,
+		userRepo,
+		timeout,
+	}
+}
+
+// GetByID fetches a session using the given ID.
+func (su *SessionUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.Session, error) {
+	ctx, cancel := withTimeout(ctx, su.timeout)
+	defer cancel()
+
+	session, err := su.sessionRepo.GetByID(ctx, ID)
+	if err != nil {
+		return nil, orNotFound(err, errSessionDoesNotExists)
+	}
+
+	return session, nil
+}
+
+// FetchActiveForUser fetches the sessions for the given user that have not
+// expired nor been revoked.
+func (su *SessionUsecaseImpl) FetchActiveForUser(ctx context.Context, userID string) ([]*models.Session, error) {
+	ctx, cancel := withTimeout(ctx, su.timeout)
+	defer cancel()
+
+	_, err := su.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, orNotFound(err, errUserDoesNotExists)
+	}
+
+	return su.sessionRepo.FetchActiveForUser(ctx, userID, time.Now().UTC())
+}
+
+// Store creates a new session. The session ID and issue time are generated,
+// the expiration time must be set by the caller.
+func (su *SessionUsecaseImpl) Store(ctx context.Context, session *models.Session) error {
+	ctx, cancel := withTimeout(ctx, su.timeout)
+	defer cancel()
+
+	uuid, err := GenerateUUID()
+	if err != nil {
+		return err
+	}
+
+	session.ID = uuid
+	session.IssuedOn = time.Now().UTC().Truncate(time.Second)
+	session.RevokedOn = models.NullTime{}
+	cleanSession(session)
+	err = validateSession(session)
+	if err != nil {
+		return err
+	}
+
+	err = su.sessionRepo.Store(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Revoke revokes an existing session.
+func (su *SessionUsecaseImpl) Revoke(ctx context.Context, ID string) error {
+	ctx, cancel := withTimeout(ctx, su.timeout)
+	defer cancel()
+
+	_, err := su.sessionRepo.GetByID(ctx, ID)
+	if err != nil {
+		return orNotFound(err, errSessionDoesNotExists)
+	}
+
+	return su.sessionRepo.Revoke(ctx, ID, time.Now().UTC())
+}
+
+// RevokeForUser revokes all the sessions for the given user.
+func (su *SessionUsecaseImpl) RevokeForUser(ctx context.Context, userID string) error {
+	ctx, cancel := withTimeout(ctx, su.timeout)
+	defer cancel()
+
+	_, err := su.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return orNotFound(err, errUserDoesNotExists)
+	}
+
+	return su.sessionRepo.RevokeForUser(ctx, userID, time.Now().UTC())
+}
+
+func cleanSession(session *models.Session) {
+	session.ID = strings.TrimSpace(session.ID)
+	session.UserID = strings.TrimSpace(session.UserID)
+	session.UserAgent.String = strings.TrimSpace(session.UserAgent.String)
+	session.Address.String = strings.TrimSpace(session.Address.String)
+
+	// NOTE: user agents are client provided and can be of any length.
+	session.UserAgent.String = truncate(session.UserAgent.String, 256)
+}
+
+func validateSession(session *models.Session) error {
+	v := usecases.Validator{}
+	v.Required("/id", session.ID)
+	v.Required("/userId", session.UserID)
+	v.Check(session.IssuedOn.Before(session.ExpiresOn), "/expiresOn", usecases.CodeOutOfRange, "expiresOn must be after issuedOn")
+	return v.Err()
+}
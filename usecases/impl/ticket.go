@@ -2,89 +2,126 @@ package impl
 
 import (
 	"context"
-	"fmt"
 	"strings"
 	"time"
 
-	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/mathutil"
-
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/metrics"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
 var (
-	errTicketExists        = fmt.Errorf("ticket with the given ID already exists")
-	errTicketDoesNotExists = fmt.Errorf("ticket with the given ID does not exists")
+	errTicketExists        = usecases.Errorf(usecases.KindConflict, "ticket with the given ID already exists")
+	errTicketDoesNotExists = usecases.Errorf(usecases.KindNotFound, "ticket with the given ID does not exists")
+)
+
+// Prices of the tickets bought by customers, for adults and for kids. Only
+// supervisors can change the price of a ticket, see Update.
+const (
+	ticketPrice    = 50.0
+	kidTicketPrice = 25.0
 )
 
 // TicketUsecaseImpl implements the TicketUsecase interface.
 type TicketUsecaseImpl struct {
-	ticketRepo repos.TicketRepository
-	rideRepo   repos.RideRepository
-	userRepo   repos.UserRepository
+	ticketRepo   repos.TicketRepository
+	rideRepo     repos.RideRepository
+	userRepo     repos.UserRepository
+	auditUsecase usecases.AuditUsecase
+	timeout      time.Duration
 }
 
 // NewTicketUsecaseImpl returns a new TicketUsecaseImpl instance.
-func NewTicketUsecaseImpl(ticketRepo repos.TicketRepository, rideRepo repos.RideRepository, userRepo repos.UserRepository) *TicketUsecaseImpl {
-	return &TicketUsecaseImpl{ticketRepo, rideRepo, userRepo}
+func NewTicketUsecaseImpl(ticketRepo repos.TicketRepository, rideRepo repos.RideRepository, userRepo repos.UserRepository, auditUsecase usecases.AuditUsecase, timeout time.Duration) *TicketUsecaseImpl {
+	return &TicketUsecaseImpl{ticketRepo, rideRepo, userRepo, auditUsecase, timeout}
 }
 
 // GetByID fetches a ticket with the given ID from the repository.
 func (tu *TicketUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.Ticket, error) {
-	ticket, err := tu.ticketRepo.GetByID(ID)
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	ticket, err := tu.ticketRepo.GetByID(ctx, ID)
 	if err != nil {
-		return nil, errTicketDoesNotExists
+		return nil, orNotFound(err, errTicketDoesNotExists)
 	}
 	return ticket, nil
 }
 
-// Fetch fetches all the tickets.
-func (tu *TicketUsecaseImpl) Fetch(ctx context.Context) ([]*models.Ticket, error) {
-	return tu.ticketRepo.Fetch()
-}
+// Fetch fetches a page of the tickets matching the given filter. Filtering on
+// an user that does not exist is a not found error.
+func (tu *TicketUsecaseImpl) Fetch(ctx context.Context, filter *models.TicketFilter, page *models.PageRequest) ([]*models.Ticket, string, error) {
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
 
-// FetchForUser fetches all the tickets for the given user.
-func (tu *TicketUsecaseImpl) FetchForUser(ctx context.Context, userID string) ([]*models.Ticket, error) {
-	_, err := tu.userRepo.GetByID(userID)
+	v := &usecases.Validator{}
+	validateList(v, page, filter.Since, filter.Until)
+	err := v.Err()
 	if err != nil {
-		return nil, errUserDoesNotExists
+		return nil, "", err
+	}
+
+	if len(filter.UserID) > 0 {
+		_, err := tu.userRepo.GetByID(ctx, filter.UserID)
+		if err != nil {
+			return nil, "", orNotFound(err, errUserDoesNotExists)
+		}
 	}
-	return tu.ticketRepo.FetchForUser(userID)
-}
 
-// FetchScans fetches all scans.
-func (tu *TicketUsecaseImpl) FetchScans(ctx context.Context) ([]*models.TicketScan, error) {
-	return tu.ticketRepo.FetchScans()
+	return tu.ticketRepo.Fetch(ctx, filter, page)
 }
 
-// FetchScansForUser fetches all scans for the given user.
-func (tu *TicketUsecaseImpl) FetchScansForUser(ctx context.Context, userID string) ([]*models.TicketScan, error) {
-	_, err := tu.userRepo.GetByID(userID)
+// FetchScans fetches a page of the scans matching the given filter. Filtering
+// on an user or a ride that does not exist is a not found error.
+func (tu *TicketUsecaseImpl) FetchScans(ctx context.Context, filter *models.TicketScanFilter, page *models.PageRequest) ([]*models.TicketScan, string, error) {
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	v := &usecases.Validator{}
+	validateList(v, page, filter.Since, filter.Until)
+	err := v.Err()
 	if err != nil {
-		return nil, errUserDoesNotExists
+		return nil, "", err
 	}
-	return tu.ticketRepo.FetchScansForUser(userID)
-}
 
-// FetchScansForRide fetches all scans for the given ride.
-func (tu *TicketUsecaseImpl) FetchScansForRide(ctx context.Context, rideID string) ([]*models.TicketScan, error) {
-	_, err := tu.rideRepo.GetByID(rideID)
-	if err != nil {
-		return nil, errRideDoesNotExists
+	if len(filter.UserID) > 0 {
+		_, err := tu.userRepo.GetByID(ctx, filter.UserID)
+		if err != nil {
+			return nil, "", orNotFound(err, errUserDoesNotExists)
+		}
+	}
+
+	if len(filter.RideID) > 0 {
+		_, err := tu.rideRepo.GetByID(ctx, filter.RideID)
+		if err != nil {
+			return nil, "", orNotFound(err, errRideDoesNotExists)
+		}
 	}
-	return tu.ticketRepo.FetchScansForRide(rideID)
+
+	return tu.ticketRepo.FetchScans(ctx, filter, page)
 }
 
-// Store creates a new Ticket.
+// Store creates a new Ticket. The buyer is always the user in the context, and
+// the price is set from whether the ticket is for a kid, whatever was given.
 func (tu *TicketUsecaseImpl) Store(ctx context.Context, ticket *models.Ticket) error {
-	_, err := tu.ticketRepo.GetByID(ticket.ID)
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	buyer, err := principalUser(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = tu.ticketRepo.GetByID(ctx, ticket.ID)
 	if err == nil {
 		return errTicketExists
 	}
 
-	_, err = tu.userRepo.GetByID(ticket.UserID)
+	ticket.UserID = buyer.ID
+	_, err = tu.userRepo.GetByID(ctx, ticket.UserID)
 	if err != nil {
-		return errUserDoesNotExists
+		return orNotFound(err, errUserDoesNotExists)
 	}
 
 	uuid, err := GenerateUUID()
@@ -93,6 +130,7 @@ func (tu *TicketUsecaseImpl) Store(ctx context.Context, ticket *models.Ticket) e
 	}
 
 	ticket.ID = uuid
+	ticket.PurchasePrice = priceOf(ticket)
 	ticket.PurchasedOn = time.Now().UTC()
 	ticket.IsValid = true
 	cleanTicket(ticket)
@@ -101,24 +139,29 @@ func (tu *TicketUsecaseImpl) Store(ctx context.Context, ticket *models.Ticket) e
 		return err
 	}
 
-	err = tu.ticketRepo.Store(ticket)
+	err = tu.ticketRepo.Store(ctx, ticket)
 	if err != nil {
 		return err
 	}
 
+	tu.auditUsecase.Record(ctx, models.AuditCreate, auditTicket, ticket.ID, nil, ticket)
+	metrics.TicketsSold.Inc()
 	return nil
 }
 
 // Update updates an existing ticket.
 func (tu *TicketUsecaseImpl) Update(ctx context.Context, ticket *models.Ticket) error {
-	_, err := tu.ticketRepo.GetByID(ticket.ID)
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	before, err := tu.ticketRepo.GetByID(ctx, ticket.ID)
 	if err != nil {
-		return errTicketDoesNotExists
+		return orNotFound(err, errTicketDoesNotExists)
 	}
 
-	_, err = tu.userRepo.GetByID(ticket.UserID)
+	_, err = tu.userRepo.GetByID(ctx, ticket.UserID)
 	if err != nil {
-		return errUserDoesNotExists
+		return orNotFound(err, errUserDoesNotExists)
 	}
 
 	cleanTicket(ticket)
@@ -127,40 +170,47 @@ func (tu *TicketUsecaseImpl) Update(ctx context.Context, ticket *models.Ticket)
 		return err
 	}
 
-	err = tu.ticketRepo.Update(ticket)
+	err = tu.ticketRepo.Update(ctx, ticket)
 	if err != nil {
 		return err
 	}
 
+	tu.auditUsecase.Record(ctx, models.AuditUpdate, auditTicket, ticket.ID, before, ticket)
 	return nil
 }
 
 // Delete deletes an existing ticket.
 func (tu *TicketUsecaseImpl) Delete(ctx context.Context, ID string) error {
-	_, err := tu.ticketRepo.GetByID(ID)
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	before, err := tu.ticketRepo.GetByID(ctx, ID)
 	if err != nil {
-		return errTicketDoesNotExists
+		return orNotFound(err, errTicketDoesNotExists)
 	}
 
-	err = tu.ticketRepo.Delete(ID)
+	err = tu.ticketRepo.Delete(ctx, ID)
 	if err != nil {
 		return err
 	}
 
+	tu.auditUsecase.Record(ctx, models.AuditDelete, auditTicket, ID, before, nil)
 	return nil
 }
 
 // ScanTicket creates a new ticket scan and returns the created object.
 func (tu *TicketUsecaseImpl) ScanTicket(ctx context.Context, ticketID string, rideID string) (*models.TicketScan, error) {
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
 
-	_, err := tu.ticketRepo.GetByID(ticketID)
+	_, err := tu.ticketRepo.GetByID(ctx, ticketID)
 	if err != nil {
-		return nil, errTicketDoesNotExists
+		return nil, orNotFound(err, errTicketDoesNotExists)
 	}
 
-	_, err = tu.rideRepo.GetByID(rideID)
+	_, err = tu.rideRepo.GetByID(ctx, rideID)
 	if err != nil {
-		return nil, errRideDoesNotExists
+		return nil, orNotFound(err, errRideDoesNotExists)
 	}
 
 	uuid, err := GenerateUUID()
@@ -175,33 +225,42 @@ func (tu *TicketUsecaseImpl) ScanTicket(ctx context.Context, ticketID string, ri
 		ScanOn:   time.Now().UTC(),
 	}
 
-	err = tu.ticketRepo.StoreScan(&scan)
+	principal, _ := usecases.PrincipalFromContext(ctx)
+	if principal != nil && principal.Device != nil {
+		scan.DeviceID = models.NewNullString(principal.Device.ID)
+	}
+
+	err = tu.ticketRepo.StoreScan(ctx, &scan)
 	if err != nil {
 		return nil, err
 	}
 
+	tu.auditUsecase.Record(ctx, models.AuditScan, auditTicketScan, scan.ID, nil, &scan)
+	metrics.TicketScans.WithLabelValues(rideID).Inc()
 	return &scan, nil
 }
 
+// priceOf returns the price customers pay for the given ticket.
+func priceOf(ticket *models.Ticket) float64 {
+	if ticket.IsKid {
+		return kidTicketPrice
+	}
+	return ticketPrice
+}
+
 func cleanTicket(ticket *models.Ticket) {
 	ticket.ID = strings.TrimSpace(ticket.ID)
 	ticket.UserID = strings.TrimSpace(ticket.UserID)
-	ticket.PurchasePrice = mathutil.ClampFloat64(ticket.PurchasePrice, 0, 1000)
 	ticket.PurchaseReference = strings.TrimSpace(ticket.PurchaseReference)
 }
 
 func validateTicket(ticket *models.Ticket) error {
-	if len(ticket.ID) <= 0 {
-		return fmt.Errorf("validateTicket: ID must be non-empty")
-	}
-
-	if len(ticket.UserID) <= 0 {
-		return fmt.Errorf("validateTicket: UserID must be non-empty")
+	v := usecases.Validator{}
+	v.Required("/id", ticket.ID)
+	v.Required("/userId", ticket.UserID)
+	v.Range("/purchasePrice", ticket.PurchasePrice, 0, 1000)
+	if v.Required("/purchaseReference", ticket.PurchaseReference) {
+		v.MaxLength("/purchaseReference", ticket.PurchaseReference, 64)
 	}
-
-	if len(ticket.PurchaseReference) <= 0 {
-		return fmt.Errorf("validateTicket: PurchaseReference must be non-empty")
-	}
-
-	return nil
+	return v.Err()
 }
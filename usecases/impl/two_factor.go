@@ -0,0 +1,291 @@
+package impl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+var (
+	errTwoFactorEnabled     = usecases.Errorf(usecases.KindConflict, "two-factor authentication is already enabled")
+	errTwoFactorNotEnabled  = usecases.Errorf(usecases.KindConflict, "two-factor authentication is not enabled")
+	errTwoFactorNotEnrolled = usecases.Errorf(usecases.KindConflict, "two-factor authentication was not set up")
+	errTwoFactorRequired    = usecases.Errorf(usecases.KindForbidden, "two-factor authentication is required for your role")
+	errInvalidTwoFactorCode = usecases.Errorf(usecases.KindValidation, "two-factor code is not valid or was already used")
+)
+
+const (
+	// twoFactorIssuer is the name shown by authenticator apps.
+	twoFactorIssuer = "Theme Park"
+
+	// twoFactorSkew is the number of time steps (30 seconds each) accepted
+	// before and after the current one, to allow for clock drift.
+	twoFactorSkew = 1
+
+	// recoveryCodeCount is the number of recovery codes given to users.
+	recoveryCodeCount = 10
+)
+
+// TwoFactorUsecaseImpl implements the TwoFactorUsecase interface.
+type TwoFactorUsecaseImpl struct {
+	twoFactorRepo repos.TwoFactorRepository
+	userRepo      repos.UserRepository
+	auditUsecase  usecases.AuditUsecase
+	timeout       time.Duration
+	*userHooks
+}
+
+// NewTwoFactorUsecaseImpl returns a new TwoFactorUsecaseImpl instance.
+func NewTwoFactorUsecaseImpl(
+	twoFactorRepo repos.TwoFactorRepository,
+	userRepo repos.UserRepository,
+	auditUsecase usecases.AuditUsecase,
+	timeout time.Duration) *TwoFactorUsecaseImpl {
+
+	return &TwoFactorUsecaseImpl{
+		twoFactorRepo,
+		userRepo,
+		auditUsecase,
+		timeout,
+		&userHooks{},
+	}
+}
+
+// Enroll starts the two-factor authentication set up for the given user. It
+// is not enabled until confirmed with Enable. Enrolling again replaces the
+// pending secret.
+func (tu *TwoFactorUsecaseImpl) Enroll(ctx context.Context, userID string) (*models.TwoFactorEnrollment, error) {
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	user, err := tu.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, orNotFound(err, errUserDoesNotExists)
+	}
+
+	twoFactor, err := tu.twoFactorRepo.GetByUserID(ctx, userID)
+	if err == nil && twoFactor.IsEnabled() {
+		return nil, errTwoFactorEnabled
+	}
+
+	secret, err := crypto.NewTOTPSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	twoFactor = &models.TwoFactor{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedOn: time.Now().UTC(),
+	}
+
+	err = tu.twoFactorRepo.Store(ctx, twoFactor)
+	if err != nil {
+		return nil, err
+	}
+
+	enrollment := &models.TwoFactorEnrollment{
+		Secret: secret,
+		URI:    crypto.TOTPURI(secret, twoFactorIssuer, user.Email),
+	}
+
+	return enrollment, nil
+}
+
+// Enable enables the pending two-factor authentication of the given user if
+// the given TOTP code is valid, and returns new recovery codes. Recovery codes
+// are only returned here and when regenerating them, only their hashes are
+// stored.
+func (tu *TwoFactorUsecaseImpl) Enable(ctx context.Context, userID, code string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	twoFactor, err := tu.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errTwoFactorNotEnrolled
+	}
+
+	if twoFactor.IsEnabled() {
+		return nil, errTwoFactorEnabled
+	}
+
+	err = tu.verifyTOTP(ctx, twoFactor, code)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tu.twoFactorRepo.Enable(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := tu.storeRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tu.auditUsecase.Record(ctx, models.AuditCreate, auditTwoFactor, userID, nil, nil)
+	tu.userChanged(userID)
+	return recoveryCodes, nil
+}
+
+// Disable removes the two-factor authentication of the given user. Users need
+// a valid code for disabling their own, and can't disable it if their role
+// requires it. Supervisors can disable it for other users without a code,
+// e.g. when they lose their device.
+func (tu *TwoFactorUsecaseImpl) Disable(ctx context.Context, userID, code string) error {
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	user, err := tu.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return orNotFound(err, errUserDoesNotExists)
+	}
+
+	twoFactor, err := tu.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return errTwoFactorNotEnabled
+	}
+
+	principal, _ := usecases.PrincipalFromContext(ctx)
+	if principal.IsUser(userID) {
+		if user.RequiresTwoFactor {
+			return errTwoFactorRequired
+		}
+
+		if twoFactor.IsEnabled() {
+			err = tu.verify(ctx, twoFactor, code)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		err = canModify(ctx, userID, models.RoleSupervisor)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tu.twoFactorRepo.Delete(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	tu.auditUsecase.Record(ctx, models.AuditDelete, auditTwoFactor, userID, nil, nil)
+	tu.userChanged(userID)
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes of the given user if the
+// given code is valid, and returns the new ones.
+func (tu *TwoFactorUsecaseImpl) RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error) {
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	err := tu.Verify(ctx, userID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	recoveryCodes, err := tu.storeRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tu.auditUsecase.Record(ctx, models.AuditUpdate, auditTwoFactor, userID, nil, nil)
+	return recoveryCodes, nil
+}
+
+// Verify returns an error unless the given code is a valid TOTP code or an
+// unused recovery code for the given user. Either can only be used once.
+func (tu *TwoFactorUsecaseImpl) Verify(ctx context.Context, userID, code string) error {
+	ctx, cancel := withTimeout(ctx, tu.timeout)
+	defer cancel()
+
+	twoFactor, err := tu.twoFactorRepo.GetByUserID(ctx, userID)
+	if err != nil || !twoFactor.IsEnabled() {
+		return errTwoFactorNotEnabled
+	}
+
+	return tu.verify(ctx, twoFactor, code)
+}
+
+func (tu *TwoFactorUsecaseImpl) verify(ctx context.Context, twoFactor *models.TwoFactor, code string) error {
+	code = strings.TrimSpace(code)
+	if isTOTPCode(code) {
+		return tu.verifyTOTP(ctx, twoFactor, code)
+	}
+
+	codeHash := crypto.HashToken(crypto.NormalizeRecoveryCode(code))
+	err := tu.twoFactorRepo.UseRecoveryCode(ctx, twoFactor.UserID, codeHash, time.Now().UTC())
+	if err != nil {
+		return errInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (tu *TwoFactorUsecaseImpl) verifyTOTP(ctx context.Context, twoFactor *models.TwoFactor, code string) error {
+	step, ok := crypto.VerifyTOTP(twoFactor.Secret, code, time.Now().UTC(), twoFactorSkew)
+	if !ok {
+		return errInvalidTwoFactorCode
+	}
+
+	// fails if the code (or a newer one) was already used
+	err := tu.twoFactorRepo.UpdateLastStep(ctx, twoFactor.UserID, step)
+	if err != nil {
+		return errInvalidTwoFactorCode
+	}
+
+	return nil
+}
+
+func (tu *TwoFactorUsecaseImpl) storeRecoveryCodes(ctx context.Context, userID string) ([]string, error) {
+	recoveryCodes := make([]string, 0, recoveryCodeCount)
+	codes := make([]*models.RecoveryCode, 0, recoveryCodeCount)
+
+	for i := 0; i < recoveryCodeCount; i++ {
+		uuid, err := GenerateUUID()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCode, err := crypto.NewRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+
+		recoveryCodes = append(recoveryCodes, recoveryCode)
+		codes = append(codes, &models.RecoveryCode{
+			ID:       uuid,
+			UserID:   userID,
+			CodeHash: crypto.HashToken(crypto.NormalizeRecoveryCode(recoveryCode)),
+		})
+	}
+
+	err := tu.twoFactorRepo.StoreRecoveryCodes(ctx, userID, codes)
+	if err != nil {
+		return nil, err
+	}
+
+	return recoveryCodes, nil
+}
+
+func isTOTPCode(code string) bool {
+	if len(code) != 6 {
+		return false
+	}
+
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+
+	return true
+}
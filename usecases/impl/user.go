@@ -6,42 +6,64 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode"
 
 	//"golang.org/x/sync/errgroup"
 
-	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/internal/mathutil"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/crypto"
 	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
 	repos "gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/repositories"
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
 )
 
 var (
-	errUserExists        = fmt.Errorf("user with the given ID already exists")
-	errUserDoesNotExists = fmt.Errorf("user with he given ID does not exists")
+	errUserExists        = usecases.Errorf(usecases.KindConflict, "user with the given ID already exists")
+	errUserDoesNotExists = usecases.Errorf(usecases.KindNotFound, "user with he given ID does not exists")
+	errEmailExists       = usecases.Errorf(usecases.KindConflict, "user with the given email already exists")
+)
+
+const (
+	minPasswordLength = 8
+	maxPasswordLength = 72 // bcrypt ignores anything after 72 bytes
+)
+
+var (
+	validEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
+	validName  = regexp.MustCompile(`^[A-z]`)
+	validPhone = regexp.MustCompile(`^[0-9]+$`)
 )
 
 // UserUsecaseImpl implements the UserUsecase interface.
 type UserUsecaseImpl struct {
-	userRepo repos.UserRepository
-	timeout  time.Duration
+	userRepo     repos.UserRepository
+	auditUsecase usecases.AuditUsecase
+	timeout      time.Duration
+	*userHooks
 }
 
 // NewUserUsecaseImpl returns a new UserUsecaseImpl instance. The timeout
 // parameter specifies a duration for each request before throwing and error.
 func NewUserUsecaseImpl(
 	userRepo repos.UserRepository,
+	auditUsecase usecases.AuditUsecase,
 	timeout time.Duration) *UserUsecaseImpl {
 
 	return &UserUsecaseImpl{
 		userRepo,
+		auditUsecase,
 		timeout,
+		&userHooks{},
 	}
 }
 
 // GetByID fetches user from the repositories using the given ID.
 func (uu *UserUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.User, error) {
-	user, err := uu.userRepo.GetByID(ID)
+	ctx, cancel := withTimeout(ctx, uu.timeout)
+	defer cancel()
+
+	user, err := uu.userRepo.GetByID(ctx, ID)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching user: %s", err)
+		return nil, fmt.Errorf("error fetching user: %w", err)
 	}
 
 	return user, nil
@@ -49,48 +71,40 @@ func (uu *UserUsecaseImpl) GetByID(ctx context.Context, ID string) (*models.User
 
 // GetByEmail fetches user from the repositories using the given email.
 func (uu *UserUsecaseImpl) GetByEmail(ctx context.Context, email string) (*models.User, error) {
-	user, err := uu.userRepo.GetByEmail(email)
+	ctx, cancel := withTimeout(ctx, uu.timeout)
+	defer cancel()
+
+	user, err := uu.userRepo.GetByEmail(ctx, email)
 	if err != nil {
-		return nil, fmt.Errorf("error fetching user: %s", err)
+		return nil, fmt.Errorf("error fetching user: %w", err)
 	}
 
 	return user, nil
 }
 
-// Fetch fetches all Users from the repositories.
-func (uu *UserUsecaseImpl) Fetch(ctx context.Context) ([]*models.User, error) {
-	allUser, err := uu.userRepo.Fetch()
-	if err != nil {
-		return nil, err
-	}
-
-	return allUser, nil
-}
+// Fetch fetches a page of the users matching the given filter.
+func (uu *UserUsecaseImpl) Fetch(ctx context.Context, filter *models.UserFilter, page *models.PageRequest) ([]*models.User, string, error) {
+	ctx, cancel := withTimeout(ctx, uu.timeout)
+	defer cancel()
 
-// FetchCustomers fetches all Customers from the repositories.
-func (uu *UserUsecaseImpl) FetchCustomers(ctx context.Context) ([]*models.User, error) {
-	allCustomer, err := uu.userRepo.FetchCustomers()
+	v := &usecases.Validator{}
+	validateList(v, page, models.NullTime{}, models.NullTime{})
+	err := v.Err()
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	return allCustomer, nil
-}
-
-// FetchEmployees fetches all Employees from the repositories.
-func (uu *UserUsecaseImpl) FetchEmployees(ctx context.Context) ([]*models.User, error) {
-	allEmployee, err := uu.userRepo.FetchEmployees()
-	if err != nil {
-		return nil, err
-	}
-
-	return allEmployee, nil
+	return uu.userRepo.Fetch(ctx, filter, page)
 }
 
 // Store creates a new user in the repository if a user with the same ID
-// doesn't exists already.
-func (uu *UserUsecaseImpl) Store(ctx context.Context, user *models.User) error {
-	_, err := uu.userRepo.GetByID(user.ID)
+// or email doesn't exists already. The given plain password is hashed, any
+// password hash or salt set in the user is ignored.
+func (uu *UserUsecaseImpl) Store(ctx context.Context, user *models.User, password string) error {
+	ctx, cancel := withTimeout(ctx, uu.timeout)
+	defer cancel()
+
+	_, err := uu.userRepo.GetByID(ctx, user.ID)
 	if err == nil {
 		return errUserExists
 	}
@@ -112,19 +126,54 @@ func (uu *UserUsecaseImpl) Store(ctx context.Context, user *models.User) error {
 		return err
 	}
 
-	err = uu.userRepo.Store(user)
+	_, err = uu.userRepo.GetByEmail(ctx, user.Email)
+	if err == nil {
+		return errEmailExists
+	}
+
+	password = cleanPassword(password)
+	err = validatePassword("/password", password)
+	if err != nil {
+		return err
+	}
+
+	passwordHash, err := crypto.HashFromPassword(password)
 	if err != nil {
 		return err
 	}
 
+	user.PasswordSalt = ""
+	user.PasswordHash = passwordHash
+
+	err = uu.userRepo.Store(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	uu.auditUsecase.Record(ctx, models.AuditCreate, auditUser, user.ID, nil, user)
 	return nil
 }
 
+// Register is like Store, but always creates a customer. Used for
+// self-service registration.
+func (uu *UserUsecaseImpl) Register(ctx context.Context, user *models.User, password string) error {
+	ctx, cancel := withTimeout(ctx, uu.timeout)
+	defer cancel()
+
+	user.IsEmployee = false
+	user.Role = models.NullString{}
+	user.HourlyRate = 0
+	return uu.Store(ctx, user, password)
+}
+
 // Update updates an existing user in the repository.
 func (uu *UserUsecaseImpl) Update(ctx context.Context, user *models.User) error {
-	_, err := uu.userRepo.GetByID(user.ID)
+	ctx, cancel := withTimeout(ctx, uu.timeout)
+	defer cancel()
+
+	before, err := uu.userRepo.GetByID(ctx, user.ID)
 	if err != nil {
-		return errUserDoesNotExists
+		return orNotFound(err, errUserDoesNotExists)
 	}
 
 	cleanUser(user)
@@ -137,11 +186,13 @@ func (uu *UserUsecaseImpl) Update(ctx context.Context, user *models.User) error
 		return err
 	}
 
-	err = uu.userRepo.Update(user)
+	err = uu.userRepo.Update(ctx, user)
 	if err != nil {
 		return err
 	}
 
+	uu.auditUsecase.Record(ctx, models.AuditUpdate, auditUser, user.ID, before, user)
+	uu.userChanged(user.ID)
 	return nil
 }
 
@@ -157,55 +208,54 @@ func cleanUser(user *models.User) {
 	user.Address.String = strings.TrimSpace(user.Address.String)
 }
 
+func cleanPassword(password string) string {
+	return strings.TrimSpace(password)
+}
+
 func cleanEmployee(employee *models.User) {
 	employee.Role.String = strings.TrimSpace(employee.Role.String)
-	employee.HourlyRate = mathutil.ClampFloat32(employee.HourlyRate, 0, 500)
+
+	// NOTE: the hourly rate comes from the role and isn't stored with the
+	// user, so it's neither cleaned nor validated here.
 }
 
 func validateUser(user *models.User) error {
-	var validEmail = regexp.MustCompile("^[a-zA-Z0-9.!#$%&'*+/=?^_`{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*$")
-	var validName = regexp.MustCompile(`^[A-z]`)
-	var validPhone = regexp.MustCompile(`^[0-9]+$`)
-
-	if len(user.ID) <= 0 {
-		return fmt.Errorf("validateUser: ID must be non-empty")
-	}
-
-	if len(user.Email) <= 0 {
-		return fmt.Errorf("validateUser: email must be non-empty")
-	}
+	v := usecases.Validator{}
+	v.Required("/id", user.ID)
 
-	if user.FirstName.Valid && len(user.FirstName.String) <= 0 {
-		return fmt.Errorf("validateUser: first name must be non-empty")
+	// NOTE: emails are also stored as usernames, which are shorter.
+	if v.Required("/email", user.Email) && v.MaxLength("/email", user.Email, 32) {
+		v.Match("/email", strings.ToLower(user.Email), validEmail)
 	}
 
-	if user.LastName.Valid && len(user.LastName.String) <= 0 {
-		return fmt.Errorf("validateUser: last name must be non-empty")
-	}
-
-	if user.Phone.Valid && len(user.Phone.String) <= 0 {
-		return fmt.Errorf("validateUser: phone number must be non-empty")
-	}
+	validateNullString(&v, "/firstName", user.FirstName, 32, validName)
+	validateNullString(&v, "/lastName", user.LastName, 32, validName)
+	validateNullString(&v, "/phone", user.Phone, 16, validPhone)
+	validateNullString(&v, "/address", user.Address, 32, nil)
 
-	if user.Address.Valid && len(user.Address.String) <= 0 {
-		return fmt.Errorf("validateUser: address must be non-empty")
-	}
+	return v.Err()
+}
 
-	if !validEmail.MatchString(strings.ToLower(user.Email)) {
-		return fmt.Errorf("validateUser: invalid email address format")
+// validateNullString checks that the given value, if not null, is non-empty,
+// at most max characters long and matches the given expression (if any).
+func validateNullString(v *usecases.Validator, pointer string, value models.NullString, max int, re *regexp.Regexp) {
+	if !value.Valid {
+		return
 	}
 
-	if user.FirstName.Valid && !validName.MatchString(strings.ToLower(user.FirstName.String)) {
-		return fmt.Errorf("validateUser: invalid first name format")
+	if v.Required(pointer, value.String) && v.MaxLength(pointer, value.String, max) && re != nil {
+		v.Match(pointer, strings.ToLower(value.String), re)
 	}
+}
 
-	if user.LastName.Valid && !validName.MatchString(strings.ToLower(user.LastName.String)) {
-		return fmt.Errorf("validateUser: invalid last name format")
-	}
+func validatePassword(pointer, password string) error {
+	v := usecases.Validator{}
+	v.Check(len(password) >= minPasswordLength, pointer, usecases.CodeTooShort, "%s must be at least %d characters long", pointer[1:], minPasswordLength)
+	v.Check(len(password) <= maxPasswordLength, pointer, usecases.CodeTooLong, "%s must be at most %d characters long", pointer[1:], maxPasswordLength)
 
-	if user.Phone.Valid && !validPhone.MatchString(strings.ToLower(user.Phone.String)) {
-		return fmt.Errorf("validateUser: invalid phone number format")
-	}
+	hasLetter := strings.IndexFunc(password, unicode.IsLetter) >= 0
+	hasDigit := strings.IndexFunc(password, unicode.IsDigit) >= 0
+	v.Check(hasLetter && hasDigit, pointer, usecases.CodeInvalid, "%s must contain both letters and digits", pointer[1:])
 
-	return nil
+	return v.Err()
 }
@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"context"
+	"time"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// LoginAttemptUsecase is the usecase for recording and reviewing log in
+// attempts.
+type LoginAttemptUsecase interface {
+	FetchSince(ctx context.Context, since time.Time) ([]*models.LoginAttempt, error)
+	FetchForEmailSince(ctx context.Context, email string, since time.Time) ([]*models.LoginAttempt, error)
+	Store(ctx context.Context, attempt *models.LoginAttempt) error
+}
@@ -9,8 +9,7 @@ import (
 // MaintenanceUsecase is the usecase for interacting with maintenance jobs.
 type MaintenanceUsecase interface {
 	GetByID(context.Context, string) (*models.Maintenance, error)
-	Fetch(context.Context) ([]*models.Maintenance, error)
-	FetchForRide(context.Context, string) ([]*models.Maintenance, error)
+	Fetch(context.Context, *models.MaintenanceFilter, *models.PageRequest) ([]*models.Maintenance, string, error)
 	Begin(context.Context, *models.Maintenance) error
 	Update(context.Context, *models.Maintenance) error
 	Close(context.Context, string) (*models.Maintenance, error)
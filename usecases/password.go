@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// PasswordUsecase is the usecase for changing and resetting passwords.
+type PasswordUsecase interface {
+	Change(ctx context.Context, userID, currentPassword, newPassword string) (*models.User, error)
+	RequestReset(ctx context.Context, email string) error
+	Reset(ctx context.Context, token, newPassword string) (*models.User, error)
+}
@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// ErrForbidden is returned by usecases when the principal in the context is
+// not allowed to do the requested operation.
+var ErrForbidden = Errorf(KindForbidden, "you are not allowed to do this")
+
+// principalContextKey is the key used for storing the Principal in a
+// context.Context.
+type principalContextKey struct{}
+
+// Principal represents the authenticated caller of an usecase, either an user
+// or a device.
+type Principal struct {
+	User   *models.User
+	Device *models.Device
+	Role   string
+}
+
+// NewPrincipal returns a new Principal instance for the given user. The role
+// is the most privileged role of the user (see models.Role*).
+func NewPrincipal(user *models.User) *Principal {
+	role := models.RoleCustomer
+	for _, r := range []string{models.RoleSupervisor, models.RoleEmployee} {
+		if user.HasRole(r) {
+			role = r
+			break
+		}
+	}
+
+	return &Principal{User: user, Role: role}
+}
+
+// NewDevicePrincipal returns a new Principal instance for the given device.
+func NewDevicePrincipal(device *models.Device) *Principal {
+	return &Principal{Device: device, Role: models.RoleDevice}
+}
+
+// ID returns the ID of the user or device, or an empty string if none.
+func (p *Principal) ID() string {
+	switch {
+	case p == nil:
+		return ""
+	case p.User != nil:
+		return p.User.ID
+	case p.Device != nil:
+		return p.Device.ID
+	}
+	return ""
+}
+
+// HasRole returns true if the principal has at least one of the given roles.
+func (p *Principal) HasRole(roles ...string) bool {
+	if p == nil || p.User == nil {
+		return false
+	}
+
+	for _, role := range roles {
+		if p.User.HasRole(role) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allows returns true if the principal is a device allowed to do the given
+// operation on the given ride (see models.Device*).
+func (p *Principal) Allows(operation, rideID string) bool {
+	return p != nil && p.Device != nil && p.Device.IsActive() && p.Device.Allows(operation, rideID)
+}
+
+// IsUser returns true if the principal is the user with the given ID.
+func (p *Principal) IsUser(userID string) bool {
+	return p != nil && p.User != nil && p.User.ID == userID
+}
+
+// ContextWithPrincipal returns a copy of the given context holding the given
+// principal.
+func ContextWithPrincipal(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+// PrincipalFromContext returns the principal stored in the given context.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(*Principal)
+	return principal, ok
+}
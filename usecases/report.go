@@ -0,0 +1,13 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/reports"
+)
+
+// ReportUsecase is the usecase for running reports.
+type ReportUsecase interface {
+	Fetch(ctx context.Context) []*reports.Report
+	Run(ctx context.Context, name string, dateRange reports.Range) (interface{}, error)
+}
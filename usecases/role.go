@@ -0,0 +1,14 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// RoleUsecase is the usecase for interacting with employee roles and their
+// policies.
+type RoleUsecase interface {
+	Fetch(ctx context.Context) ([]*models.Role, error)
+	UpdateRequiresTwoFactor(ctx context.Context, ID string, requiresTwoFactor bool) (*models.Role, error)
+}
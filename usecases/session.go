@@ -0,0 +1,16 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// SessionUsecase is the usecase for interacting with log in sessions.
+type SessionUsecase interface {
+	GetByID(ctx context.Context, ID string) (*models.Session, error)
+	FetchActiveForUser(ctx context.Context, userID string) ([]*models.Session, error)
+	Store(ctx context.Context, session *models.Session) error
+	Revoke(ctx context.Context, ID string) error
+	RevokeForUser(ctx context.Context, userID string) error
+}
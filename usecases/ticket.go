@@ -10,12 +10,8 @@ import (
 type TicketUsecase interface {
 	GetByID(ctx context.Context, ID string) (*models.Ticket, error)
 
-	Fetch(ctx context.Context) ([]*models.Ticket, error)
-	FetchForUser(ctx context.Context, userID string) ([]*models.Ticket, error)
-
-	FetchScans(ctx context.Context) ([]*models.TicketScan, error)
-	FetchScansForUser(ctx context.Context, userID string) ([]*models.TicketScan, error)
-	FetchScansForRide(ctx context.Context, rideID string) ([]*models.TicketScan, error)
+	Fetch(ctx context.Context, filter *models.TicketFilter, page *models.PageRequest) ([]*models.Ticket, string, error)
+	FetchScans(ctx context.Context, filter *models.TicketScanFilter, page *models.PageRequest) ([]*models.TicketScan, string, error)
 
 	Store(ctx context.Context, ticket *models.Ticket) error
 	Update(ctx context.Context, ticket *models.Ticket) error
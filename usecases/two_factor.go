@@ -0,0 +1,17 @@
+package usecases
+
+import (
+	"context"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/models"
+)
+
+// TwoFactorUsecase is the usecase for interacting with the TOTP two-factor
+// authentication of users. Codes are either TOTP codes or recovery codes.
+type TwoFactorUsecase interface {
+	Enroll(ctx context.Context, userID string) (*models.TwoFactorEnrollment, error)
+	Enable(ctx context.Context, userID, code string) (recoveryCodes []string, err error)
+	Disable(ctx context.Context, userID, code string) error
+	RegenerateRecoveryCodes(ctx context.Context, userID, code string) ([]string, error)
+	Verify(ctx context.Context, userID, code string) error
+}
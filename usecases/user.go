@@ -10,9 +10,8 @@ import (
 type UserUsecase interface {
 	GetByID(context.Context, string) (*models.User, error)
 	GetByEmail(context.Context, string) (*models.User, error)
-	Fetch(context.Context) ([]*models.User, error)
-	FetchCustomers(context.Context) ([]*models.User, error)
-	FetchEmployees(context.Context) ([]*models.User, error)
-	Store(context.Context, *models.User) error
+	Fetch(context.Context, *models.UserFilter, *models.PageRequest) ([]*models.User, string, error)
+	Store(ctx context.Context, user *models.User, password string) error
+	Register(ctx context.Context, user *models.User, password string) error
 	Update(context.Context, *models.User) error
 }
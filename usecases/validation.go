@@ -0,0 +1,97 @@
+package usecases
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field error codes, which clients can rely on.
+const (
+	CodeRequired   = "required"
+	CodeTooShort   = "too_short"
+	CodeTooLong    = "too_long"
+	CodeOutOfRange = "out_of_range"
+	CodeInvalid    = "invalid"
+	CodeNotFound   = "not_found"
+)
+
+// FieldError is a problem with a single field, given as a JSON pointer into
+// the request body (e.g. "/scopes/0/operation").
+type FieldError struct {
+	Pointer string `json:"pointer"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Validator collects field errors, so that every problem is reported at once
+// instead of only the first one. The zero value is ready to use.
+type Validator struct {
+	fields []FieldError
+}
+
+// Add adds an error for the field at the given pointer, with the message
+// formatted like fmt.Sprintf.
+func (v *Validator) Add(pointer, code, format string, args ...interface{}) {
+	v.fields = append(v.fields, FieldError{pointer, code, fmt.Sprintf(format, args...)})
+}
+
+// Check adds an error for the field at the given pointer if ok is false, and
+// returns ok.
+func (v *Validator) Check(ok bool, pointer, code, format string, args ...interface{}) bool {
+	if !ok {
+		v.Add(pointer, code, format, args...)
+	}
+	return ok
+}
+
+// Required checks that the given value is non-empty.
+func (v *Validator) Required(pointer, value string) bool {
+	return v.Check(len(value) > 0, pointer, CodeRequired, "%s must be non-empty", fieldName(pointer))
+}
+
+// MaxLength checks that the given value is at most max characters long, which
+// should match the length of its column.
+func (v *Validator) MaxLength(pointer, value string, max int) bool {
+	return v.Check(utf8.RuneCountInString(value) <= max, pointer, CodeTooLong, "%s must be at most %d characters long", fieldName(pointer), max)
+}
+
+// Range checks that the given value is in the range [min, max].
+func (v *Validator) Range(pointer string, value, min, max float64) bool {
+	return v.Check(value >= min && value <= max, pointer, CodeOutOfRange, "%s must be in the range [%v, %v]", fieldName(pointer), min, max)
+}
+
+// Match checks that the given value matches the given expression.
+func (v *Validator) Match(pointer, value string, re *regexp.Regexp) bool {
+	return v.Check(re.MatchString(value), pointer, CodeInvalid, "%s has an invalid format", fieldName(pointer))
+}
+
+// Valid returns true if no errors were added.
+func (v *Validator) Valid() bool {
+	return len(v.fields) <= 0
+}
+
+// Err returns a validation error with every field error that was added, or
+// nil if none were.
+func (v *Validator) Err() error {
+	if v.Valid() {
+		return nil
+	}
+
+	messages := make([]string, len(v.fields))
+	for i, field := range v.fields {
+		messages[i] = field.Message
+	}
+
+	return &Error{
+		Kind:    KindValidation,
+		Message: fmt.Sprintf("invalid fields: %s", strings.Join(messages, "; ")),
+		Fields:  v.fields,
+	}
+}
+
+// fieldName returns the last part of the given pointer, used in messages.
+func fieldName(pointer string) string {
+	return pointer[strings.LastIndex(pointer, "/")+1:]
+}
@@ -0,0 +1,38 @@
+package usecases_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"gitlab.com/uh-spring-2020/cosc-3380-team-14/backend/usecases"
+)
+
+func TestValidatorCollectsEveryError(t *testing.T) {
+	v := usecases.Validator{}
+	assert.False(t, v.Required("/title", ""))
+	assert.False(t, v.MaxLength("/address", "ünïcödé", 6))
+	assert.True(t, v.MaxLength("/address", "ünïcödé", 7))
+	assert.False(t, v.Range("/rating", 6, 1, 5))
+	assert.False(t, v.Match("/phone", "555-0100", regexp.MustCompile(`^[0-9]+$`)))
+
+	err := v.Err()
+	assert.True(t, usecases.IsKind(err, usecases.KindValidation))
+
+	domainErr := err.(*usecases.Error)
+	codes := []string{}
+	for _, field := range domainErr.Fields {
+		codes = append(codes, field.Code)
+	}
+	assert.Equal(t, []string{usecases.CodeRequired, usecases.CodeTooLong, usecases.CodeOutOfRange, usecases.CodeInvalid}, codes)
+	assert.Equal(t, "/rating", domainErr.Fields[2].Pointer)
+	assert.Equal(t, "rating must be in the range [1, 5]", domainErr.Fields[2].Message)
+}
+
+func TestValidatorWithoutErrors(t *testing.T) {
+	v := usecases.Validator{}
+	assert.True(t, v.Required("/title", "title"))
+	assert.True(t, v.Valid())
+	assert.Nil(t, v.Err())
+}